@@ -0,0 +1,33 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// RegistrationFinalizer is set on resources registered in a llama-stack server, so they are
+	// unregistered before being deleted.
+	RegistrationFinalizer = "llamastack.io/registration"
+
+	// ConditionTypeReady indicates whether a resource registered in a llama-stack server is ready.
+	ConditionTypeReady = "Ready"
+)
+
+// DistributionReference references a LlamaStackDistribution in the same namespace.
+type DistributionReference struct {
+	// Name of the LlamaStackDistribution
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
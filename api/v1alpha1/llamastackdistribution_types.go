@@ -22,6 +22,7 @@ package v1alpha1
 //nolint:gci
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,6 +43,18 @@ const (
 	DefaultMountPath = "/.llama"
 	// LlamaStackDistributionKind is the kind name for LlamaStackDistribution resources
 	LlamaStackDistributionKind = "LlamaStackDistribution"
+	// ReconcileAnnotation controls whether the operator reconciles a distribution
+	ReconcileAnnotation = "llamastack.io/reconcile"
+	// ReconcilePausedValue is the ReconcileAnnotation value that pauses reconciliation
+	ReconcilePausedValue = "paused"
+	// PruneAnnotation controls whether the operator deletes a resource it no longer renders
+	PruneAnnotation = "llamastack.io/prune"
+	// PruneKeepValue is the PruneAnnotation value that keeps the resource
+	PruneKeepValue = "keep"
+	// AdoptAnnotation opts an existing resource in to being adopted by the distribution rendering it
+	AdoptAnnotation = "llamastack.io/adopt"
+	// AdoptValue is the AdoptAnnotation value that allows the adoption
+	AdoptValue = "true"
 )
 
 // DefaultStorageSize is the default size for persistent storage
@@ -62,13 +75,228 @@ type DistributionType struct {
 type ProviderHealthStatus struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
+	// LastTransitionTime is when the operator observed the provider changing to its current status.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 // LlamaStackDistributionSpec defines the desired state of LlamaStackDistribution.
+// +kubebuilder:validation:XValidation:rule="!has(self.rolloutStrategy) || !has(self.rolloutStrategy.type) || self.rolloutStrategy.type == 'RollingUpdate' || !has(self.server.storage)",message="Canary and BlueGreen rollouts cannot be used with server.storage, the preview pods would mount the same volume"
 type LlamaStackDistributionSpec struct {
 	// +kubebuilder:default:=1
 	Replicas int32      `json:"replicas,omitempty"`
 	Server   ServerSpec `json:"server"`
+	// Suspend scales the server down to zero replicas while keeping its storage,
+	// Service and configuration, so it can be resumed later.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// Schedule overrides Replicas at the times given by its entries. The entry that fired
+	// most recently is active until the next entry fires. Suspend takes precedence.
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Schedule []ScheduleEntry `json:"schedule,omitempty"`
+	// RolloutStrategy defines how image or configuration changes are rolled out to the server.
+	// Defaults to a regular Deployment rolling update when unset. Canary and BlueGreen rollouts
+	// cannot be used with server.storage.
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+	// Monitoring configures Prometheus Operator resources for the server.
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+	// Network configures the NetworkPolicy of the server pods.
+	// +optional
+	Network *NetworkSpec `json:"network,omitempty"`
+	// DriftPolicy controls how changes made to the managed resources outside the operator are
+	// handled. Report only reports them in the Drifted condition and Events, Correct also reverts
+	// them. Changes to the spec are applied with either policy.
+	// +kubebuilder:default:=Correct
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// AdoptionPolicy controls which existing resources not owned by the distribution are adopted
+	// instead of skipped. Annotated only adopts the resources annotated llamastack.io/adopt: "true",
+	// Unowned adopts every resource without a controller. Resources controlled by another owner are
+	// never adopted.
+	// +kubebuilder:default:=Annotated
+	// +optional
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+	// Overrides customize the rendered resources beyond the settings of the spec.
+	// +optional
+	Overrides *OverridesSpec `json:"overrides,omitempty"`
+}
+
+// OverridesSpec customizes the resources rendered for a distribution.
+type OverridesSpec struct {
+	// Patches are applied in order to the rendered resources. They must not change the owner
+	// references, selectors or managed-by label of the resources.
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=atomic
+	// +optional
+	Patches []ManifestPatch `json:"patches,omitempty"`
+}
+
+// ManifestPatchType is the format of a ManifestPatch.
+// +kubebuilder:validation:Enum=StrategicMerge;JSON6902
+type ManifestPatchType string
+
+const (
+	// ManifestPatchStrategicMerge is a strategic merge patch, as used by kubectl patch.
+	ManifestPatchStrategicMerge ManifestPatchType = "StrategicMerge"
+	// ManifestPatchJSON6902 is a list of JSON patch operations, as defined by RFC 6902.
+	ManifestPatchJSON6902 ManifestPatchType = "JSON6902"
+)
+
+// ManifestPatch patches the rendered resources matching its target.
+type ManifestPatch struct {
+	// Target selects the rendered resources to patch.
+	Target PatchTarget `json:"target"`
+	// Type is the format of the patch.
+	// +kubebuilder:default:=StrategicMerge
+	// +optional
+	Type ManifestPatchType `json:"type,omitempty"`
+	// Patch is the YAML or JSON patch. The apiVersion, kind and metadata.name of a strategic merge
+	// patch may be omitted, they are taken from the target resource.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=16384
+	Patch string `json:"patch"`
+}
+
+// PatchTarget selects rendered resources by kind and name.
+type PatchTarget struct {
+	// Kind of the rendered resources, e.g. Deployment.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+	// Name of the rendered resource. Every resource of the kind is patched when empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// AdoptionPolicy is which existing resources not owned by the distribution are adopted.
+// +kubebuilder:validation:Enum=Annotated;Unowned
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyAnnotated adopts the resources annotated for adoption.
+	AdoptionPolicyAnnotated AdoptionPolicy = "Annotated"
+	// AdoptionPolicyUnowned adopts every resource without a controller.
+	AdoptionPolicyUnowned AdoptionPolicy = "Unowned"
+)
+
+// DriftPolicy is how changes made to the managed resources outside the operator are handled.
+// +kubebuilder:validation:Enum=Report;Correct
+type DriftPolicy string
+
+const (
+	// DriftPolicyReport reports the drifted resources and leaves them as they are.
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyCorrect reports the drifted resources and applies the desired state again.
+	DriftPolicyCorrect DriftPolicy = "Correct"
+)
+
+// NetworkSpec configures the NetworkPolicy rendered for a distribution.
+// +kubebuilder:validation:XValidation:rule="!has(self.enabled) || self.enabled || (!has(self.ingress) && !has(self.egress))",message="ingress and egress rules require the NetworkPolicy to be enabled"
+type NetworkSpec struct {
+	// Enabled creates the NetworkPolicy regardless of the enableNetworkPolicy feature flag of the
+	// operator when true, and never creates it when false. Defaults to the feature flag, unless
+	// ingress or egress rules are set.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Ingress rules are added to the default rules, which allow the pods labelled
+	// app.kubernetes.io/part-of: llama-stack and the operator namespace.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	Ingress []networkingv1.NetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress rules restrict the traffic leaving the server pods. DNS is always allowed.
+	// Egress is not restricted when empty.
+	// +kubebuilder:validation:MaxItems=32
+	// +optional
+	Egress []networkingv1.NetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// MonitoringSpec configures the ServiceMonitor and PrometheusRule rendered for a distribution.
+// They are only created when the monitoring.coreos.com CRDs are installed in the cluster.
+type MonitoringSpec struct {
+	// Enabled renders a ServiceMonitor scraping the server and a PrometheusRule with default alerts.
+	Enabled bool `json:"enabled"`
+	// Interval is the scrape interval of the ServiceMonitor. Defaults to 30s.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// Path is the HTTP path of the server metrics endpoint. Defaults to /metrics.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ScheduleEntry sets the number of replicas from the time its cron expression fires.
+type ScheduleEntry struct {
+	// Name identifies the entry in status. Defaults to the cron expression.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Cron is a standard five-field cron expression (minute hour day-of-month month day-of-week)
+	// at which this entry becomes active, e.g. "0 8 * * 1-5".
+	// +kubebuilder:validation:MinLength=9
+	Cron string `json:"cron"`
+	// Timezone is the IANA time zone in which Cron is evaluated. Defaults to UTC.
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// Replicas is the number of replicas while this entry is active.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+}
+
+// RolloutStrategyType is the type of rollout used for server changes.
+// +kubebuilder:validation:Enum=RollingUpdate;Canary;BlueGreen
+type RolloutStrategyType string
+
+const (
+	// RolloutStrategyRollingUpdate applies changes through the Deployment's own rolling update.
+	RolloutStrategyRollingUpdate RolloutStrategyType = "RollingUpdate"
+	// RolloutStrategyCanary shifts replicas to the new revision in weighted steps.
+	RolloutStrategyCanary RolloutStrategyType = "Canary"
+	// RolloutStrategyBlueGreen brings up the new revision in full before switching the Service to it.
+	RolloutStrategyBlueGreen RolloutStrategyType = "BlueGreen"
+)
+
+// RolloutStrategy defines the rollout strategy for the llama-stack server.
+// +kubebuilder:validation:XValidation:rule="self.type != 'Canary' || has(self.canary)",message="canary must be set when type is Canary"
+type RolloutStrategy struct {
+	// Type is the rollout strategy type.
+	// +kubebuilder:default:=RollingUpdate
+	// +optional
+	Type RolloutStrategyType `json:"type,omitempty"`
+	// Canary configures the steps of a Canary rollout.
+	// +optional
+	Canary *CanaryStrategy `json:"canary,omitempty"`
+	// BlueGreen configures a BlueGreen rollout.
+	// +optional
+	BlueGreen *BlueGreenStrategy `json:"blueGreen,omitempty"`
+}
+
+// CanaryStrategy defines the steps of a Canary rollout.
+type CanaryStrategy struct {
+	// Steps are applied in order. Each step is gated on the health and provider checks
+	// of the new revision before the next one starts.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=10
+	Steps []CanaryStep `json:"steps"`
+}
+
+// CanaryStep defines a single weighted step of a Canary rollout.
+type CanaryStep struct {
+	// Weight is the percentage of replicas that run the new revision during this step.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+	// Pause is how long the step is held after its checks pass before moving on.
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+}
+
+// BlueGreenStrategy defines the behavior of a BlueGreen rollout.
+type BlueGreenStrategy struct {
+	// PromotionDelay is how long the new revision must stay healthy before the Service is switched to it.
+	// +optional
+	PromotionDelay *metav1.Duration `json:"promotionDelay,omitempty"`
 }
 
 // ServerSpec defines the desired state of llama server.
@@ -85,6 +313,42 @@ type ServerSpec struct {
 	// TLSConfig defines the TLS configuration for the llama-stack server
 	// +optional
 	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+	// Telemetry configures OpenTelemetry export of the server traces and metrics
+	// +optional
+	Telemetry *TelemetrySpec `json:"telemetry,omitempty"`
+}
+
+// OTLPProtocol is the transport used to export OpenTelemetry data.
+// +kubebuilder:validation:Enum=http/protobuf;grpc
+type OTLPProtocol string
+
+const (
+	// OTLPProtocolHTTPProtobuf exports over HTTP with protobuf payloads.
+	OTLPProtocolHTTPProtobuf OTLPProtocol = "http/protobuf"
+	// OTLPProtocolGRPC exports over gRPC.
+	OTLPProtocolGRPC OTLPProtocol = "grpc"
+)
+
+// TelemetrySpec configures OpenTelemetry export to an OTLP collector.
+type TelemetrySpec struct {
+	// Endpoint is the base URL of the OTLP collector, e.g. http://otel-collector.observability:4318
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+	// Protocol is the OTLP transport. Defaults to http/protobuf.
+	// +kubebuilder:default:="http/protobuf"
+	// +optional
+	Protocol OTLPProtocol `json:"protocol,omitempty"`
+	// ServiceName is reported as the service.name resource attribute. Defaults to the name of the distribution.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// SamplingRatio is the fraction of traces sampled, between "0" and "1". Defaults to "1".
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	SamplingRatio string `json:"samplingRatio,omitempty"`
+	// HeadersSecretRef selects a Secret key holding the export headers as comma separated
+	// key=value pairs, e.g. "authorization=Bearer <token>"
+	// +optional
+	HeadersSecretRef *corev1.SecretKeySelector `json:"headersSecretRef,omitempty"`
 }
 
 type UserConfigSpec struct {
@@ -146,6 +410,18 @@ type PodOverrides struct {
 	ServiceAccountName string               `json:"serviceAccountName,omitempty"`
 	Volumes            []corev1.Volume      `json:"volumes,omitempty"`
 	VolumeMounts       []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// NodeSelector constrains the server pods to nodes with these labels
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the server pods
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity scheduling rules of the server pods
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Labels are added to the server pods. The labels set by the operator take precedence.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // ProviderInfo represents a single provider from the providers endpoint.
@@ -164,10 +440,35 @@ type DistributionConfig struct {
 	Providers          []ProviderInfo `json:"providers,omitempty"`
 	// AvailableDistributions lists all available distributions and their images
 	AvailableDistributions map[string]string `json:"availableDistributions,omitempty"`
+	// Models summarizes the models registered in the server
+	// +optional
+	Models *RegisteredResources `json:"models,omitempty"`
+	// Shields summarizes the shields registered in the server
+	// +optional
+	Shields *RegisteredResources `json:"shields,omitempty"`
+	// VectorDBs summarizes the vector databases registered in the server
+	// +optional
+	VectorDBs *RegisteredResources `json:"vectorDBs,omitempty"`
+}
+
+// MaxRegisteredIdentifiers caps the identifiers listed in a RegisteredResources summary.
+const MaxRegisteredIdentifiers = 20
+
+// RegisteredResources summarizes the resources of a kind registered in the server.
+type RegisteredResources struct {
+	// Count is the number of registered resources
+	Count int `json:"count"`
+	// Identifiers lists the registered resources in alphabetical order, up to 20 entries
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	Identifiers []string `json:"identifiers,omitempty"`
+	// Truncated is set when not all identifiers are listed
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // LlamaStackDistributionPhase represents the current phase of the LlamaStackDistribution
-// +kubebuilder:validation:Enum=Pending;Initializing;Ready;Failed;Terminating
+// +kubebuilder:validation:Enum=Pending;Initializing;Ready;Failed;Terminating;Suspended;Paused
 type DistributionPhase string
 
 const (
@@ -181,6 +482,10 @@ const (
 	LlamaStackDistributionPhaseFailed DistributionPhase = "Failed"
 	// LlamaStackDistributionPhaseTerminating indicates that the distribution is being terminated
 	LlamaStackDistributionPhaseTerminating DistributionPhase = "Terminating"
+	// LlamaStackDistributionPhaseSuspended indicates that the distribution is scaled down by spec.suspend
+	LlamaStackDistributionPhaseSuspended DistributionPhase = "Suspended"
+	// LlamaStackDistributionPhasePaused indicates that reconciliation is paused by the reconcile annotation
+	LlamaStackDistributionPhasePaused DistributionPhase = "Paused"
 )
 
 // VersionInfo contains version-related information
@@ -207,6 +512,134 @@ type LlamaStackDistributionStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
 	// ServiceURL is the internal Kubernetes service URL where the distribution is exposed
 	ServiceURL string `json:"serviceURL,omitempty"`
+	// Rollout reports the progress of a Canary or BlueGreen rollout
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// Schedule reports the schedule entry currently overriding the replicas
+	// +optional
+	Schedule *ScheduleStatus `json:"schedule,omitempty"`
+	// History records the outcome of the most recent spec generations, oldest first
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	History []GenerationRecord `json:"history,omitempty"`
+	// Defaults reports the LlamaStackDistributionDefaults merged under the spec
+	// +optional
+	Defaults *DefaultsStatus `json:"defaults,omitempty"`
+	// Inventory lists the resources applied by the last successful reconciliation. The resources
+	// missing from the next render are pruned.
+	// +optional
+	// +kubebuilder:validation:MaxItems=64
+	// +listType=atomic
+	Inventory []InventoryEntry `json:"inventory,omitempty"`
+}
+
+// InventoryEntry identifies a resource applied in the namespace of the distribution.
+type InventoryEntry struct {
+	// APIVersion of the resource
+	APIVersion string `json:"apiVersion"`
+	// Kind of the resource
+	Kind string `json:"kind"`
+	// Name of the resource
+	Name string `json:"name"`
+}
+
+// DefaultsStatus reports the namespace defaults merged under the spec of a distribution.
+type DefaultsStatus struct {
+	// Name is the name of the LlamaStackDistributionDefaults
+	Name string `json:"name"`
+	// ObservedGeneration is the generation of the defaults that was merged
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// EffectiveSpec is the spec rendered by the operator, after merging the defaults
+	// +optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	EffectiveSpec *apiextensionsv1.JSON `json:"effectiveSpec,omitempty"`
+}
+
+// GenerationOutcome is the result of reconciling a spec generation.
+// +kubebuilder:validation:Enum=Applied;Failed
+type GenerationOutcome string
+
+const (
+	// GenerationOutcomeApplied means the generation was reconciled successfully.
+	GenerationOutcomeApplied GenerationOutcome = "Applied"
+	// GenerationOutcomeFailed means the last attempt to reconcile the generation failed.
+	GenerationOutcomeFailed GenerationOutcome = "Failed"
+)
+
+// GenerationRecord is an entry of the spec change history.
+type GenerationRecord struct {
+	// Generation is the metadata.generation that was reconciled
+	Generation int64 `json:"generation"`
+	// Outcome is the result of the latest reconciliation of this generation
+	Outcome GenerationOutcome `json:"outcome"`
+	// Message describes the failure when the outcome is Failed
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Time is when the outcome was last observed
+	Time metav1.Time `json:"time"`
+	// ChangedPaths lists the spec fields changed since the previous generation, without their values.
+	// Changes made while the operator was not running are not listed.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=atomic
+	ChangedPaths []string `json:"changedPaths,omitempty"`
+	// OmittedChangedPaths is the number of changed spec fields left out of ChangedPaths
+	// +optional
+	OmittedChangedPaths int32 `json:"omittedChangedPaths,omitempty"`
+}
+
+// ScheduleStatus reports the evaluation of spec.schedule.
+type ScheduleStatus struct {
+	// ActiveEntry is the name of the schedule entry currently in effect, empty if none has fired yet
+	// +optional
+	ActiveEntry string `json:"activeEntry,omitempty"`
+	// Replicas is the number of replicas set by the active entry
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// ActiveSince is when the active entry last fired
+	// +optional
+	ActiveSince *metav1.Time `json:"activeSince,omitempty"`
+	// NextTransition is when the next schedule entry fires
+	// +optional
+	NextTransition *metav1.Time `json:"nextTransition,omitempty"`
+	// Message reports schedule entries that could not be evaluated
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RolloutPhase is the phase of a progressive rollout.
+// +kubebuilder:validation:Enum=Idle;Progressing;Promoting
+type RolloutPhase string
+
+const (
+	// RolloutPhaseIdle indicates that the stable revision matches the desired state.
+	RolloutPhaseIdle RolloutPhase = "Idle"
+	// RolloutPhaseProgressing indicates that the new revision is running next to the stable one.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhasePromoting indicates that the new revision is being promoted to stable.
+	RolloutPhasePromoting RolloutPhase = "Promoting"
+)
+
+// RolloutStatus reports the progress of a Canary or BlueGreen rollout.
+type RolloutStatus struct {
+	// Strategy is the rollout strategy in use
+	Strategy RolloutStrategyType `json:"strategy,omitempty"`
+	// Phase is the current rollout phase
+	Phase RolloutPhase `json:"phase,omitempty"`
+	// StableRevision is the pod template revision currently serving as stable
+	StableRevision string `json:"stableRevision,omitempty"`
+	// UpdateRevision is the pod template revision being rolled out
+	UpdateRevision string `json:"updateRevision,omitempty"`
+	// CurrentStep is the index of the current step
+	CurrentStep int32 `json:"currentStep,omitempty"`
+	// CurrentWeight is the percentage of replicas running the update revision
+	CurrentWeight int32 `json:"currentWeight,omitempty"`
+	// StepHealthyTime is when the checks of the current step first passed
+	// +optional
+	StepHealthyTime *metav1.Time `json:"stepHealthyTime,omitempty"`
+	// Message is a human readable description of the rollout progress
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -216,6 +649,10 @@ type LlamaStackDistributionStatus struct {
 //+kubebuilder:printcolumn:name="Operator Version",type="string",JSONPath=".status.version.operatorVersion"
 //+kubebuilder:printcolumn:name="Server Version",type="string",JSONPath=".status.version.llamaStackServerVersion"
 //+kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableReplicas"
+//+kubebuilder:printcolumn:name="Models",type="integer",JSONPath=".status.distributionConfig.models.count",priority=1
+//+kubebuilder:printcolumn:name="Model IDs",type="string",JSONPath=".status.distributionConfig.models.identifiers",priority=1
+//+kubebuilder:printcolumn:name="Shields",type="integer",JSONPath=".status.distributionConfig.shields.count",priority=1
+//+kubebuilder:printcolumn:name="Vector DBs",type="integer",JSONPath=".status.distributionConfig.vectorDBs.count",priority=1
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 //+kubebuilder:selectablefield:JSONPath=".spec.server.userConfig.configMapName"
 //+kubebuilder:selectablefield:JSONPath=".spec.server.userConfig.configMapNamespace"
@@ -244,6 +681,30 @@ func init() { //nolint:gochecknoinits
 	SchemeBuilder.Register(&LlamaStackDistribution{}, &LlamaStackDistributionList{})
 }
 
+// IsMonitoringEnabled checks if Prometheus Operator resources are requested for the distribution.
+func (r *LlamaStackDistribution) IsMonitoringEnabled() bool {
+	return r.Spec.Monitoring != nil && r.Spec.Monitoring.Enabled
+}
+
+// IsNetworkPolicyEnabled checks if the NetworkPolicy is rendered for the distribution. Setting
+// ingress or egress rules opts in, otherwise defaultEnabled applies.
+func (r *LlamaStackDistribution) IsNetworkPolicyEnabled(defaultEnabled bool) bool {
+	network := r.Spec.Network
+	switch {
+	case network == nil:
+		return defaultEnabled
+	case network.Enabled != nil:
+		return *network.Enabled
+	default:
+		return defaultEnabled || len(network.Ingress) > 0 || len(network.Egress) > 0
+	}
+}
+
+// IsReconcilePaused checks if reconciliation is paused through the reconcile annotation.
+func (r *LlamaStackDistribution) IsReconcilePaused() bool {
+	return r.Annotations[ReconcileAnnotation] == ReconcilePausedValue
+}
+
 // HasPorts checks if the container spec defines a port.
 func (r *LlamaStackDistribution) HasPorts() bool {
 	return r.Spec.Server.ContainerSpec.Port != 0 || len(r.Spec.Server.ContainerSpec.Env) > 0
@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DistributionDefaultsName is the name of the LlamaStackDistributionDefaults read in each namespace
+const DistributionDefaultsName = "default"
+
+// LlamaStackDistributionDefaultsSpec defines the settings merged under the spec of every
+// LlamaStackDistribution of the namespace. The settings of a distribution take precedence.
+type LlamaStackDistributionDefaultsSpec struct {
+	// Env is added to the server container. Variables the distribution sets with the same name
+	// take precedence. The defaults come first, so the distribution can reference them.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Resources of the server container when the distribution sets neither requests nor limits
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// TLSConfig is used when the distribution sets no CA bundle
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+	// NodeSelector is merged with the node selector of the distribution, whose keys take precedence
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the tolerations of the distribution
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity is used when the distribution sets no affinity
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Labels are merged with the pod labels of the distribution, whose keys take precedence
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=llsdd
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="the LlamaStackDistributionDefaults must be named default"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LlamaStackDistributionDefaults holds the defaults of the LlamaStackDistributions of a namespace.
+// The operator only reads the instance named default. Each distribution reports the defaults it
+// merged in .status.defaults.
+type LlamaStackDistributionDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LlamaStackDistributionDefaultsSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LlamaStackDistributionDefaultsList contains a list of LlamaStackDistributionDefaults.
+type LlamaStackDistributionDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LlamaStackDistributionDefaults `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&LlamaStackDistributionDefaults{}, &LlamaStackDistributionDefaultsList{})
+}
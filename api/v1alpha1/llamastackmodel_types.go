@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ModelType is the kind of model registered in llama-stack.
+// +kubebuilder:validation:Enum=llm;embedding
+type ModelType string
+
+const (
+	// ModelTypeLLM is a model serving completions.
+	ModelTypeLLM ModelType = "llm"
+	// ModelTypeEmbedding is a model computing embeddings.
+	ModelTypeEmbedding ModelType = "embedding"
+)
+
+// LlamaStackModelSpec defines the model to register in a LlamaStackDistribution.
+type LlamaStackModelSpec struct {
+	// DistributionRef references the LlamaStackDistribution serving the model
+	DistributionRef DistributionReference `json:"distributionRef"`
+	// ModelID is the identifier the model is registered as. Defaults to the name of the resource.
+	// +optional
+	ModelID string `json:"modelId,omitempty"`
+	// ProviderID is the inference provider serving the model, as listed in the distribution status
+	// +kubebuilder:validation:MinLength=1
+	ProviderID string `json:"providerId"`
+	// ProviderModelID is the name of the model in the provider. Defaults to the model ID.
+	// +optional
+	ProviderModelID string `json:"providerModelId,omitempty"`
+	// ModelType is the kind of model
+	// +optional
+	// +kubebuilder:default=llm
+	ModelType ModelType `json:"modelType,omitempty"`
+	// Metadata is passed to the server, e.g. embedding_dimension for embedding models
+	// +optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Metadata *apiextensionsv1.JSON `json:"metadata,omitempty"`
+}
+
+// LlamaStackModelStatus defines the observed state of LlamaStackModel.
+type LlamaStackModelStatus struct {
+	// ObservedGeneration is the generation last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// RegisteredModelID is the identifier the model is currently registered as
+	// +optional
+	RegisteredModelID string `json:"registeredModelId,omitempty"`
+	// LastRegistrationTime is when the operator last registered the model, e.g. after a server restart
+	// +optional
+	LastRegistrationTime *metav1.Time `json:"lastRegistrationTime,omitempty"`
+	// Conditions represent the latest available observations of the model's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=llsm
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Distribution",type="string",JSONPath=".spec.distributionRef.name"
+//+kubebuilder:printcolumn:name="Model ID",type="string",JSONPath=".status.registeredModelId"
+//+kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.providerId"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LlamaStackModel registers a model in the server of a LlamaStackDistribution.
+type LlamaStackModel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LlamaStackModelSpec   `json:"spec"`
+	Status LlamaStackModelStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LlamaStackModelList contains a list of LlamaStackModel.
+type LlamaStackModelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LlamaStackModel `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&LlamaStackModel{}, &LlamaStackModelList{})
+}
+
+// GetModelID returns the identifier the model is registered as.
+func (m *LlamaStackModel) GetModelID() string {
+	if m.Spec.ModelID != "" {
+		return m.Spec.ModelID
+	}
+	return m.Name
+}
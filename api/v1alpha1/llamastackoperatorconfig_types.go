@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OperatorConfigName is the name of the singleton LlamaStackOperatorConfig read by the operator
+	OperatorConfigName = "cluster"
+	// MigratedFromAnnotation records the ConfigMap a LlamaStackOperatorConfig was migrated from
+	MigratedFromAnnotation = "llamastack.io/migrated-from"
+	// ConditionTypeApplied indicates whether the running operator applied the latest spec
+	ConditionTypeApplied = "Applied"
+)
+
+// LlamaStackOperatorConfigSpec defines the operator-wide settings.
+type LlamaStackOperatorConfigSpec struct {
+	// FeatureFlags enable optional operator behaviors
+	// +optional
+	FeatureFlags OperatorFeatureFlags `json:"featureFlags,omitempty"`
+	// DefaultDistribution is the distribution name used by LlamaStackDistributions that set neither
+	// a distribution name nor an image. It must be one of the supported distributions.
+	// +optional
+	DefaultDistribution string `json:"defaultDistribution,omitempty"`
+	// ImageMirrors rewrite the registry of the server images, e.g. to pull from a disconnected mirror.
+	// The first rule whose source prefixes the image is applied.
+	// +optional
+	// +kubebuilder:validation:MaxItems=32
+	// +listType=atomic
+	ImageMirrors []ImageMirror `json:"imageMirrors,omitempty"`
+	// DefaultResources are the resources of the server container of LlamaStackDistributions that
+	// set neither requests nor limits
+	// +optional
+	DefaultResources *corev1.ResourceRequirements `json:"defaultResources,omitempty"`
+	// Polling configures how often the operator queries the distribution servers
+	// +optional
+	Polling PollingSpec `json:"polling,omitempty"`
+	// Pruning configures the deletion of the resources a distribution no longer renders
+	// +optional
+	Pruning PruningSpec `json:"pruning,omitempty"`
+}
+
+// PruningSpec configures the deletion of the resources a distribution no longer renders, e.g. the
+// Service once the distribution has no port or the PersistentVolumeClaim once storage is removed.
+type PruningSpec struct {
+	// DryRun publishes an Event for each resource that would be deleted instead of deleting it
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// OperatorFeatureFlags enable optional operator behaviors.
+type OperatorFeatureFlags struct {
+	// EnableNetworkPolicy creates a NetworkPolicy for every LlamaStackDistribution
+	// +optional
+	EnableNetworkPolicy bool `json:"enableNetworkPolicy,omitempty"`
+	// ProviderConfigRedaction controls how the provider configs are exposed in the status
+	// +optional
+	ProviderConfigRedaction ProviderConfigRedactionSpec `json:"providerConfigRedaction,omitempty"`
+}
+
+// ProviderConfigRedactionSpec configures the redaction of the provider configs reported by the
+// server before they are written to .status.distributionConfig.providers.
+type ProviderConfigRedactionSpec struct {
+	// KeyPatterns are case-insensitive regular expressions matched against config field names, at
+	// any depth. The values of matching fields are redacted. Defaults are used when empty.
+	// +optional
+	// +kubebuilder:validation:MaxItems=64
+	// +listType=atomic
+	KeyPatterns []string `json:"keyPatterns,omitempty"`
+	// DropConfig removes the provider configs from the status entirely
+	// +optional
+	DropConfig bool `json:"dropConfig,omitempty"`
+}
+
+// ImageMirror rewrites the images starting with Source to start with Mirror instead.
+type ImageMirror struct {
+	// Source is the registry or repository prefix to replace, e.g. docker.io/llamastack
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[^:/@\s][^@\s]*$`
+	Source string `json:"source"`
+	// Mirror is the prefix used instead, e.g. registry.example.com/llamastack
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^[^:/@\s][^@\s]*$`
+	Mirror string `json:"mirror"`
+}
+
+// PollingSpec configures how often the operator queries the distribution servers.
+type PollingSpec struct {
+	// HealthProbeInterval is how often the providers and version of ready distributions are polled.
+	// Defaults to the --distribution-health-interval flag. It cannot enable the prober when the flag
+	// disabled it.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('5s')",message="healthProbeInterval must be at least 5s"
+	HealthProbeInterval *metav1.Duration `json:"healthProbeInterval,omitempty"`
+	// RegistrationResyncInterval is how often registered models, shields, tool groups and vector
+	// stores are verified, so resources lost by a server restart are registered again. Defaults to 2m.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('10s')",message="registrationResyncInterval must be at least 10s"
+	RegistrationResyncInterval *metav1.Duration `json:"registrationResyncInterval,omitempty"`
+}
+
+// LlamaStackOperatorConfigStatus reports the settings applied by the running operator.
+type LlamaStackOperatorConfigStatus struct {
+	// ObservedGeneration is the generation last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// AppliedGeneration is the generation of the applied spec. It lags behind the observed
+	// generation while the latest spec is invalid.
+	// +optional
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+	// Applied is the spec the running operator applied
+	// +optional
+	Applied *LlamaStackOperatorConfigSpec `json:"applied,omitempty"`
+	// Conditions represent the latest available observations of the config's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=llsoc
+//+kubebuilder:subresource:status
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the LlamaStackOperatorConfig must be named cluster"
+//+kubebuilder:printcolumn:name="Network Policy",type="boolean",JSONPath=".status.applied.featureFlags.enableNetworkPolicy"
+//+kubebuilder:printcolumn:name="Default Distribution",type="string",JSONPath=".status.applied.defaultDistribution"
+//+kubebuilder:printcolumn:name="Applied",type="string",JSONPath=".status.conditions[?(@.type==\"Applied\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LlamaStackOperatorConfig holds the operator-wide settings. The operator only reads the
+// instance named cluster.
+type LlamaStackOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LlamaStackOperatorConfigSpec   `json:"spec,omitempty"`
+	Status LlamaStackOperatorConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LlamaStackOperatorConfigList contains a list of LlamaStackOperatorConfig.
+type LlamaStackOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LlamaStackOperatorConfig `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&LlamaStackOperatorConfig{}, &LlamaStackOperatorConfigList{})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LlamaStackShieldSpec defines the safety shield to register in a LlamaStackDistribution.
+type LlamaStackShieldSpec struct {
+	// DistributionRef references the LlamaStackDistribution the shield is registered in
+	DistributionRef DistributionReference `json:"distributionRef"`
+	// ShieldID is the identifier the shield is registered as. Defaults to the name of the resource.
+	// +optional
+	ShieldID string `json:"shieldId,omitempty"`
+	// ProviderID is the safety provider running the shield, as listed in the distribution status
+	// +kubebuilder:validation:MinLength=1
+	ProviderID string `json:"providerId"`
+	// ProviderShieldID is the name of the shield in the provider, e.g. the Llama Guard model. Defaults to the shield ID.
+	// +optional
+	ProviderShieldID string `json:"providerShieldId,omitempty"`
+	// Params are passed to the provider
+	// +optional
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Params *apiextensionsv1.JSON `json:"params,omitempty"`
+	// Required makes the shield mandatory: the ShieldsEnforced condition of the distribution is
+	// False while the server does not list it.
+	// +optional
+	// +kubebuilder:default=true
+	Required *bool `json:"required,omitempty"`
+}
+
+// LlamaStackShieldStatus defines the observed state of LlamaStackShield.
+type LlamaStackShieldStatus struct {
+	// ObservedGeneration is the generation last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// RegisteredShieldID is the identifier the shield is currently registered as
+	// +optional
+	RegisteredShieldID string `json:"registeredShieldId,omitempty"`
+	// LastRegistrationTime is when the operator last registered the shield, e.g. after a server restart
+	// +optional
+	LastRegistrationTime *metav1.Time `json:"lastRegistrationTime,omitempty"`
+	// Conditions represent the latest available observations of the shield's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=llssh
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Distribution",type="string",JSONPath=".spec.distributionRef.name"
+//+kubebuilder:printcolumn:name="Shield ID",type="string",JSONPath=".status.registeredShieldId"
+//+kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.providerId"
+//+kubebuilder:printcolumn:name="Required",type="boolean",JSONPath=".spec.required"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LlamaStackShield registers a safety shield in the server of a LlamaStackDistribution.
+type LlamaStackShield struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LlamaStackShieldSpec   `json:"spec"`
+	Status LlamaStackShieldStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LlamaStackShieldList contains a list of LlamaStackShield.
+type LlamaStackShieldList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LlamaStackShield `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&LlamaStackShield{}, &LlamaStackShieldList{})
+}
+
+// GetShieldID returns the identifier the shield is registered as.
+func (s *LlamaStackShield) GetShieldID() string {
+	if s.Spec.ShieldID != "" {
+		return s.Spec.ShieldID
+	}
+	return s.Name
+}
+
+// IsRequired returns true if the distribution must have the shield registered. Shields are
+// required unless stated otherwise.
+func (s *LlamaStackShield) IsRequired() bool {
+	return s.Spec.Required == nil || *s.Spec.Required
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultMCPServerPort is the default port of the MCP servers deployed by the operator.
+	DefaultMCPServerPort int32 = 8000
+	// DefaultMCPServerPath is the default path of the MCP endpoint of the deployed servers.
+	DefaultMCPServerPath = "/sse"
+
+	// ConditionTypeAvailable indicates whether the tools of a tool group can be listed by the server.
+	ConditionTypeAvailable = "Available"
+)
+
+// LlamaStackToolGroupSpec defines the MCP tool group to register in a LlamaStackDistribution.
+// +kubebuilder:validation:XValidation:rule="has(self.endpoint) != has(self.server)",message="exactly one of endpoint or server must be set"
+type LlamaStackToolGroupSpec struct {
+	// DistributionRef references the LlamaStackDistribution the tool group is registered in
+	DistributionRef DistributionReference `json:"distributionRef"`
+	// ToolGroupID is the identifier the tool group is registered as. Defaults to mcp::<name of the resource>.
+	// +optional
+	ToolGroupID string `json:"toolGroupId,omitempty"`
+	// ProviderID is the tool runtime provider of the tool group
+	// +optional
+	// +kubebuilder:default=model-context-protocol
+	ProviderID string `json:"providerId,omitempty"`
+	// Endpoint is the URL of an existing MCP server, e.g. http://github-mcp.tools.svc:8000/sse
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Server deploys an MCP server next to the tool group, reachable from the distribution pods
+	// +optional
+	Server *MCPServerSpec `json:"server,omitempty"`
+}
+
+// MCPServerSpec defines the MCP server container deployed by the operator.
+type MCPServerSpec struct {
+	// Image of the MCP server
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// Command overrides the entrypoint of the image
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// Args of the MCP server
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// Env of the MCP server
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Port the MCP server listens on
+	// +optional
+	// +kubebuilder:default=8000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port,omitempty"`
+	// Path of the MCP endpoint, e.g. /sse for the SSE transport or /mcp for streamable HTTP
+	// +optional
+	// +kubebuilder:default=/sse
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path,omitempty"`
+	// Resources of the MCP server container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// LlamaStackToolGroupStatus defines the observed state of LlamaStackToolGroup.
+type LlamaStackToolGroupStatus struct {
+	// ObservedGeneration is the generation last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// RegisteredToolGroupID is the identifier the tool group is currently registered as
+	// +optional
+	RegisteredToolGroupID string `json:"registeredToolGroupId,omitempty"`
+	// Endpoint is the MCP endpoint the tool group is registered with
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Tools summarizes the tools the server lists for the tool group
+	// +optional
+	Tools *RegisteredResources `json:"tools,omitempty"`
+	// Conditions represent the latest available observations of the tool group's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=llstg
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Distribution",type="string",JSONPath=".spec.distributionRef.name"
+//+kubebuilder:printcolumn:name="Tool Group ID",type="string",JSONPath=".status.registeredToolGroupId"
+//+kubebuilder:printcolumn:name="Tools",type="integer",JSONPath=".status.tools.count"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type==\"Available\")].status"
+//+kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".status.endpoint",priority=1
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LlamaStackToolGroup registers an MCP tool group in the server of a LlamaStackDistribution,
+// optionally deploying the MCP server.
+type LlamaStackToolGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LlamaStackToolGroupSpec   `json:"spec"`
+	Status LlamaStackToolGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LlamaStackToolGroupList contains a list of LlamaStackToolGroup.
+type LlamaStackToolGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LlamaStackToolGroup `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&LlamaStackToolGroup{}, &LlamaStackToolGroupList{})
+}
+
+// GetToolGroupID returns the identifier the tool group is registered as.
+func (t *LlamaStackToolGroup) GetToolGroupID() string {
+	if t.Spec.ToolGroupID != "" {
+		return t.Spec.ToolGroupID
+	}
+	return "mcp::" + t.Name
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LlamaStackVectorStoreSpec defines the vector store to create in a LlamaStackDistribution.
+type LlamaStackVectorStoreSpec struct {
+	// DistributionRef references the LlamaStackDistribution hosting the vector store
+	DistributionRef DistributionReference `json:"distributionRef"`
+	// VectorStoreName is the name of the vector store in the server. Defaults to the name of the resource.
+	// +optional
+	VectorStoreName string `json:"vectorStoreName,omitempty"`
+	// EmbeddingModel is the registered embedding model used to embed the documents. It cannot be
+	// changed, since the stored embeddings would no longer match the queries.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="embeddingModel is immutable"
+	EmbeddingModel string `json:"embeddingModel"`
+	// EmbeddingDimension is the dimension of the embeddings. Defaults to the dimension registered with the model.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="embeddingDimension is immutable"
+	EmbeddingDimension int32 `json:"embeddingDimension,omitempty"`
+	// ProviderID is the vector_io provider storing the vectors, as listed in the distribution status
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="providerId is immutable"
+	ProviderID string `json:"providerId"`
+	// Ingestion loads documents into the vector store with a Job
+	// +optional
+	Ingestion *IngestionSpec `json:"ingestion,omitempty"`
+}
+
+// IngestionSpec defines the documents ingested in a vector store. The ingestion runs again when the
+// spec changes or when the vector store had to be recreated.
+// +kubebuilder:validation:XValidation:rule="!has(self.chunkOverlap) || self.chunkOverlap < self.chunkSize",message="chunkOverlap must be smaller than chunkSize"
+type IngestionSpec struct {
+	// Sources of the documents
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	Sources []IngestionSource `json:"sources"`
+	// ChunkSize is the number of words per chunk
+	// +optional
+	// +kubebuilder:default=512
+	// +kubebuilder:validation:Minimum=16
+	ChunkSize int32 `json:"chunkSize,omitempty"`
+	// ChunkOverlap is the number of words shared by consecutive chunks
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ChunkOverlap int32 `json:"chunkOverlap,omitempty"`
+}
+
+// IngestionSource is a source of documents. Exactly one of its fields must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.configMap), has(self.persistentVolumeClaim), has(self.urls)].filter(x, x).size() == 1",message="exactly one of configMap, persistentVolumeClaim or urls must be set"
+type IngestionSource struct {
+	// ConfigMap ingests the keys of a ConfigMap in the namespace, each key being a document
+	// +optional
+	ConfigMap *ConfigMapIngestionSource `json:"configMap,omitempty"`
+	// PersistentVolumeClaim ingests the files under a path of a PVC in the namespace
+	// +optional
+	PersistentVolumeClaim *PVCIngestionSource `json:"persistentVolumeClaim,omitempty"`
+	// URLs of documents fetched over HTTP by the ingestion Job
+	// +optional
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=256
+	// +kubebuilder:validation:items:MaxLength=2048
+	URLs []string `json:"urls,omitempty"`
+}
+
+// ConfigMapIngestionSource selects the documents of a ConfigMap.
+type ConfigMapIngestionSource struct {
+	// Name of the ConfigMap
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Keys to ingest. All keys are ingested when empty.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// PVCIngestionSource selects the documents of a PersistentVolumeClaim.
+type PVCIngestionSource struct {
+	// ClaimName is the name of the PersistentVolumeClaim, which is mounted read-only
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+	// Path of the file or directory to ingest, relative to the root of the volume. Directories are
+	// walked recursively, skipping hidden entries.
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:XValidation:rule="!self.startsWith('/') && !self.contains('..')",message="path must be relative and must not contain '..'"
+	Path string `json:"path,omitempty"`
+}
+
+// IngestionPhase is the state of the ingestion Job.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type IngestionPhase string
+
+const (
+	// IngestionPhasePending means the Job was created but has not started.
+	IngestionPhasePending IngestionPhase = "Pending"
+	// IngestionPhaseRunning means the Job is ingesting the documents.
+	IngestionPhaseRunning IngestionPhase = "Running"
+	// IngestionPhaseSucceeded means every document was ingested.
+	IngestionPhaseSucceeded IngestionPhase = "Succeeded"
+	// IngestionPhaseFailed means the Job failed or some documents could not be ingested.
+	IngestionPhaseFailed IngestionPhase = "Failed"
+)
+
+// IngestionStatus reports the progress of the ingestion.
+type IngestionStatus struct {
+	// JobName is the name of the ingestion Job of the current spec and vector store
+	JobName string `json:"jobName"`
+	// Phase of the ingestion
+	Phase IngestionPhase `json:"phase"`
+	// Documents is the number of documents ingested
+	// +optional
+	Documents int32 `json:"documents,omitempty"`
+	// FailedDocuments is the number of documents that could not be read or inserted
+	// +optional
+	FailedDocuments int32 `json:"failedDocuments,omitempty"`
+	// Chunks is the number of chunks inserted in the vector store
+	// +optional
+	Chunks int32 `json:"chunks,omitempty"`
+	// StartTime is when the Job started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is when the Job finished
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message describes the first failure, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// LlamaStackVectorStoreStatus defines the observed state of LlamaStackVectorStore.
+type LlamaStackVectorStoreStatus struct {
+	// ObservedGeneration is the generation last reconciled
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// VectorStoreID is the identifier assigned by the server
+	// +optional
+	VectorStoreID string `json:"vectorStoreId,omitempty"`
+	// Ingestion reports the progress of the document ingestion
+	// +optional
+	Ingestion *IngestionStatus `json:"ingestion,omitempty"`
+	// Conditions represent the latest available observations of the vector store's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=llsvs
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Distribution",type="string",JSONPath=".spec.distributionRef.name"
+//+kubebuilder:printcolumn:name="Vector Store ID",type="string",JSONPath=".status.vectorStoreId"
+//+kubebuilder:printcolumn:name="Ingestion",type="string",JSONPath=".status.ingestion.phase"
+//+kubebuilder:printcolumn:name="Chunks",type="integer",JSONPath=".status.ingestion.chunks"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LlamaStackVectorStore creates a vector store in the server of a LlamaStackDistribution and
+// optionally ingests documents into it.
+type LlamaStackVectorStore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LlamaStackVectorStoreSpec   `json:"spec"`
+	Status LlamaStackVectorStoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LlamaStackVectorStoreList contains a list of LlamaStackVectorStore.
+type LlamaStackVectorStoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LlamaStackVectorStore `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits
+	SchemeBuilder.Register(&LlamaStackVectorStore{}, &LlamaStackVectorStoreList{})
+}
+
+// GetVectorStoreName returns the name of the vector store in the server.
+func (v *LlamaStackVectorStore) GetVectorStoreName() string {
+	if v.Spec.VectorStoreName != "" {
+		return v.Spec.VectorStoreName
+	}
+	return v.Name
+}
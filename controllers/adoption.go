@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetResourcesOwnedCondition reports the existing resources skipped because the distribution does
+// not own them in the ResourcesOwned condition.
+func SetResourcesOwnedCondition(status *llamav1alpha1.LlamaStackDistributionStatus, skipped []deploy.SkippedResource) {
+	if len(skipped) == 0 {
+		setConditionKeepingTransitionTime(status, metav1.Condition{
+			Type:    ConditionTypeResourcesOwned,
+			Status:  metav1.ConditionTrue,
+			Reason:  ReasonResourcesOwned,
+			Message: "Every existing resource is owned by the distribution",
+		})
+		return
+	}
+
+	resources := make([]string, 0, len(skipped))
+	for _, resource := range skipped {
+		if resource.Controller != "" {
+			resources = append(resources, fmt.Sprintf("%s %s (controlled by %s)", resource.Kind, resource.Name, resource.Controller))
+			continue
+		}
+		resources = append(resources, resource.Kind+" "+resource.Name)
+	}
+	setConditionKeepingTransitionTime(status, metav1.Condition{
+		Type:   ConditionTypeResourcesOwned,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonResourcesNotOwned,
+		Message: fmt.Sprintf("Skipped existing resources not owned by the distribution: %s. Annotate them with %s=%s "+
+			"or set spec.adoptionPolicy to Unowned to adopt those without a controller",
+			strings.Join(resources, ", "), llamav1alpha1.AdoptAnnotation, llamav1alpha1.AdoptValue),
+	})
+}
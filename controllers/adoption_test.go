@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetResourcesOwnedCondition(t *testing.T) {
+	status := &llamav1alpha1.LlamaStackDistributionStatus{}
+
+	SetResourcesOwnedCondition(status, []deploy.SkippedResource{
+		{Kind: "Service", Name: "llsd-service"},
+		{Kind: "Deployment", Name: "llsd", Controller: "LlamaStackDistribution/other"},
+	})
+
+	condition := GetCondition(status, ConditionTypeResourcesOwned)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonResourcesNotOwned, condition.Reason)
+	assert.Contains(t, condition.Message,
+		"Skipped existing resources not owned by the distribution: Service llsd-service, Deployment llsd (controlled by LlamaStackDistribution/other).")
+
+	SetResourcesOwnedCondition(status, nil)
+
+	assert.True(t, IsConditionTrue(status, ConditionTypeResourcesOwned))
+}
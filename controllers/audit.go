@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/go-logr/logr"
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/audit"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxHistoryEntries bounds status.history and must match its MaxItems validation.
+	maxHistoryEntries = 10
+	// maxEventChangedPaths bounds the number of changed paths listed in a SpecChanged Event.
+	maxEventChangedPaths = 5
+	// maxHistoryChangedPaths bounds the changed paths of a status.history entry and must match its MaxItems validation.
+	maxHistoryChangedPaths = 10
+)
+
+// specChangeTracker keeps the paths changed by spec updates until the generation they lead to is
+// recorded in status.history. Update events carry the previous spec, Reconcile does not.
+type specChangeTracker struct {
+	mu      sync.Mutex
+	pending map[types.NamespacedName]map[int64][]string
+}
+
+// add keeps the paths changed by the update to a generation.
+func (t *specChangeTracker) add(key types.NamespacedName, generation int64, paths []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending == nil {
+		t.pending = make(map[types.NamespacedName]map[int64][]string)
+	}
+	if t.pending[key] == nil {
+		t.pending[key] = make(map[int64][]string)
+	}
+	t.pending[key][generation] = append(t.pending[key][generation], paths...)
+}
+
+// take returns the sorted paths changed by the updates up to a generation and forgets them.
+// Later generations are kept for the reconciliation that observes them.
+func (t *specChangeTracker) take(key types.NamespacedName, generation int64) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var paths []string
+	for pendingGeneration, changed := range t.pending[key] {
+		if pendingGeneration <= generation {
+			paths = append(paths, changed...)
+			delete(t.pending[key], pendingGeneration)
+		}
+	}
+	if len(t.pending[key]) == 0 {
+		delete(t.pending, key)
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// forget drops the paths of a deleted distribution.
+func (t *specChangeTracker) forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, key)
+}
+
+// auditSpecChange logs and publishes the redacted difference between two versions of a spec.
+func (r *LlamaStackDistributionReconciler) auditSpecChange(logger logr.Logger, oldObj, newObj *llamav1alpha1.LlamaStackDistribution) {
+	logger = logger.WithValues("namespace", newObj.Namespace, "name", newObj.Name)
+
+	changes, err := audit.Diff("spec", oldObj.Spec, newObj.Spec, r.redactor())
+	if err != nil {
+		logger.Error(err, "failed to compute LlamaStackDistribution CR spec diff")
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	logger.Info("LlamaStackDistribution CR spec changed",
+		"generation", newObj.Generation,
+		"previousGeneration", oldObj.Generation,
+		"diff", changes)
+	r.Recorder.Eventf(newObj, corev1.EventTypeNormal, EventReasonSpecChanged,
+		"Spec changed in generation %d: %s", newObj.Generation, audit.Summarize(changes, maxEventChangedPaths))
+	r.specChanges.add(client.ObjectKeyFromObject(newObj), newObj.Generation, audit.Paths(changes))
+}
+
+// recordGeneration records the outcome of reconciling the current generation in status.history,
+// with the spec paths changed since the previous generation. Repeated reconciliations of a
+// generation update its entry, and only the last maxHistoryEntries generations are kept.
+func recordGeneration(instance *llamav1alpha1.LlamaStackDistribution, reconcileErr error, now metav1.Time, changedPaths []string) {
+	record := llamav1alpha1.GenerationRecord{
+		Generation: instance.Generation,
+		Outcome:    llamav1alpha1.GenerationOutcomeApplied,
+		Time:       now,
+	}
+	if reconcileErr != nil {
+		record.Outcome = llamav1alpha1.GenerationOutcomeFailed
+		record.Message = fmt.Sprintf("Resource reconciliation failed: %v", reconcileErr)
+	}
+	record.ChangedPaths = changedPaths
+	if len(changedPaths) > maxHistoryChangedPaths {
+		record.ChangedPaths = changedPaths[:maxHistoryChangedPaths]
+		record.OmittedChangedPaths = int32(len(changedPaths) - maxHistoryChangedPaths) //nolint:gosec // bounded by the spec size
+	}
+
+	history := instance.Status.History
+	if last := len(history) - 1; last >= 0 && history[last].Generation == record.Generation {
+		// The changes of a generation are taken by its first reconciliation.
+		record.ChangedPaths, record.OmittedChangedPaths = history[last].ChangedPaths, history[last].OmittedChangedPaths
+		// Keep the time of the first observation while the outcome is unchanged.
+		if history[last].Outcome == record.Outcome && history[last].Message == record.Message {
+			return
+		}
+		history[last] = record
+		return
+	}
+
+	history = append(history, record)
+	if len(history) > maxHistoryEntries {
+		history = history[len(history)-maxHistoryEntries:]
+	}
+	instance.Status.History = history
+}
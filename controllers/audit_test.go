@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRecordGeneration(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(start.Add(time.Minute))
+
+	t.Run("updates the entry of a generation that is reconciled again", func(t *testing.T) {
+		instance := &llamav1alpha1.LlamaStackDistribution{}
+		instance.Generation = 1
+
+		recordGeneration(instance, errors.New("boom"), start, []string{"spec.replicas"})
+		require.Len(t, instance.Status.History, 1)
+		assert.Equal(t, llamav1alpha1.GenerationOutcomeFailed, instance.Status.History[0].Outcome)
+		assert.Contains(t, instance.Status.History[0].Message, "boom")
+
+		recordGeneration(instance, nil, later, nil)
+		require.Len(t, instance.Status.History, 1)
+		assert.Equal(t, llamav1alpha1.GenerationOutcomeApplied, instance.Status.History[0].Outcome)
+		assert.Empty(t, instance.Status.History[0].Message)
+		assert.Equal(t, later, instance.Status.History[0].Time)
+		assert.Equal(t, []string{"spec.replicas"}, instance.Status.History[0].ChangedPaths, "the changes of the generation are kept")
+
+		recordGeneration(instance, nil, metav1.NewTime(later.Add(time.Minute)), nil)
+		assert.Equal(t, later, instance.Status.History[0].Time, "unchanged outcomes keep their time")
+	})
+
+	t.Run("keeps only the most recent generations", func(t *testing.T) {
+		instance := &llamav1alpha1.LlamaStackDistribution{}
+		for generation := int64(1); generation <= maxHistoryEntries+3; generation++ {
+			instance.Generation = generation
+			recordGeneration(instance, nil, start, nil)
+		}
+
+		require.Len(t, instance.Status.History, maxHistoryEntries)
+		assert.Equal(t, int64(4), instance.Status.History[0].Generation)
+		assert.Equal(t, int64(maxHistoryEntries+3), instance.Status.History[maxHistoryEntries-1].Generation)
+	})
+
+	t.Run("caps the changed paths", func(t *testing.T) {
+		instance := &llamav1alpha1.LlamaStackDistribution{}
+		paths := make([]string, maxHistoryChangedPaths+2)
+		for i := range paths {
+			paths[i] = fmt.Sprintf("spec.server.containerSpec.env[%d].value", i)
+		}
+
+		recordGeneration(instance, nil, start, paths)
+
+		require.Len(t, instance.Status.History, 1)
+		assert.Equal(t, paths[:maxHistoryChangedPaths], instance.Status.History[0].ChangedPaths)
+		assert.Equal(t, int32(2), instance.Status.History[0].OmittedChangedPaths)
+	})
+}
+
+func TestSpecChangeTracker(t *testing.T) {
+	var tracker specChangeTracker
+	key := types.NamespacedName{Name: "llsd", Namespace: "ns"}
+	tracker.add(key, 2, []string{"spec.replicas"})
+	tracker.add(key, 3, []string{"spec.server.distribution.name", "spec.replicas"})
+	tracker.add(key, 4, []string{"spec.suspend"})
+
+	assert.Equal(t, []string{"spec.replicas", "spec.server.distribution.name"}, tracker.take(key, 3),
+		"the changes of the skipped generations are merged")
+	assert.Equal(t, []string{"spec.suspend"}, tracker.take(key, 4))
+	assert.Empty(t, tracker.take(key, 4))
+
+	tracker.add(key, 5, []string{"spec.replicas"})
+	tracker.forget(key)
+	assert.Empty(t, tracker.take(key, 5))
+}
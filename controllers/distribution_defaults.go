@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getDistributionDefaults returns the LlamaStackDistributionDefaults of the namespace, or nil when
+// the namespace has none.
+func (r *LlamaStackDistributionReconciler) getDistributionDefaults(ctx context.Context, namespace string) (*llamav1alpha1.LlamaStackDistributionDefaults, error) {
+	defaults := &llamav1alpha1.LlamaStackDistributionDefaults{}
+	key := client.ObjectKey{Namespace: namespace, Name: llamav1alpha1.DistributionDefaultsName}
+	if err := r.Get(ctx, key, defaults); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get LlamaStackDistributionDefaults: %w", err)
+	}
+	return defaults, nil
+}
+
+// effectiveDistribution returns the distribution rendered by the operator. When the namespace has
+// defaults, it is a copy of the instance whose spec has the defaults merged under it, and whose
+// status reports the merged defaults. Otherwise it is the instance itself.
+func (r *LlamaStackDistributionReconciler) effectiveDistribution(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+) (*llamav1alpha1.LlamaStackDistribution, error) {
+	defaults, err := r.getDistributionDefaults(ctx, instance.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if defaults == nil {
+		instance.Status.Defaults = nil
+		return instance, nil
+	}
+
+	effective := instance.DeepCopy()
+	effective.Spec = *mergeDistributionDefaults(&instance.Spec, &defaults.Spec)
+
+	raw, err := json.Marshal(effective.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal effective spec: %w", err)
+	}
+	effective.Status.Defaults = &llamav1alpha1.DefaultsStatus{
+		Name:               defaults.Name,
+		ObservedGeneration: defaults.Generation,
+		EffectiveSpec:      &apiextensionsv1.JSON{Raw: raw},
+	}
+	log.FromContext(ctx).V(1).Info("merged namespace defaults", "generation", defaults.Generation)
+	return effective, nil
+}
+
+// mergeDistributionDefaults returns a copy of spec with the namespace defaults merged under it.
+// The settings of the distribution always take precedence:
+//   - env: the default variables come first, except those the distribution sets itself
+//   - resources: the defaults are used when the distribution sets neither requests nor limits
+//   - TLS: the default CA bundle is used when the distribution sets none
+//   - node selector and pod labels: merged by key
+//   - tolerations: the default tolerations are added to those of the distribution
+//   - affinity: the default affinity is used when the distribution sets none
+func mergeDistributionDefaults(
+	spec *llamav1alpha1.LlamaStackDistributionSpec,
+	defaults *llamav1alpha1.LlamaStackDistributionDefaultsSpec,
+) *llamav1alpha1.LlamaStackDistributionSpec {
+	merged := spec.DeepCopy()
+	defaults = defaults.DeepCopy()
+	server := &merged.Server
+	container := &server.ContainerSpec
+
+	container.Env = mergeEnv(defaults.Env, container.Env)
+
+	if defaults.Resources != nil && len(container.Resources.Requests) == 0 && len(container.Resources.Limits) == 0 {
+		container.Resources = *defaults.Resources
+	}
+
+	if defaults.TLSConfig != nil && defaults.TLSConfig.CABundle != nil {
+		if server.TLSConfig == nil {
+			server.TLSConfig = &llamav1alpha1.TLSConfig{}
+		}
+		if server.TLSConfig.CABundle == nil {
+			server.TLSConfig.CABundle = defaults.TLSConfig.CABundle
+		}
+	}
+
+	if len(defaults.NodeSelector) == 0 && len(defaults.Tolerations) == 0 && defaults.Affinity == nil && len(defaults.Labels) == 0 {
+		return merged
+	}
+	if server.PodOverrides == nil {
+		server.PodOverrides = &llamav1alpha1.PodOverrides{}
+	}
+	overrides := server.PodOverrides
+	overrides.NodeSelector = mergeStringMaps(defaults.NodeSelector, overrides.NodeSelector)
+	overrides.Labels = mergeStringMaps(defaults.Labels, overrides.Labels)
+	overrides.Tolerations = mergeTolerations(defaults.Tolerations, overrides.Tolerations)
+	if overrides.Affinity == nil {
+		overrides.Affinity = defaults.Affinity
+	}
+	return merged
+}
+
+// mergeEnv returns the default variables not set by the distribution, followed by the variables of
+// the distribution.
+func mergeEnv(defaults, env []corev1.EnvVar) []corev1.EnvVar {
+	if len(defaults) == 0 {
+		return env
+	}
+	set := make(map[string]bool, len(env))
+	for _, envVar := range env {
+		set[envVar.Name] = true
+	}
+	merged := make([]corev1.EnvVar, 0, len(defaults)+len(env))
+	for _, envVar := range defaults {
+		if !set[envVar.Name] {
+			merged = append(merged, envVar)
+		}
+	}
+	return append(merged, env...)
+}
+
+// mergeStringMaps returns the defaults overridden by values.
+func mergeStringMaps(defaults, values map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return values
+	}
+	merged := maps.Clone(defaults)
+	maps.Copy(merged, values)
+	return merged
+}
+
+// mergeTolerations returns the tolerations followed by the defaults they do not already contain.
+func mergeTolerations(defaults, tolerations []corev1.Toleration) []corev1.Toleration {
+	merged := tolerations
+	for i := range defaults {
+		if !containsToleration(tolerations, &defaults[i]) {
+			merged = append(merged, defaults[i])
+		}
+	}
+	return merged
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration *corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+// findDistributionsForDefaults maps the namespace defaults to every LlamaStackDistribution of the namespace.
+func (r *LlamaStackDistributionReconciler) findDistributionsForDefaults(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != llamav1alpha1.DistributionDefaultsName {
+		return nil
+	}
+	distributions := &llamav1alpha1.LlamaStackDistributionList{}
+	if err := r.List(ctx, distributions, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list LlamaStackDistributions for namespace defaults", "namespace", obj.GetNamespace())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(distributions.Items))
+	for i := range distributions.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&distributions.Items[i])})
+	}
+	return requests
+}
+
+// findDistributionsForDefaultsCABundle returns the distributions that mount the ConfigMap because
+// the defaults of their namespace reference it as the CA bundle, and they set none of their own.
+func (r *LlamaStackDistributionReconciler) findDistributionsForDefaultsCABundle(
+	ctx context.Context,
+	configMap client.Object,
+) []llamav1alpha1.LlamaStackDistribution {
+	logger := log.FromContext(ctx).WithValues(
+		"configMapName", configMap.GetName(),
+		"configMapNamespace", configMap.GetNamespace())
+
+	defaultsList := &llamav1alpha1.LlamaStackDistributionDefaultsList{}
+	if err := r.List(ctx, defaultsList); err != nil {
+		logger.Error(err, "failed to list LlamaStackDistributionDefaults for ConfigMap reference search")
+		return nil
+	}
+
+	var referencing []llamav1alpha1.LlamaStackDistribution
+	for i := range defaultsList.Items {
+		defaults := &defaultsList.Items[i]
+		if defaults.Name != llamav1alpha1.DistributionDefaultsName ||
+			defaults.Spec.TLSConfig == nil || defaults.Spec.TLSConfig.CABundle == nil {
+			continue
+		}
+		distributions := &llamav1alpha1.LlamaStackDistributionList{}
+		if err := r.List(ctx, distributions, client.InNamespace(defaults.Namespace)); err != nil {
+			logger.Error(err, "failed to list LlamaStackDistributions for namespace defaults", "namespace", defaults.Namespace)
+			continue
+		}
+		for _, ls := range distributions.Items {
+			if hasValidCABundleConfig(&ls) {
+				continue
+			}
+			effective := ls.DeepCopy()
+			effective.Spec = *mergeDistributionDefaults(&ls.Spec, &defaults.Spec)
+			if r.doesLlamaStackReferenceConfigMap(*effective, configMap.GetNamespace(), configMap.GetName()) {
+				referencing = append(referencing, ls)
+			}
+		}
+	}
+	return referencing
+}
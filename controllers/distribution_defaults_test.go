@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMergeDistributionDefaults(t *testing.T) {
+	defaultResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}
+	distributionResources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+	}
+	defaultCABundle := &llamav1alpha1.CABundleConfig{ConfigMapName: "tenant-ca"}
+	distributionCABundle := &llamav1alpha1.CABundleConfig{ConfigMapName: "custom-ca"}
+	gpuToleration := corev1.Toleration{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	spotToleration := corev1.Toleration{Key: "spot", Operator: corev1.TolerationOpExists}
+	defaultAffinity := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+	distributionAffinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+
+	defaults := &llamav1alpha1.LlamaStackDistributionDefaultsSpec{
+		Env: []corev1.EnvVar{
+			{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+			{Name: "LOG_LEVEL", Value: "info"},
+		},
+		Resources:    &defaultResources,
+		TLSConfig:    &llamav1alpha1.TLSConfig{CABundle: defaultCABundle},
+		NodeSelector: map[string]string{"pool": "inference", "zone": "a"},
+		Tolerations:  []corev1.Toleration{gpuToleration},
+		Affinity:     defaultAffinity,
+		Labels:       map[string]string{"cost-center": "ai", "team": "platform"},
+	}
+
+	tests := []struct {
+		name   string
+		server llamav1alpha1.ServerSpec
+		assert func(t *testing.T, merged *llamav1alpha1.ServerSpec)
+	}{
+		{
+			name:   "uses the defaults when the distribution sets nothing",
+			server: llamav1alpha1.ServerSpec{},
+			assert: func(t *testing.T, merged *llamav1alpha1.ServerSpec) {
+				t.Helper()
+				assert.Equal(t, defaults.Env, merged.ContainerSpec.Env)
+				assert.Equal(t, defaultResources, merged.ContainerSpec.Resources)
+				assert.Equal(t, defaultCABundle, merged.TLSConfig.CABundle)
+				require.NotNil(t, merged.PodOverrides)
+				assert.Equal(t, defaults.NodeSelector, merged.PodOverrides.NodeSelector)
+				assert.Equal(t, []corev1.Toleration{gpuToleration}, merged.PodOverrides.Tolerations)
+				assert.Equal(t, defaultAffinity, merged.PodOverrides.Affinity)
+				assert.Equal(t, defaults.Labels, merged.PodOverrides.Labels)
+			},
+		},
+		{
+			name: "the distribution overrides the defaults",
+			server: llamav1alpha1.ServerSpec{
+				ContainerSpec: llamav1alpha1.ContainerSpec{
+					Env:       []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "INFERENCE_MODEL", Value: "llama"}},
+					Resources: distributionResources,
+				},
+				TLSConfig: &llamav1alpha1.TLSConfig{CABundle: distributionCABundle},
+				PodOverrides: &llamav1alpha1.PodOverrides{
+					NodeSelector: map[string]string{"zone": "b"},
+					Tolerations:  []corev1.Toleration{spotToleration},
+					Affinity:     distributionAffinity,
+					Labels:       map[string]string{"team": "search"},
+				},
+			},
+			assert: func(t *testing.T, merged *llamav1alpha1.ServerSpec) {
+				t.Helper()
+				assert.Equal(t, []corev1.EnvVar{
+					{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "INFERENCE_MODEL", Value: "llama"},
+				}, merged.ContainerSpec.Env)
+				assert.Equal(t, distributionResources, merged.ContainerSpec.Resources)
+				assert.Equal(t, distributionCABundle, merged.TLSConfig.CABundle)
+				assert.Equal(t, map[string]string{"pool": "inference", "zone": "b"}, merged.PodOverrides.NodeSelector)
+				assert.Equal(t, []corev1.Toleration{spotToleration, gpuToleration}, merged.PodOverrides.Tolerations)
+				assert.Equal(t, distributionAffinity, merged.PodOverrides.Affinity)
+				assert.Equal(t, map[string]string{"cost-center": "ai", "team": "search"}, merged.PodOverrides.Labels)
+			},
+		},
+		{
+			name: "does not duplicate tolerations set by both",
+			server: llamav1alpha1.ServerSpec{
+				PodOverrides: &llamav1alpha1.PodOverrides{Tolerations: []corev1.Toleration{gpuToleration}},
+			},
+			assert: func(t *testing.T, merged *llamav1alpha1.ServerSpec) {
+				t.Helper()
+				assert.Equal(t, []corev1.Toleration{gpuToleration}, merged.PodOverrides.Tolerations)
+			},
+		},
+		{
+			name: "keeps the other TLS settings of the distribution",
+			server: llamav1alpha1.ServerSpec{
+				TLSConfig: &llamav1alpha1.TLSConfig{},
+			},
+			assert: func(t *testing.T, merged *llamav1alpha1.ServerSpec) {
+				t.Helper()
+				assert.Equal(t, defaultCABundle, merged.TLSConfig.CABundle)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &llamav1alpha1.LlamaStackDistributionSpec{Server: tt.server}
+			original := spec.DeepCopy()
+
+			merged := mergeDistributionDefaults(spec, defaults)
+
+			tt.assert(t, &merged.Server)
+			assert.Equal(t, original, spec, "the spec of the distribution must not be modified")
+		})
+	}
+}
+
+func TestEffectiveDistribution(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "tenant"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{
+				ContainerSpec: llamav1alpha1.ContainerSpec{Env: []corev1.EnvVar{{Name: "A", Value: "cr"}}},
+			},
+		},
+	}
+
+	t.Run("returns the instance without defaults", func(t *testing.T) {
+		r := &LlamaStackDistributionReconciler{
+			Client: clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).Build(),
+		}
+		current := instance.DeepCopy()
+		current.Status.Defaults = &llamav1alpha1.DefaultsStatus{Name: llamav1alpha1.DistributionDefaultsName}
+
+		effective, err := r.effectiveDistribution(t.Context(), current)
+		require.NoError(t, err)
+		assert.Same(t, current, effective)
+		assert.Nil(t, current.Status.Defaults)
+	})
+
+	t.Run("merges the defaults of the namespace", func(t *testing.T) {
+		r := &LlamaStackDistributionReconciler{
+			Client: clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+				WithObjects(
+					&llamav1alpha1.LlamaStackDistributionDefaults{
+						ObjectMeta: metav1.ObjectMeta{Name: llamav1alpha1.DistributionDefaultsName, Namespace: "tenant", Generation: 3},
+						Spec:       llamav1alpha1.LlamaStackDistributionDefaultsSpec{Env: []corev1.EnvVar{{Name: "A", Value: "default"}, {Name: "B", Value: "default"}}},
+					},
+					&llamav1alpha1.LlamaStackDistributionDefaults{
+						ObjectMeta: metav1.ObjectMeta{Name: llamav1alpha1.DistributionDefaultsName, Namespace: "other"},
+						Spec:       llamav1alpha1.LlamaStackDistributionDefaultsSpec{Env: []corev1.EnvVar{{Name: "C", Value: "other"}}},
+					},
+				).
+				Build(),
+		}
+		current := instance.DeepCopy()
+
+		effective, err := r.effectiveDistribution(t.Context(), current)
+		require.NoError(t, err)
+		assert.NotSame(t, current, effective)
+		assert.Equal(t, instance.Spec, current.Spec)
+		assert.Equal(t, []corev1.EnvVar{{Name: "B", Value: "default"}, {Name: "A", Value: "cr"}}, effective.Spec.Server.ContainerSpec.Env)
+
+		require.NotNil(t, effective.Status.Defaults)
+		assert.Equal(t, llamav1alpha1.DistributionDefaultsName, effective.Status.Defaults.Name)
+		assert.Equal(t, int64(3), effective.Status.Defaults.ObservedGeneration)
+		reported := llamav1alpha1.LlamaStackDistributionSpec{}
+		require.NoError(t, json.Unmarshal(effective.Status.Defaults.EffectiveSpec.Raw, &reported))
+		assert.Equal(t, effective.Spec, reported)
+	})
+}
+
+func TestFindDistributionsForDefaultsCABundle(t *testing.T) {
+	distribution := func(name string, tlsConfig *llamav1alpha1.TLSConfig) *llamav1alpha1.LlamaStackDistribution {
+		return &llamav1alpha1.LlamaStackDistribution{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant"},
+			Spec:       llamav1alpha1.LlamaStackDistributionSpec{Server: llamav1alpha1.ServerSpec{TLSConfig: tlsConfig}},
+		}
+	}
+	r := &LlamaStackDistributionReconciler{
+		Client: clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+			WithObjects(
+				&llamav1alpha1.LlamaStackDistributionDefaults{
+					ObjectMeta: metav1.ObjectMeta{Name: llamav1alpha1.DistributionDefaultsName, Namespace: "tenant"},
+					Spec: llamav1alpha1.LlamaStackDistributionDefaultsSpec{
+						TLSConfig: &llamav1alpha1.TLSConfig{CABundle: &llamav1alpha1.CABundleConfig{ConfigMapName: "tenant-ca"}},
+					},
+				},
+				distribution("inherits", nil),
+				distribution("own", &llamav1alpha1.TLSConfig{CABundle: &llamav1alpha1.CABundleConfig{ConfigMapName: "own-ca"}}),
+			).
+			Build(),
+	}
+	configMap := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tenant"}}
+	}
+
+	t.Run("maps the default CA bundle to the distributions inheriting it", func(t *testing.T) {
+		requests := r.findLlamaStackDistributionsForConfigMap(t.Context(), configMap("tenant-ca"))
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "inherits", requests[0].Name)
+		assert.True(t, r.isConfigMapReferenced(configMap("tenant-ca")))
+	})
+
+	t.Run("keeps mapping the CA bundle set by the distribution", func(t *testing.T) {
+		requests := r.findLlamaStackDistributionsForConfigMap(t.Context(), configMap("own-ca"))
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, "own", requests[0].Name)
+	})
+
+	t.Run("ignores unreferenced ConfigMaps", func(t *testing.T) {
+		assert.Empty(t, r.findLlamaStackDistributionsForConfigMap(t.Context(), configMap("unrelated")))
+		assert.False(t, r.isConfigMapReferenced(configMap("unrelated")))
+	})
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxDriftPaths is the number of drifted fields listed per resource in the condition and Events.
+const maxDriftPaths = 5
+
+// reportDrift sets the Drifted condition and emits an Event for each corrected resource. Resources
+// left drifted by the Report policy are reported by an Event when the drift changes.
+func (r *LlamaStackDistributionReconciler) reportDrift(instance *llamav1alpha1.LlamaStackDistribution, drifts []deploy.ResourceDrift) {
+	var remaining []string
+	for _, drift := range drifts {
+		if drift.Corrected {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, EventReasonDriftCorrected,
+				"Reverted changes made outside the operator to %s", formatDrift(drift))
+			continue
+		}
+		remaining = append(remaining, formatDrift(drift))
+	}
+
+	if len(remaining) == 0 {
+		setConditionKeepingTransitionTime(&instance.Status, metav1.Condition{
+			Type:    ConditionTypeDrifted,
+			Status:  metav1.ConditionFalse,
+			Reason:  ReasonNoDrift,
+			Message: "The managed resources match their desired state",
+		})
+		return
+	}
+
+	message := "Changes made outside the operator to " + strings.Join(remaining, "; ")
+	previous := GetCondition(&instance.Status, ConditionTypeDrifted)
+	if previous == nil || previous.Status != metav1.ConditionTrue || previous.Message != message {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, EventReasonDriftDetected, message)
+	}
+	setConditionKeepingTransitionTime(&instance.Status, metav1.Condition{
+		Type:    ConditionTypeDrifted,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonDriftDetected,
+		Message: message,
+	})
+}
+
+// formatDrift describes a drifted resource and its first drifted fields.
+func formatDrift(drift deploy.ResourceDrift) string {
+	paths := drift.Paths
+	suffix := ""
+	if len(paths) > maxDriftPaths {
+		suffix = fmt.Sprintf(" and %d more", len(paths)-maxDriftPaths)
+		paths = paths[:maxDriftPaths]
+	}
+	return fmt.Sprintf("%s %s: %s%s", drift.Kind, drift.Name, strings.Join(paths, ", "), suffix)
+}
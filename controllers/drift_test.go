@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReportDrift(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &LlamaStackDistributionReconciler{Recorder: recorder}
+	instance := &llamav1alpha1.LlamaStackDistribution{ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns"}}
+	drifted := []deploy.ResourceDrift{{Kind: "Service", Name: "llsd-service", Paths: []string{"spec.type"}}}
+
+	t.Run("reports resources left drifted", func(t *testing.T) {
+		r.reportDrift(instance, drifted)
+
+		condition := GetCondition(&instance.Status, ConditionTypeDrifted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, ReasonDriftDetected, condition.Reason)
+		assert.Equal(t, "Changes made outside the operator to Service llsd-service: spec.type", condition.Message)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning DriftDetected "+condition.Message, <-recorder.Events)
+	})
+
+	t.Run("does not repeat the Event for the same drift", func(t *testing.T) {
+		r.reportDrift(instance, drifted)
+
+		assert.True(t, IsConditionTrue(&instance.Status, ConditionTypeDrifted))
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("reports corrected resources", func(t *testing.T) {
+		paths := []string{"a", "b", "c", "d", "e", "f", "g"}
+		r.reportDrift(instance, []deploy.ResourceDrift{{Kind: "Deployment", Name: "llsd", Paths: paths, Corrected: true}})
+
+		condition := GetCondition(&instance.Status, ConditionTypeDrifted)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, ReasonNoDrift, condition.Reason)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal DriftCorrected Reverted changes made outside the operator to Deployment llsd: a, b, c, d, e and 2 more",
+			<-recorder.Events)
+	})
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"fmt"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Event reasons emitted on LlamaStackDistribution resources. Tooling may filter on
+// these values, so they must stay stable.
+const (
+	// EventReasonPhaseChanged is emitted when the distribution moves to another phase.
+	EventReasonPhaseChanged = "PhaseChanged"
+	// EventReasonConfigMapRestart is emitted when a change to a referenced ConfigMap restarts the server pods.
+	EventReasonConfigMapRestart = "ConfigMapRestart"
+	// EventReasonCABundleInvalid is emitted when the referenced CA bundle fails validation.
+	EventReasonCABundleInvalid = "CABundleInvalid"
+	// EventReasonSpecChanged is emitted with a redacted summary when the spec changes.
+	EventReasonSpecChanged = "SpecChanged"
+	// EventReasonProviderHealthChanged is emitted when a provider changes between OK and Error.
+	EventReasonProviderHealthChanged = "ProviderHealthChanged"
+	// EventReasonShieldsMissing is emitted when a required shield goes missing from the server.
+	EventReasonShieldsMissing = "ShieldsMissing"
+	// EventReasonShieldsEnforced is emitted when every required shield is registered again.
+	EventReasonShieldsEnforced = "ShieldsEnforced"
+	// EventReasonResourceNotOwned is emitted when an existing resource is skipped because another owner manages it.
+	EventReasonResourceNotOwned = deploy.EventReasonResourceNotOwned
+	// EventReasonResourceAdopted is emitted when an existing resource is adopted by the distribution.
+	EventReasonResourceAdopted = deploy.EventReasonResourceAdopted
+	// EventReasonResourcePruned is emitted when a resource the distribution no longer renders is deleted.
+	EventReasonResourcePruned = "ResourcePruned"
+	// EventReasonPruneDryRun is emitted instead of deleting a resource while pruning runs in dry-run mode.
+	EventReasonPruneDryRun = "PruneDryRun"
+	// EventReasonDriftDetected is emitted when drifted resources are reported and left as they are.
+	EventReasonDriftDetected = "DriftDetected"
+	// EventReasonDriftCorrected is emitted when the desired state is applied again to a drifted resource.
+	EventReasonDriftCorrected = "DriftCorrected"
+	// EventReasonTelemetryNotApplied is emitted when the telemetry provider cannot be merged into the run.yaml provided through userConfig.
+	EventReasonTelemetryNotApplied = "TelemetryNotApplied"
+)
+
+// Event reasons emitted on the operator ConfigMap.
+const (
+	// EventReasonOperatorConfigReloaded is emitted when changed feature flags are applied.
+	EventReasonOperatorConfigReloaded = "OperatorConfigReloaded"
+	// EventReasonOperatorConfigInvalid is emitted when the feature flags cannot be parsed and the previous ones are kept.
+	EventReasonOperatorConfigInvalid = "OperatorConfigInvalid"
+)
+
+// recordPhaseChange emits an Event if the phase changed during this reconciliation.
+func (r *LlamaStackDistributionReconciler) recordPhaseChange(instance *llamav1alpha1.LlamaStackDistribution, previous llamav1alpha1.DistributionPhase) {
+	current := instance.Status.Phase
+	if current == previous || current == "" {
+		return
+	}
+
+	message := fmt.Sprintf("Phase changed from %s to %s", previous, current)
+	if previous == "" {
+		message = fmt.Sprintf("Phase changed to %s", current)
+	}
+
+	eventType := corev1.EventTypeNormal
+	if current == llamav1alpha1.LlamaStackDistributionPhaseFailed {
+		eventType = corev1.EventTypeWarning
+		if condition := GetCondition(&instance.Status, ConditionTypeDeploymentReady); condition != nil {
+			message = fmt.Sprintf("%s: %s", message, condition.Message)
+		}
+	}
+	r.Recorder.Event(instance, eventType, EventReasonPhaseChanged, message)
+}
+
+// recordConfigMapRestarts emits an Event for every referenced ConfigMap whose change restarted the
+// server pods. The restart happens when the apply patched the Deployment, so the hashes on its pod
+// template before the patch are compared with the applied ones.
+func (r *LlamaStackDistributionReconciler) recordConfigMapRestarts(
+	instance *llamav1alpha1.LlamaStackDistribution,
+	patched []deploy.PatchedResource,
+	manifestCtx *deploy.ManifestContext,
+) {
+	var previous *unstructured.Unstructured
+	for _, resource := range patched {
+		if resource.Kind == "Deployment" && resource.Name == instance.Name {
+			previous = resource.Previous
+			break
+		}
+	}
+	if previous == nil {
+		// The Deployment was created or left untouched, so nothing restarts.
+		return
+	}
+	running, _, _ := unstructured.NestedStringMap(previous.Object, "spec", "template", "metadata", "annotations")
+
+	if r.hasUserConfigMap(instance) && configMapHashChanged(running[deploy.UserConfigHashAnnotation], manifestCtx.ConfigMapHash) {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, EventReasonConfigMapRestart,
+			"Restarting pods because user ConfigMap %s/%s changed",
+			r.getUserConfigMapNamespace(instance), instance.Spec.Server.UserConfig.ConfigMapName)
+		configMapRestarts.WithLabelValues(instance.Namespace, instance.Name, configMapKindUserConfig).Inc()
+	}
+	if r.hasCABundleConfigMap(instance) && configMapHashChanged(running[deploy.CABundleHashAnnotation], manifestCtx.CABundleHash) {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, EventReasonConfigMapRestart,
+			"Restarting pods because CA bundle ConfigMap %s/%s changed",
+			r.getCABundleConfigMapNamespace(instance), instance.Spec.Server.TLSConfig.CABundle.ConfigMapName)
+		configMapRestarts.WithLabelValues(instance.Namespace, instance.Name, configMapKindCABundle).Inc()
+	}
+}
+
+// configMapHashChanged returns true if a ConfigMap that was already mounted has new content.
+func configMapHashChanged(running, desired string) bool {
+	return running != "" && desired != "" && running != desired
+}
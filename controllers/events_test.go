@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRecordPhaseChange(t *testing.T) {
+	testCases := []struct {
+		name          string
+		previous      llamav1alpha1.DistributionPhase
+		current       llamav1alpha1.DistributionPhase
+		expectedEvent string
+	}{
+		{
+			name:          "first phase",
+			current:       llamav1alpha1.LlamaStackDistributionPhasePending,
+			expectedEvent: "Normal PhaseChanged Phase changed to Pending",
+		},
+		{
+			name:          "becomes ready",
+			previous:      llamav1alpha1.LlamaStackDistributionPhaseInitializing,
+			current:       llamav1alpha1.LlamaStackDistributionPhaseReady,
+			expectedEvent: "Normal PhaseChanged Phase changed from Initializing to Ready",
+		},
+		{
+			name:          "failure includes the reason",
+			previous:      llamav1alpha1.LlamaStackDistributionPhaseReady,
+			current:       llamav1alpha1.LlamaStackDistributionPhaseFailed,
+			expectedEvent: "Warning PhaseChanged Phase changed from Ready to Failed: Resource reconciliation failed",
+		},
+		{
+			name:     "unchanged phase",
+			previous: llamav1alpha1.LlamaStackDistributionPhaseReady,
+			current:  llamav1alpha1.LlamaStackDistributionPhaseReady,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			r := &LlamaStackDistributionReconciler{Recorder: recorder}
+			instance := &llamav1alpha1.LlamaStackDistribution{}
+			instance.Status.Phase = tc.current
+			SetDeploymentReadyCondition(&instance.Status, false, "Resource reconciliation failed")
+
+			r.recordPhaseChange(instance, tc.previous)
+
+			if tc.expectedEvent == "" {
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			assert.Equal(t, tc.expectedEvent, <-recorder.Events)
+		})
+	}
+}
+
+func TestConfigMapHashChanged(t *testing.T) {
+	assert.True(t, configMapHashChanged("old", "new"), "changed content restarts the pods")
+	assert.False(t, configMapHashChanged("same", "same"), "unchanged content does not restart the pods")
+	assert.False(t, configMapHashChanged("", "new"), "a newly referenced ConfigMap is not a restart")
+	assert.False(t, configMapHashChanged("old", ""), "a removed reference is not reported as a ConfigMap change")
+}
+
+func TestRecordConfigMapRestarts(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{
+				UserConfig: &llamav1alpha1.UserConfigSpec{ConfigMapName: "user-config"},
+			},
+		},
+	}
+	t.Cleanup(func() { deleteDistributionMetrics(instance.Namespace, instance.Name) })
+	manifestCtx := &deploy.ManifestContext{ConfigMapHash: "new"}
+	deploymentWithHash := func(name, hash string) deploy.PatchedResource {
+		previous := &unstructured.Unstructured{Object: map[string]any{}}
+		previous.SetKind("Deployment")
+		previous.SetName(name)
+		require.NoError(t, unstructured.SetNestedStringMap(previous.Object,
+			map[string]string{deploy.UserConfigHashAnnotation: hash}, "spec", "template", "metadata", "annotations"))
+		return deploy.PatchedResource{Kind: "Deployment", Name: name, Previous: previous}
+	}
+
+	testCases := []struct {
+		name          string
+		patched       []deploy.PatchedResource
+		expectedEvent string
+	}{
+		{
+			name:          "Deployment patched with a new hash",
+			patched:       []deploy.PatchedResource{deploymentWithHash("llsd", "old")},
+			expectedEvent: "Normal ConfigMapRestart Restarting pods because user ConfigMap ns/user-config changed",
+		},
+		{
+			name:    "Deployment patched with the same hash",
+			patched: []deploy.PatchedResource{deploymentWithHash("llsd", "new")},
+		},
+		{
+			name:    "Deployment not patched",
+			patched: []deploy.PatchedResource{deploymentWithHash("llsd-preview", "old")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			r := &LlamaStackDistributionReconciler{Recorder: recorder}
+			restarts := configMapRestarts.WithLabelValues(instance.Namespace, instance.Name, configMapKindUserConfig)
+			before := testutil.ToFloat64(restarts)
+
+			r.recordConfigMapRestarts(instance, tc.patched, manifestCtx)
+
+			if tc.expectedEvent == "" {
+				assert.Empty(t, recorder.Events)
+				assert.InDelta(t, before, testutil.ToFloat64(restarts), 0)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			assert.Equal(t, tc.expectedEvent, <-recorder.Events)
+			assert.InDelta(t, before+1, testutil.ToFloat64(restarts), 0, "each restart is counted once")
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultHealthProbeInterval is how often ready distributions are probed by default.
+	DefaultHealthProbeInterval = 30 * time.Second
+	// DefaultHealthProbeConcurrency is the default number of distributions probed in parallel.
+	DefaultHealthProbeConcurrency = 10
+
+	// healthProbeTriggerBuffer bounds the probes requested by Reconcile between two ticks.
+	healthProbeTriggerBuffer = 128
+	// healthProbeEventBuffer bounds the change notifications waiting to be enqueued.
+	healthProbeEventBuffer = 128
+)
+
+// HealthProberOptions configures the background health prober.
+type HealthProberOptions struct {
+	// Interval between two probes of the same distribution. The prober is disabled when zero.
+	Interval time.Duration
+	// Concurrency is the maximum number of distributions probed in parallel.
+	Concurrency int
+}
+
+// healthProbeResult is the outcome of querying the providers and version endpoints of a distribution.
+type healthProbeResult struct {
+	Providers    []llamav1alpha1.ProviderInfo
+	ProvidersErr error
+	Version      string
+	VersionErr   error
+	Models       registeredQuery
+	Shields      registeredQuery
+	VectorDBs    registeredQuery
+	// PreviewRevision is the revision of the preview probed during a Canary or BlueGreen rollout, empty otherwise.
+	PreviewRevision string
+	PreviewErr      error
+}
+
+// equal compares two results, only considering whether the requests failed and not the error message.
+func (h healthProbeResult) equal(other healthProbeResult) bool {
+	return (h.ProvidersErr == nil) == (other.ProvidersErr == nil) &&
+		(h.VersionErr == nil) == (other.VersionErr == nil) &&
+		h.Version == other.Version &&
+		equality.Semantic.DeepEqual(h.Providers, other.Providers) &&
+		h.Models.equal(other.Models) &&
+		h.Shields.equal(other.Shields) &&
+		h.VectorDBs.equal(other.VectorDBs) &&
+		h.PreviewRevision == other.PreviewRevision &&
+		(h.PreviewErr == nil) == (other.PreviewErr == nil)
+}
+
+type healthProbeFunc func(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) healthProbeResult
+
+// HealthProber polls the providers and version of ready distributions in the background and
+// notifies the controller through a channel when a result changes, so Reconcile never waits
+// on the server API and the status is only written when the health actually changed.
+type HealthProber struct {
+	client client.Reader
+	probe  healthProbeFunc
+	// interval is read on every tick, so it can be changed while the prober runs
+	interval atomic.Int64
+	// sem bounds the number of probes in flight
+	sem chan struct{}
+
+	triggers chan types.NamespacedName
+	events   chan event.GenericEvent
+
+	mu       sync.Mutex
+	results  map[types.NamespacedName]healthProbeResult
+	inFlight map[types.NamespacedName]bool
+}
+
+func newHealthProber(reader client.Reader, probe healthProbeFunc, opts HealthProberOptions) *HealthProber {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultHealthProbeConcurrency
+	}
+	p := &HealthProber{
+		client:   reader,
+		probe:    probe,
+		sem:      make(chan struct{}, concurrency),
+		triggers: make(chan types.NamespacedName, healthProbeTriggerBuffer),
+		events:   make(chan event.GenericEvent, healthProbeEventBuffer),
+		results:  make(map[types.NamespacedName]healthProbeResult),
+		inFlight: make(map[types.NamespacedName]bool),
+	}
+	p.interval.Store(int64(opts.Interval))
+	return p
+}
+
+// SetInterval changes the interval between two probes from the next tick on.
+func (p *HealthProber) SetInterval(interval time.Duration) {
+	if interval > 0 {
+		p.interval.Store(int64(interval))
+	}
+}
+
+// Events returns the channel on which distributions with a changed result are published.
+func (p *HealthProber) Events() <-chan event.GenericEvent {
+	return p.events
+}
+
+// Result returns the last probe result of a distribution, if it was probed since it became ready.
+func (p *HealthProber) Result(key types.NamespacedName) (healthProbeResult, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	result, ok := p.results[key]
+	return result, ok
+}
+
+// Trigger requests a probe of the distribution ahead of the next tick. It never blocks; when
+// too many probes are pending, the distribution is probed on the next tick instead.
+func (p *HealthProber) Trigger(key types.NamespacedName) {
+	select {
+	case p.triggers <- key:
+	default:
+	}
+}
+
+// Forget drops the result of a distribution that is no longer ready.
+func (p *HealthProber) Forget(key types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.results, key)
+}
+
+// Start implements manager.Runnable and probes until ctx is done.
+func (p *HealthProber) Start(ctx context.Context) error {
+	interval := time.Duration(p.interval.Load())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if current := time.Duration(p.interval.Load()); current != interval {
+				interval = current
+				ticker.Reset(interval)
+			}
+			p.probeAll(ctx, &wg)
+		case key := <-p.triggers:
+			instance := &llamav1alpha1.LlamaStackDistribution{}
+			if err := p.client.Get(ctx, key, instance); err != nil {
+				log.FromContext(ctx).V(1).Info("Skipping health probe of unavailable distribution", "namespace", key.Namespace, "name", key.Name)
+				continue
+			}
+			p.probeAsync(ctx, &wg, instance)
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; only the leader updates the status.
+func (p *HealthProber) NeedLeaderElection() bool {
+	return true
+}
+
+// probeAll starts a probe of every ready distribution and drops the results of the others.
+func (p *HealthProber) probeAll(ctx context.Context, wg *sync.WaitGroup) {
+	list := &llamav1alpha1.LlamaStackDistributionList{}
+	if err := p.client.List(ctx, list); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list distributions for health probes")
+		return
+	}
+
+	probed := make(map[types.NamespacedName]bool, len(list.Items))
+	for i := range list.Items {
+		instance := &list.Items[i]
+		if !isProbeable(instance) {
+			continue
+		}
+		probed[client.ObjectKeyFromObject(instance)] = true
+		p.probeAsync(ctx, wg, instance)
+	}
+
+	p.mu.Lock()
+	for key := range p.results {
+		if !probed[key] {
+			delete(p.results, key)
+		}
+	}
+	p.mu.Unlock()
+}
+
+// probeAsync probes the distribution in a goroutine once a concurrency slot is free, unless a
+// probe of the same distribution is already running.
+func (p *HealthProber) probeAsync(ctx context.Context, wg *sync.WaitGroup, instance *llamav1alpha1.LlamaStackDistribution) {
+	key := client.ObjectKeyFromObject(instance)
+
+	p.mu.Lock()
+	if p.inFlight[key] {
+		p.mu.Unlock()
+		return
+	}
+	p.inFlight[key] = true
+	p.mu.Unlock()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			p.mu.Lock()
+			delete(p.inFlight, key)
+			p.mu.Unlock()
+		}()
+
+		select {
+		case p.sem <- struct{}{}:
+			defer func() { <-p.sem }()
+		case <-ctx.Done():
+			return
+		}
+		p.probeOne(ctx, instance)
+	}()
+}
+
+// probeOne probes a distribution and publishes it if the result changed.
+func (p *HealthProber) probeOne(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) {
+	key := client.ObjectKeyFromObject(instance)
+	logger := log.FromContext(ctx).WithValues("namespace", key.Namespace, "name", key.Name)
+	result := p.probe(ctx, instance)
+
+	p.mu.Lock()
+	previous, known := p.results[key]
+	changed := !known || !previous.equal(result)
+	p.results[key] = result
+	p.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if result.ProvidersErr != nil {
+		logger.Error(result.ProvidersErr, "failed to get provider info")
+	}
+	if result.VersionErr != nil {
+		logger.Error(result.VersionErr, "failed to get version info from API endpoint")
+	}
+	logRegisteredErrors(logger, result)
+	logger.V(1).Info("Distribution health changed, requesting status update")
+
+	select {
+	case p.events <- event.GenericEvent{Object: instance}:
+	case <-ctx.Done():
+	}
+}
+
+// isProbeable returns true if the distribution serves the API that the prober queries. During a
+// rollout the preview is probed even when the stable revision is not ready, so a fix can roll out.
+func isProbeable(instance *llamav1alpha1.LlamaStackDistribution) bool {
+	return instance.DeletionTimestamp.IsZero() &&
+		!instance.IsReconcilePaused() &&
+		(instance.Status.Phase == llamav1alpha1.LlamaStackDistributionPhaseReady || isRolloutInProgress(instance))
+}
+
+// probeHealth queries the providers, version and registered resources of the distribution, and
+// the health of the preview revision while a rollout is in progress.
+func (r *LlamaStackDistributionReconciler) probeHealth(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) healthProbeResult {
+	var result healthProbeResult
+	result.Providers, result.ProvidersErr = r.getProviderInfo(ctx, instance)
+	result.Version, result.VersionErr = r.getVersionInfo(ctx, instance)
+	result.Models = r.getRegisteredIdentifiers(ctx, instance, statusPollModels, "/v1/models")
+	result.Shields = r.getRegisteredIdentifiers(ctx, instance, statusPollShields, "/v1/shields")
+	result.VectorDBs = r.getRegisteredIdentifiers(ctx, instance, statusPollVectorDBs, "/v1/vector-dbs")
+	if isRolloutInProgress(instance) && instance.HasPorts() {
+		result.PreviewRevision = instance.Status.Rollout.UpdateRevision
+		result.PreviewErr = r.probePreview(ctx, instance)
+	}
+	return result
+}
+
+// applyHealth copies the providers and version of the distribution into its status. Without a
+// prober the server is queried inline; otherwise the last background result is used and a probe
+// is requested when there is none yet.
+func (r *LlamaStackDistributionReconciler) applyHealth(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) {
+	logger := log.FromContext(ctx)
+
+	var result healthProbeResult
+	if r.healthProber == nil {
+		result = r.probeHealth(ctx, instance)
+		if result.ProvidersErr != nil {
+			logger.Error(result.ProvidersErr, "failed to get provider info, clearing provider list")
+		}
+		if result.VersionErr != nil {
+			logger.Error(result.VersionErr, "failed to get version info from API endpoint")
+		}
+		logRegisteredErrors(logger, result)
+	} else {
+		var ok bool
+		if result, ok = r.healthProber.Result(client.ObjectKeyFromObject(instance)); !ok {
+			// Keep the current status until the first probe reports back.
+			r.healthProber.Trigger(client.ObjectKeyFromObject(instance))
+			return
+		}
+	}
+
+	if result.ProvidersErr != nil {
+		instance.Status.DistributionConfig.Providers = nil
+		SetProvidersUnknownCondition(&instance.Status, fmt.Sprintf("Failed to query providers: %v", result.ProvidersErr))
+	} else {
+		// Redact a copy of the providers, the prober keeps comparing its result with the next polls
+		providers := r.redactProviders(ctx, result.Providers)
+		trackProviderTransitions(instance.Status.DistributionConfig.Providers, providers, metav1.NewTime(metav1.Now().UTC()))
+		instance.Status.DistributionConfig.Providers = providers
+		SetProvidersHealthyCondition(&instance.Status, providers)
+	}
+	instance.Status.DistributionConfig.Models = summarizeRegistered(result.Models)
+	instance.Status.DistributionConfig.Shields = summarizeRegistered(result.Shields)
+	instance.Status.DistributionConfig.VectorDBs = summarizeRegistered(result.VectorDBs)
+	r.applyShieldsEnforced(ctx, instance, &result.Shields, "")
+
+	// Don't clear the version if we cant fetch it - keep the existing one
+	if result.VersionErr == nil {
+		instance.Status.Version.LlamaStackServerVersion = result.Version
+		logger.V(1).Info("Updated LlamaStack version from API endpoint", "version", result.Version)
+	}
+}
+
+// logRegisteredErrors logs the registered resources that could not be listed. Older servers may not
+// serve every endpoint, so these are only logged at debug level.
+func logRegisteredErrors(logger logr.Logger, result healthProbeResult) {
+	for _, query := range []registeredQuery{result.Models, result.Shields, result.VectorDBs} {
+		if query.Err != nil {
+			logger.V(1).Info("Failed to list registered resources", "error", query.Err.Error())
+		}
+	}
+}
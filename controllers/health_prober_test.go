@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newProbeTestDistribution(name string, phase llamav1alpha1.DistributionPhase) *llamav1alpha1.LlamaStackDistribution {
+	return &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "probe-ns"},
+		Status:     llamav1alpha1.LlamaStackDistributionStatus{Phase: phase},
+	}
+}
+
+func TestHealthProber(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, llamav1alpha1.AddToScheme(scheme))
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newProbeTestDistribution("ready", llamav1alpha1.LlamaStackDistributionPhaseReady),
+		newProbeTestDistribution("pending", llamav1alpha1.LlamaStackDistributionPhasePending),
+	).Build()
+
+	var (
+		mu     sync.Mutex
+		health = "OK"
+		probed []string
+	)
+	probe := func(_ context.Context, instance *llamav1alpha1.LlamaStackDistribution) healthProbeResult {
+		mu.Lock()
+		defer mu.Unlock()
+		probed = append(probed, instance.Name)
+		return healthProbeResult{
+			Providers: []llamav1alpha1.ProviderInfo{{ProviderID: "ollama", Health: llamav1alpha1.ProviderHealthStatus{Status: health}}},
+			Version:   "0.2.0",
+		}
+	}
+	prober := newHealthProber(reader, probe, HealthProberOptions{Concurrency: 1})
+	ctx := context.Background() //nolint:usetesting // the prober outlives the assertions of each step
+	readyKey := types.NamespacedName{Name: "ready", Namespace: "probe-ns"}
+
+	probeAll := func() {
+		var wg sync.WaitGroup
+		prober.probeAll(ctx, &wg)
+		wg.Wait()
+	}
+
+	t.Run("probes ready distributions and publishes the first result", func(t *testing.T) {
+		probeAll()
+		assert.Equal(t, []string{"ready"}, probed)
+		require.Len(t, prober.Events(), 1)
+		evt := <-prober.Events()
+		assert.Equal(t, "ready", evt.Object.GetName())
+
+		result, ok := prober.Result(readyKey)
+		require.True(t, ok)
+		assert.Equal(t, "0.2.0", result.Version)
+	})
+
+	t.Run("does not publish an unchanged result", func(t *testing.T) {
+		probeAll()
+		assert.Empty(t, prober.Events())
+	})
+
+	t.Run("publishes a health change", func(t *testing.T) {
+		mu.Lock()
+		health = "Error"
+		mu.Unlock()
+		probeAll()
+		require.Len(t, prober.Events(), 1)
+		<-prober.Events()
+	})
+
+	t.Run("forgets distributions that are no longer ready", func(t *testing.T) {
+		instance := &llamav1alpha1.LlamaStackDistribution{}
+		require.NoError(t, reader.Get(ctx, readyKey, instance))
+		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseFailed
+		require.NoError(t, reader.Update(ctx, instance))
+
+		probeAll()
+		_, ok := prober.Result(readyKey)
+		assert.False(t, ok)
+	})
+}
+
+func TestHealthProbeResultEqual(t *testing.T) {
+	base := healthProbeResult{Version: "0.2.0", ProvidersErr: errors.New("connection refused")}
+
+	assert.True(t, base.equal(healthProbeResult{Version: "0.2.0", ProvidersErr: errors.New("i/o timeout")}),
+		"a different error message is not a change")
+	assert.False(t, base.equal(healthProbeResult{Version: "0.2.0"}), "a recovered endpoint is a change")
+	assert.False(t, base.equal(healthProbeResult{Version: "0.3.0", ProvidersErr: base.ProvidersErr}), "a new version is a change")
+	assert.False(t, base.equal(healthProbeResult{Version: "0.2.0", ProvidersErr: base.ProvidersErr, PreviewRevision: "new"}),
+		"a probed preview is a change")
+}
@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/ingest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// vectorStoreLabel selects the ingestion Jobs of a vector store.
+	vectorStoreLabel = "llamastack.io/vectorstore"
+	// jobNameLabel is set by the Job controller on the pods of a Job.
+	jobNameLabel = "job-name"
+
+	ingestionContainerName = "ingest"
+	ingestionMountRoot     = "/ingest"
+	ingestionHashLength    = 10
+	// maxJobNameLength keeps the job-name label of the pods within the label value limit.
+	maxJobNameLength = 63
+)
+
+// ingestionJobName returns the name of the Job ingesting the current spec in the current vector
+// store. A change of either results in a new Job, so the ingestion runs again.
+func ingestionJobName(store *llamav1alpha1.LlamaStackVectorStore) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Ingestion     *llamav1alpha1.IngestionSpec `json:"ingestion"`
+		VectorStoreID string                       `json:"vectorStoreId"`
+	}{store.Spec.Ingestion, store.Status.VectorStoreID})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ingestion spec: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	suffix := "-ingest-" + hex.EncodeToString(sum[:])[:ingestionHashLength]
+
+	name := store.Name
+	if len(name)+len(suffix) > maxJobNameLength {
+		name = name[:maxJobNameLength-len(suffix)]
+	}
+	return name + suffix, nil
+}
+
+// ensureIngestion starts the ingestion Job of the current spec if needed and copies its progress to the status.
+func (r *LlamaStackVectorStoreReconciler) ensureIngestion(
+	ctx context.Context,
+	store *llamav1alpha1.LlamaStackVectorStore,
+	distribution *llamav1alpha1.LlamaStackDistribution,
+) error {
+	jobName, err := ingestionJobName(store)
+	if err != nil {
+		return err
+	}
+
+	// A finished ingestion is not run again, even once its Job is deleted.
+	if current := store.Status.Ingestion; current != nil && current.JobName == jobName &&
+		(current.Phase == llamav1alpha1.IngestionPhaseSucceeded || current.Phase == llamav1alpha1.IngestionPhaseFailed) {
+		return nil
+	}
+
+	job := &batchv1.Job{}
+	err = r.Get(ctx, types.NamespacedName{Name: jobName, Namespace: store.Namespace}, job)
+	if k8serrors.IsNotFound(err) {
+		return r.startIngestion(ctx, store, distribution, jobName)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch ingestion Job %s: %w", jobName, err)
+	}
+
+	status := &llamav1alpha1.IngestionStatus{
+		JobName:   jobName,
+		Phase:     ingestionPhase(job),
+		StartTime: job.Status.StartTime,
+	}
+	switch status.Phase {
+	case llamav1alpha1.IngestionPhaseSucceeded, llamav1alpha1.IngestionPhaseFailed:
+		r.readIngestionResult(ctx, job, status)
+	case llamav1alpha1.IngestionPhasePending, llamav1alpha1.IngestionPhaseRunning:
+	}
+
+	if previous := store.Status.Ingestion; previous == nil || previous.Phase != status.Phase {
+		switch status.Phase {
+		case llamav1alpha1.IngestionPhaseSucceeded:
+			r.Recorder.Eventf(store, corev1.EventTypeNormal, EventReasonIngestionSucceeded,
+				"Ingested %d documents in %d chunks", status.Documents, status.Chunks)
+		case llamav1alpha1.IngestionPhaseFailed:
+			r.Recorder.Eventf(store, corev1.EventTypeWarning, EventReasonIngestionFailed,
+				"Ingestion Job %s failed: %s", jobName, status.Message)
+		case llamav1alpha1.IngestionPhasePending, llamav1alpha1.IngestionPhaseRunning:
+		}
+	}
+	store.Status.Ingestion = status
+	return nil
+}
+
+// startIngestion deletes the Jobs of previous ingestions and creates the Job of the current one.
+func (r *LlamaStackVectorStoreReconciler) startIngestion(
+	ctx context.Context,
+	store *llamav1alpha1.LlamaStackVectorStore,
+	distribution *llamav1alpha1.LlamaStackDistribution,
+	jobName string,
+) error {
+	logger := log.FromContext(ctx)
+
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(store.Namespace), client.MatchingLabels{vectorStoreLabel: store.Name}); err != nil {
+		return fmt.Errorf("failed to list ingestion Jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		previous := &jobs.Items[i]
+		if previous.Name == jobName || !metav1.IsControlledBy(previous, store) {
+			continue
+		}
+		if err := r.Delete(ctx, previous, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete previous ingestion Job %s: %w", previous.Name, err)
+		}
+		logger.Info("Deleted previous ingestion Job", "job", previous.Name)
+	}
+
+	job := buildIngestionJob(store, distribution, jobName, r.ingestionImage)
+	if err := controllerutil.SetControllerReference(store, job, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on ingestion Job: %w", err)
+	}
+	if err := r.Create(ctx, job); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create ingestion Job %s: %w", jobName, err)
+	}
+
+	logger.Info("Started ingestion", "job", jobName)
+	r.Recorder.Eventf(store, corev1.EventTypeNormal, EventReasonIngestionStarted, "Created ingestion Job %s", jobName)
+	store.Status.Ingestion = &llamav1alpha1.IngestionStatus{JobName: jobName, Phase: llamav1alpha1.IngestionPhasePending}
+	return nil
+}
+
+// ingestionPhase returns the phase of the ingestion from the state of its Job.
+func ingestionPhase(job *batchv1.Job) llamav1alpha1.IngestionPhase {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type { //nolint:exhaustive // the other conditions do not end the Job
+		case batchv1.JobComplete:
+			return llamav1alpha1.IngestionPhaseSucceeded
+		case batchv1.JobFailed:
+			return llamav1alpha1.IngestionPhaseFailed
+		}
+	}
+	if job.Status.Active > 0 {
+		return llamav1alpha1.IngestionPhaseRunning
+	}
+	return llamav1alpha1.IngestionPhasePending
+}
+
+// readIngestionResult copies the result written by the ingestion container as its termination
+// message, and the completion time of the Job, to the status.
+func (r *LlamaStackVectorStoreReconciler) readIngestionResult(ctx context.Context, job *batchv1.Job, status *llamav1alpha1.IngestionStatus) {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			completion := condition.LastTransitionTime
+			status.CompletionTime = &completion
+			status.Message = condition.Message
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.apiReader.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{jobNameLabel: job.Name}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list ingestion pods", "job", job.Name)
+		return
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			terminated := container.State.Terminated
+			if container.Name != ingestionContainerName || terminated == nil || terminated.Message == "" {
+				continue
+			}
+			result, err := ingest.ParseResult(terminated.Message)
+			if err != nil {
+				log.FromContext(ctx).V(1).Info("Ignoring invalid ingestion result", "pod", pod.Name, "error", err.Error())
+				continue
+			}
+			status.Documents = result.Documents
+			status.FailedDocuments = result.FailedDocuments
+			status.Chunks = result.Chunks
+			if result.Message != "" {
+				status.Message = result.Message
+			}
+			return
+		}
+	}
+}
+
+// buildIngestionJob returns the Job running the ingest command of the operator image against the
+// vector store. It is never retried, since a retry would insert the ingested chunks twice.
+func buildIngestionJob(
+	store *llamav1alpha1.LlamaStackVectorStore,
+	distribution *llamav1alpha1.LlamaStackDistribution,
+	jobName, image string,
+) *batchv1.Job {
+	ingestion := store.Spec.Ingestion
+	args := []string{
+		ingest.Command,
+		fmt.Sprintf("--%s=%s", ingest.FlagServerURL, serverURL(distribution, "").String()),
+		fmt.Sprintf("--%s=%s", ingest.FlagVectorStoreID, store.Status.VectorStoreID),
+		fmt.Sprintf("--%s=%d", ingest.FlagChunkSize, ingestion.ChunkSize),
+		fmt.Sprintf("--%s=%d", ingest.FlagChunkOverlap, ingestion.ChunkOverlap),
+	}
+
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	for i, source := range ingestion.Sources {
+		name := fmt.Sprintf("source-%d", i)
+		mountPath := path.Join(ingestionMountRoot, name)
+
+		switch {
+		case source.ConfigMap != nil:
+			configMap := &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: source.ConfigMap.Name}}
+			for _, key := range source.ConfigMap.Keys {
+				configMap.Items = append(configMap.Items, corev1.KeyToPath{Key: key, Path: key})
+			}
+			volumes = append(volumes, corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{ConfigMap: configMap}})
+			mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: mountPath, ReadOnly: true})
+			args = append(args, fmt.Sprintf("--%s=%s", ingest.FlagPath, mountPath))
+		case source.PersistentVolumeClaim != nil:
+			volumes = append(volumes, corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: source.PersistentVolumeClaim.ClaimName, ReadOnly: true},
+			}})
+			mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: mountPath, ReadOnly: true})
+			args = append(args, fmt.Sprintf("--%s=%s", ingest.FlagPath, path.Join(mountPath, source.PersistentVolumeClaim.Path)))
+		default:
+			for _, u := range source.URLs {
+				args = append(args, fmt.Sprintf("--%s=%s", ingest.FlagURL, u))
+			}
+		}
+	}
+
+	labels := map[string]string{
+		vectorStoreLabel: store.Name,
+		// Allowed by the NetworkPolicy of the distribution.
+		"app.kubernetes.io/part-of":    "llama-stack",
+		"app.kubernetes.io/managed-by": "llama-stack-operator",
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: store.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:                corev1.RestartPolicyNever,
+					AutomountServiceAccountToken: ptr.To(false),
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   ptr.To(true),
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					Containers: []corev1.Container{{
+						Name:         ingestionContainerName,
+						Image:        image,
+						Command:      []string{"/manager"},
+						Args:         args,
+						VolumeMounts: mounts,
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("50m"),
+								corev1.ResourceMemory: resource.MustParse("64Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("512Mi"),
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							ReadOnlyRootFilesystem:   ptr.To(true),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+					}},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
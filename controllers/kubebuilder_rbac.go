@@ -5,6 +5,37 @@ package controllers
 //+kubebuilder:rbac:groups=llamastack.io,resources=llamastackdistributions/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=llamastack.io,resources=llamastackdistributions/finalizers,verbs=update
 
+// LlamaStackModel CRD permissions
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackmodels,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackmodels/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackmodels/finalizers,verbs=update
+
+// LlamaStackVectorStore CRD permissions
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackvectorstores,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackvectorstores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackvectorstores/finalizers,verbs=update
+
+// LlamaStackToolGroup CRD permissions
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastacktoolgroups,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastacktoolgroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastacktoolgroups/finalizers,verbs=update
+
+// LlamaStackShield CRD permissions
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackshields,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackshields/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackshields/finalizers,verbs=update
+
+// LlamaStackOperatorConfig CRD permissions - controller migrates the operator ConfigMap to the singleton config
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackoperatorconfigs,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackoperatorconfigs/status,verbs=get;update;patch
+
+// LlamaStackDistributionDefaults CRD permissions - controller merges the namespace defaults under each distribution
+//+kubebuilder:rbac:groups=llamastack.io,resources=llamastackdistributiondefaults,verbs=get;list;watch
+
+// Job permissions - controller runs the document ingestion of vector stores as Jobs and reads the result from their pods
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+
 // Deployment permissions - controller creates and manages deployments
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 
@@ -23,10 +54,20 @@ package controllers
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=use
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=anyuid,verbs=use
 
-//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create
+// PersistentVolumeClaim permissions - controller adopts existing claims and deletes the claim once storage is removed from the distribution
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 
-// ConfigMap permissions - controller reads user configmaps and manages operator config configmaps
-//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+// ConfigMap permissions - controller reads user configmaps and manages operator config and telemetry run config configmaps
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
 // NetworkPolicy permissions - controller creates and manages network policies
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// Secret permissions - controller reads the export headers of spec.server.telemetry for its reconcile spans
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Event permissions - controller publishes Events on the resources it reconciles
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Prometheus Operator permissions - controller manages ServiceMonitors and PrometheusRules when monitoring is enabled
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create;update;patch;delete
@@ -25,8 +25,8 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -35,6 +35,7 @@ import (
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/cluster"
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/featureflags"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/telemetry"
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -43,6 +44,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +53,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
 
@@ -82,11 +85,25 @@ const (
 type LlamaStackDistributionReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
-	// Feature flags
-	EnableNetworkPolicy bool
+	// settings are the settings of the LlamaStackOperatorConfig, swapped when it changes
+	settings atomic.Pointer[OperatorSettings]
+	// watchOperatorConfig applies the changes of the LlamaStackOperatorConfig while running
+	watchOperatorConfig bool
+	// operatorConfigEvents publishes the distributions to reconcile after a feature flag change
+	operatorConfigEvents chan event.GenericEvent
 	// Cluster info
 	ClusterInfo *cluster.ClusterInfo
-	httpClient  *http.Client
+	// Recorder publishes Events on the reconciled instances
+	Recorder   record.EventRecorder
+	httpClient *http.Client
+	// tracers export reconcile spans to the collectors configured on the instances
+	tracers *telemetry.TracerProviders
+	// healthProberOptions configure the background health prober created in SetupWithManager
+	healthProberOptions HealthProberOptions
+	// healthProber polls the server API of ready instances, inline polling is used when nil
+	healthProber *HealthProber
+	// specChanges keeps the spec paths changed by updates until they are recorded in status.history
+	specChanges specChangeTracker
 }
 
 // hasUserConfigMap checks if the instance has a valid UserConfig with ConfigMapName.
@@ -172,14 +189,37 @@ func (r *LlamaStackDistributionReconciler) Reconcile(ctx context.Context, req ct
 
 	if instance == nil {
 		logger.Info("LlamaStackDistribution resource not found, skipping reconciliation")
+		deleteDistributionMetrics(req.Namespace, req.Name)
+		r.specChanges.forget(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	ctx, span := r.startSpan(ctx, instance, "Reconcile")
+	result, err := r.reconcileInstance(ctx, instance)
+	endSpan(span, err)
+	return result, err
+}
+
+// reconcileInstance reconciles an existing LlamaStackDistribution instance.
+func (r *LlamaStackDistributionReconciler) reconcileInstance(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Skip all mutations while reconciliation is paused, e.g. for manual debugging
+	if instance.IsReconcilePaused() {
+		logger.Info("LlamaStackDistribution reconciliation is paused, skipping reconciliation")
+		return ctrl.Result{}, r.updatePausedStatus(ctx, instance)
+	}
+
 	// Reconcile all resources, storing the error for later.
-	reconcileErr := r.reconcileResources(ctx, instance)
+	resourcesCtx, resourcesSpan := r.startSpan(ctx, instance, "ReconcileResources")
+	reconcileErr := r.reconcileResources(resourcesCtx, instance)
+	endSpan(resourcesSpan, reconcileErr)
 
 	// Update the status, passing in any reconciliation error.
-	if statusUpdateErr := r.updateStatus(ctx, instance, reconcileErr); statusUpdateErr != nil {
+	statusCtx, statusSpan := r.startSpan(ctx, instance, "UpdateStatus")
+	statusUpdateErr := r.updateStatus(statusCtx, instance, reconcileErr)
+	endSpan(statusSpan, statusUpdateErr)
+	if statusUpdateErr != nil {
 		// Log the status update error, but prioritize the reconciliation error for return.
 		logger.Error(statusUpdateErr, "failed to update status")
 		if reconcileErr != nil {
@@ -198,8 +238,14 @@ func (r *LlamaStackDistributionReconciler) Reconcile(ctx context.Context, req ct
 		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	// Rollout steps are gated on health checks and pauses, so keep re-evaluating them
+	if isRolloutInProgress(instance) {
+		return ctrl.Result{RequeueAfter: rolloutRequeueInterval}, nil
+	}
+
 	logger.Info("Successfully reconciled LlamaStackDistribution")
-	return ctrl.Result{}, nil
+	// Reconcile again when the next schedule entry fires to apply its replicas
+	return ctrl.Result{RequeueAfter: scheduleRequeueAfter(instance)}, nil
 }
 
 // fetchInstance retrieves the LlamaStackDistribution instance.
@@ -226,8 +272,8 @@ func (r *LlamaStackDistributionReconciler) determineKindsToExclude(instance *lla
 		kinds = append(kinds, "PersistentVolumeClaim")
 	}
 
-	// Exclude NetworkPolicy if the feature is disabled
-	if !r.EnableNetworkPolicy {
+	// Exclude NetworkPolicy if the feature is disabled and the distribution does not opt in
+	if !instance.IsNetworkPolicyEnabled(r.Settings().EnableNetworkPolicy) {
 		kinds = append(kinds, "NetworkPolicy")
 	}
 
@@ -236,6 +282,11 @@ func (r *LlamaStackDistributionReconciler) determineKindsToExclude(instance *lla
 		kinds = append(kinds, "Service")
 	}
 
+	// Exclude monitoring resources unless requested and the Prometheus Operator CRDs are installed
+	if !instance.IsMonitoringEnabled() || !r.hasMonitoringCRDs() {
+		kinds = append(kinds, deploy.ServiceMonitorKind, deploy.PrometheusRuleKind)
+	}
+
 	return kinds
 }
 
@@ -253,68 +304,45 @@ func (r *LlamaStackDistributionReconciler) reconcileAllManifestResources(ctx con
 		return fmt.Errorf("failed to render manifests: %w", err)
 	}
 
+	r.warnIfMonitoringUnavailable(instance)
 	kindsToExclude := r.determineKindsToExclude(instance)
 	filteredResMap, err := deploy.FilterExcludeKinds(resMap, kindsToExclude)
 	if err != nil {
 		return fmt.Errorf("failed to filter manifests: %w", err)
 	}
 
-	// Delete excluded resources that might exist from previous reconciliations
-	if err := r.deleteExcludedResources(ctx, instance, kindsToExclude); err != nil {
-		return fmt.Errorf("failed to delete excluded resources: %w", err)
-	}
-
-	// Apply resources to cluster
-	if err := deploy.ApplyResources(ctx, r.Client, r.Scheme, instance, filteredResMap); err != nil {
-		return fmt.Errorf("failed to apply manifests: %w", err)
-	}
-
-	return nil
-}
-
-// deleteExcludedResources deletes resources that are excluded from the current reconciliation
-// but might exist from previous reconciliations.
-func (r *LlamaStackDistributionReconciler) deleteExcludedResources(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution, kindsToExclude []string) error {
-	logger := log.FromContext(ctx)
-
-	if slices.Contains(kindsToExclude, "NetworkPolicy") {
-		if err := r.deleteNetworkPolicyIfExists(ctx, instance); err != nil {
-			logger.Error(err, "Failed to delete NetworkPolicy")
-			return err
-		}
+	// Prune excluded resources that might exist from previous reconciliations
+	if err := r.pruneExcludedResources(ctx, instance, resMap, kindsToExclude); err != nil {
+		return fmt.Errorf("failed to prune excluded resources: %w", err)
 	}
 
-	return nil
-}
-
-// deleteNetworkPolicyIfExists deletes the NetworkPolicy if it exists.
-func (r *LlamaStackDistributionReconciler) deleteNetworkPolicyIfExists(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) error {
-	logger := log.FromContext(ctx)
-
-	networkPolicy := &networkingv1.NetworkPolicy{}
-	networkPolicyName := instance.Name + "-network-policy"
-	key := types.NamespacedName{Name: networkPolicyName, Namespace: instance.Namespace}
+	// The inventory lists the full render, including the stable Deployment the rollout holds back
+	rendered := buildInventory(filteredResMap)
 
-	err := r.Get(ctx, key, networkPolicy)
+	// Hold back the new revision while a Canary or BlueGreen rollout is in progress
+	filteredResMap, err = r.reconcileRollout(ctx, instance, filteredResMap)
 	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			// NetworkPolicy doesn't exist, nothing to delete
-			return nil
-		}
-		return fmt.Errorf("failed to get NetworkPolicy: %w", err)
+		return fmt.Errorf("failed to reconcile rollout: %w", err)
 	}
 
-	// Check if this NetworkPolicy is owned by our instance
-	if !metav1.IsControlledBy(networkPolicy, instance) {
-		logger.V(1).Info("NetworkPolicy not owned by this instance, skipping deletion",
-			"networkPolicy", networkPolicyName)
-		return nil
+	// Apply resources to cluster
+	applyResult, err := deploy.ApplyResourcesWithOptions(ctx, r.Client, r.Scheme, r.Recorder, instance, filteredResMap, deploy.ApplyOptions{
+		DriftPolicy:    instance.Spec.DriftPolicy,
+		AdoptionPolicy: instance.Spec.AdoptionPolicy,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply manifests: %w", err)
 	}
+	r.recordConfigMapRestarts(instance, applyResult.Patched, manifestCtx)
+	r.reportDrift(instance, applyResult.Drifts)
+	SetResourcesOwnedCondition(&instance.Status, applyResult.Skipped)
 
-	logger.Info("Deleting NetworkPolicy as feature is disabled", "networkPolicy", networkPolicyName)
-	if err := r.Delete(ctx, networkPolicy); err != nil {
-		return fmt.Errorf("failed to delete NetworkPolicy: %w", err)
+	// Prune the resources of the previous reconciliation that are no longer rendered
+	inventory, err := r.pruneInventory(ctx, instance, rendered, kindsToExclude)
+	if err != nil {
+		return fmt.Errorf("failed to prune inventory: %w", err)
 	}
+	instance.Status.Inventory = inventory
 
 	return nil
 }
@@ -362,18 +390,37 @@ func (r *LlamaStackDistributionReconciler) buildManifestContext(ctx context.Cont
 		ConfigMapHash: configMapHash,
 		CABundleHash:  caBundleHash,
 		PodSpec:       podSpecMap,
+		PodLabels:     podLabels(instance),
 	}, nil
 }
 
+// podLabels returns the labels added to the server pods.
+func podLabels(instance *llamav1alpha1.LlamaStackDistribution) map[string]string {
+	if instance.Spec.Server.PodOverrides == nil {
+		return nil
+	}
+	return instance.Spec.Server.PodOverrides.Labels
+}
+
 // reconcileResources reconciles all resources for the LlamaStackDistribution instance.
 func (r *LlamaStackDistributionReconciler) reconcileResources(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) error {
+	// Render the spec with the namespace defaults merged under it
+	effective, err := r.effectiveDistribution(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if effective != instance {
+		// Keep the status recorded while reconciling the effective distribution, e.g. the rollout progress
+		defer func() { instance.Status = effective.Status }()
+	}
+
 	// Reconcile ConfigMaps first
-	if err := r.reconcileConfigMaps(ctx, instance); err != nil {
+	if err := r.reconcileConfigMaps(ctx, effective); err != nil {
 		return err
 	}
 
 	// Reconcile all manifest-based resources including Deployment: PVC, ServiceAccount, Service, NetworkPolicy, Deployment
-	if err := r.reconcileAllManifestResources(ctx, instance); err != nil {
+	if err := r.reconcileAllManifestResources(ctx, effective); err != nil {
 		return err
 	}
 
@@ -391,10 +438,16 @@ func (r *LlamaStackDistributionReconciler) reconcileConfigMaps(ctx context.Conte
 	// Reconcile the CA bundle ConfigMap if specified
 	if r.hasCABundleConfigMap(instance) {
 		if err := r.reconcileCABundleConfigMap(ctx, instance); err != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, EventReasonCABundleInvalid, err.Error())
 			return fmt.Errorf("failed to reconcile CA bundle ConfigMap: %w", err)
 		}
 	}
 
+	// Merge the telemetry provider into the run.yaml of the user ConfigMap
+	if err := r.reconcileTelemetryRunConfig(ctx, instance); err != nil {
+		return fmt.Errorf("failed to reconcile telemetry run config: %w", err)
+	}
+
 	return nil
 }
 
@@ -405,7 +458,41 @@ func (r *LlamaStackDistributionReconciler) SetupWithManager(ctx context.Context,
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	// Flush the reconcile spans when the manager stops
+	if r.tracers != nil {
+		if err := mgr.Add(r.tracers); err != nil {
+			return fmt.Errorf("failed to add tracer providers to manager: %w", err)
+		}
+	}
+
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr)
+
+	// Poll the server API in the background and reconcile when the health changes
+	if r.healthProberOptions.Interval > 0 {
+		r.healthProber = newHealthProber(mgr.GetClient(), r.probeHealth, r.healthProberOptions)
+		r.healthProber.SetInterval(r.healthProbeInterval())
+		if err := mgr.Add(r.healthProber); err != nil {
+			return fmt.Errorf("failed to add health prober to manager: %w", err)
+		}
+		controllerBuilder = controllerBuilder.WatchesRawSource(
+			&source.Channel{Source: r.healthProber.Events()},
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+
+	// Apply the changes of the LlamaStackOperatorConfig without a restart
+	if r.watchOperatorConfig {
+		configEvents, err := r.setupOperatorConfigWatch(mgr)
+		if err != nil {
+			return err
+		}
+		controllerBuilder = controllerBuilder.WatchesRawSource(
+			&source.Channel{Source: configEvents},
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+
+	return controllerBuilder.
 		For(&llamav1alpha1.LlamaStackDistribution{}, builder.WithPredicates(predicate.Funcs{
 			UpdateFunc: r.llamaStackUpdatePredicate(mgr),
 		})).
@@ -413,6 +500,16 @@ func (r *LlamaStackDistributionReconciler) SetupWithManager(ctx context.Context,
 		Owns(&corev1.Service{}).
 		Owns(&networkingv1.NetworkPolicy{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
+		Watches(
+			&llamav1alpha1.LlamaStackDistributionDefaults{},
+			handler.EnqueueRequestsFromMapFunc(r.findDistributionsForDefaults),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&llamav1alpha1.LlamaStackShield{},
+			handler.EnqueueRequestsFromMapFunc(r.findDistributionForShield),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(r.findLlamaStackDistributionsForConfigMap),
@@ -509,15 +606,7 @@ func (r *LlamaStackDistributionReconciler) llamaStackUpdatePredicate(mgr ctrl.Ma
 		newObjCopy := newObj.DeepCopy()
 
 		// Compare only spec, ignoring metadata and status
-		if diff := cmp.Diff(oldObjCopy.Spec, newObjCopy.Spec); diff != "" {
-			logger := mgr.GetLogger().WithValues("namespace", newObjCopy.Namespace, "name", newObjCopy.Name)
-			logger.Info("LlamaStackDistribution CR spec changed")
-			// Note that both the logger and fmt.Printf could appear entangled in the output
-			// but there is no simple way to avoid this (forcing the logger to flush its output).
-			// When the logger is used to print the diff the output is hard to read,
-			// fmt.Printf is better for readability.
-			fmt.Printf("%s\n", diff)
-		}
+		r.auditSpecChange(mgr.GetLogger(), oldObjCopy, newObjCopy)
 
 		return true
 	}
@@ -691,8 +780,8 @@ func (r *LlamaStackDistributionReconciler) manuallyCheckConfigMapReference(confi
 		}
 	}
 
-	// no LlamaStackDistribution found that references the ConfigMap
-	return false
+	// Check CA bundle ConfigMap references inherited from the namespace defaults
+	return len(r.findDistributionsForDefaultsCABundle(context.Background(), configMap)) > 0
 }
 
 // findLlamaStackDistributionsForConfigMap maps ConfigMap changes to LlamaStackDistribution reconcile requests.
@@ -704,6 +793,9 @@ func (r *LlamaStackDistributionReconciler) findLlamaStackDistributionsForConfigM
 		attachedLlamaStacks = r.performManualSearch(ctx, configMap)
 	}
 
+	// Add the distributions mounting the ConfigMap as the CA bundle of their namespace defaults
+	attachedLlamaStacks.Items = append(attachedLlamaStacks.Items, r.findDistributionsForDefaultsCABundle(ctx, configMap)...)
+
 	// Convert to reconcile requests
 	requests := r.convertToReconcileRequests(attachedLlamaStacks)
 
@@ -817,6 +909,11 @@ func (r *LlamaStackDistributionReconciler) convertToReconcileRequests(attachedLl
 
 // getServerURL returns the URL for the LlamaStack server.
 func (r *LlamaStackDistributionReconciler) getServerURL(instance *llamav1alpha1.LlamaStackDistribution, path string) *url.URL {
+	return serverURL(instance, path)
+}
+
+// serverURL returns the in-cluster URL of the LlamaStack server of the instance.
+func serverURL(instance *llamav1alpha1.LlamaStackDistribution, path string) *url.URL {
 	serviceName := deploy.GetServiceName(instance)
 	port := deploy.GetServicePort(instance)
 
@@ -827,11 +924,28 @@ func (r *LlamaStackDistributionReconciler) getServerURL(instance *llamav1alpha1.
 	}
 }
 
+// getPreviewServerURL returns the URL for the LlamaStack server running the preview revision.
+func (r *LlamaStackDistributionReconciler) getPreviewServerURL(instance *llamav1alpha1.LlamaStackDistribution, path string) *url.URL {
+	port := deploy.GetServicePort(instance)
+
+	return &url.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s.%s.svc.cluster.local:%d", deploy.GetPreviewServiceName(instance), instance.Namespace, port),
+		Path:   path,
+	}
+}
+
 // getProviderInfo makes an HTTP request to the providers endpoint.
 func (r *LlamaStackDistributionReconciler) getProviderInfo(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) ([]llamav1alpha1.ProviderInfo, error) {
-	u := r.getServerURL(instance, "/v1/providers")
+	start := time.Now()
+	providers, err := r.queryProviders(ctx, r.getServerURL(instance, "/v1/providers").String())
+	observeStatusPoll(instance, statusPollProviders, start, err)
+	return providers, err
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+// queryProviders makes an HTTP request to the given providers endpoint.
+func (r *LlamaStackDistributionReconciler) queryProviders(ctx context.Context, endpoint string) ([]llamav1alpha1.ProviderInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create providers request: %w", err)
 	}
@@ -863,9 +977,15 @@ func (r *LlamaStackDistributionReconciler) getProviderInfo(ctx context.Context,
 
 // getVersionInfo makes an HTTP request to the version endpoint.
 func (r *LlamaStackDistributionReconciler) getVersionInfo(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) (string, error) {
-	u := r.getServerURL(instance, "/v1/version")
+	start := time.Now()
+	version, err := r.queryVersion(ctx, r.getServerURL(instance, "/v1/version").String())
+	observeStatusPoll(instance, statusPollVersion, start, err)
+	return version, err
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+// queryVersion makes an HTTP request to the given version endpoint.
+func (r *LlamaStackDistributionReconciler) queryVersion(ctx context.Context, endpoint string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create version request: %w", err)
 	}
@@ -903,6 +1023,25 @@ func (r *LlamaStackDistributionReconciler) updateStatus(ctx context.Context, ins
 		instance.Status.Version.OperatorVersion = os.Getenv("OPERATOR_VERSION")
 	}
 
+	previousPhase := instance.Status.Phase
+	previousProviders := instance.Status.DistributionConfig.Providers
+	var previousShieldsEnforced metav1.ConditionStatus
+	if condition := GetCondition(&instance.Status, ConditionTypeShieldsEnforced); condition != nil {
+		previousShieldsEnforced = condition.Status
+	}
+
+	// Reconciliation is running again after having been paused
+	if IsConditionTrue(&instance.Status, ConditionTypeReconcilePaused) {
+		SetReconcilePausedCondition(&instance.Status, false)
+	}
+
+	recordGeneration(instance, reconcileErr, metav1.NewTime(metav1.Now().UTC()),
+		r.specChanges.take(client.ObjectKeyFromObject(instance), instance.Generation))
+	updateScheduleStatus(instance)
+	if instance.Status.Schedule != nil && instance.Status.Schedule.Message != "" {
+		logger.Info("Ignoring invalid schedule entries", "message", instance.Status.Schedule.Message)
+	}
+
 	// A reconciliation error is the highest priority. It overrides all other status checks.
 	if reconcileErr != nil {
 		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseFailed
@@ -921,28 +1060,26 @@ func (r *LlamaStackDistributionReconciler) updateStatus(ctx context.Context, ins
 		if deploymentReady {
 			instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseReady
 
-			providers, err := r.getProviderInfo(ctx, instance)
-			if err != nil {
-				logger.Error(err, "failed to get provider info, clearing provider list")
-				instance.Status.DistributionConfig.Providers = nil
-			} else {
-				instance.Status.DistributionConfig.Providers = providers
-			}
-
-			version, err := r.getVersionInfo(ctx, instance)
-			if err != nil {
-				logger.Error(err, "failed to get version info from API endpoint")
-				// Don't clear the version if we cant fetch it - keep the existing one
-			} else {
-				instance.Status.Version.LlamaStackServerVersion = version
-				logger.V(1).Info("Updated LlamaStack version from API endpoint", "version", version)
-			}
+			r.applyHealth(ctx, instance)
 
 			SetHealthCheckCondition(&instance.Status, true, MessageHealthCheckPassed)
 		} else {
 			// If not ready, health can't be checked. Set condition appropriately.
-			SetHealthCheckCondition(&instance.Status, false, "Deployment not ready")
+			healthMessage := "Deployment not ready"
+			if instance.Status.Phase == llamav1alpha1.LlamaStackDistributionPhaseSuspended {
+				healthMessage = MessageDeploymentSuspended
+			}
+			SetHealthCheckCondition(&instance.Status, false, healthMessage)
+			SetProvidersUnknownCondition(&instance.Status, healthMessage)
 			instance.Status.DistributionConfig.Providers = nil // Clear providers
+			instance.Status.DistributionConfig.Models = nil
+			instance.Status.DistributionConfig.Shields = nil
+			instance.Status.DistributionConfig.VectorDBs = nil
+			r.applyShieldsEnforced(ctx, instance, nil, healthMessage)
+			// A rollout in progress keeps using the probes of its preview revision.
+			if r.healthProber != nil && !isRolloutInProgress(instance) {
+				r.healthProber.Forget(client.ObjectKeyFromObject(instance))
+			}
 		}
 	}
 
@@ -951,10 +1088,33 @@ func (r *LlamaStackDistributionReconciler) updateStatus(ctx context.Context, ins
 	if err := r.Status().Update(ctx, instance); err != nil {
 		return fmt.Errorf("failed to update status: %w", err)
 	}
+	r.recordPhaseChange(instance, previousPhase)
+	r.recordProviderHealthChanges(instance, previousProviders)
+	r.recordShieldsEnforcedChange(instance, previousShieldsEnforced)
+	recordDistributionMetrics(instance, deploy.GetDesiredReplicas(instance))
 
 	return nil
 }
 
+// updatePausedStatus reports that reconciliation is paused without touching any managed resource.
+func (r *LlamaStackDistributionReconciler) updatePausedStatus(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) error {
+	if instance.Status.Phase == llamav1alpha1.LlamaStackDistributionPhasePaused &&
+		IsConditionTrue(&instance.Status, ConditionTypeReconcilePaused) {
+		recordDistributionMetrics(instance, deploy.GetDesiredReplicas(instance))
+		return nil
+	}
+
+	previousPhase := instance.Status.Phase
+	instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhasePaused
+	SetReconcilePausedCondition(&instance.Status, true)
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	r.recordPhaseChange(instance, previousPhase)
+	recordDistributionMetrics(instance, deploy.GetDesiredReplicas(instance))
+	return nil
+}
+
 func (r *LlamaStackDistributionReconciler) updateDeploymentStatus(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) (bool, error) {
 	deployment := &appsv1.Deployment{}
 	deploymentErr := r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, deployment)
@@ -963,28 +1123,45 @@ func (r *LlamaStackDistributionReconciler) updateDeploymentStatus(ctx context.Co
 	}
 
 	deploymentReady := false
+	desiredReplicas := deploy.GetDesiredReplicas(instance)
+
+	readyReplicas := deployment.Status.ReadyReplicas
+	if isRolloutInProgress(instance) {
+		// Pods of the new revision serve traffic too while the rollout is in progress
+		readyReplicas += r.getPreviewReadyReplicas(ctx, instance)
+	}
 
 	switch {
+	case desiredReplicas == 0 && (instance.Spec.Suspend || instance.Status.Schedule != nil):
+		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseSuspended
+		deploymentMessage := MessageDeploymentSuspended
+		if !instance.Spec.Suspend {
+			deploymentMessage = fmt.Sprintf("Deployment is scaled to zero by schedule entry %s", instance.Status.Schedule.ActiveEntry)
+		}
+		if readyReplicas > 0 {
+			deploymentMessage = fmt.Sprintf("Deployment is suspending: %d replicas still ready", readyReplicas)
+		}
+		SetDeploymentSuspendedCondition(&instance.Status, deploymentMessage)
 	case deploymentErr != nil: // This case covers when the deployment is not found
 		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhasePending
 		SetDeploymentReadyCondition(&instance.Status, false, MessageDeploymentPending)
-	case deployment.Status.ReadyReplicas == 0:
+	case readyReplicas == 0:
 		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseInitializing
 		SetDeploymentReadyCondition(&instance.Status, false, MessageDeploymentPending)
-	case deployment.Status.ReadyReplicas < instance.Spec.Replicas:
+	case readyReplicas < desiredReplicas:
 		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseInitializing
-		deploymentMessage := fmt.Sprintf("Deployment is scaling: %d/%d replicas ready", deployment.Status.ReadyReplicas, instance.Spec.Replicas)
+		deploymentMessage := fmt.Sprintf("Deployment is scaling: %d/%d replicas ready", readyReplicas, desiredReplicas)
 		SetDeploymentReadyCondition(&instance.Status, false, deploymentMessage)
-	case deployment.Status.ReadyReplicas > instance.Spec.Replicas:
+	case readyReplicas > desiredReplicas && !isRolloutInProgress(instance):
 		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseInitializing
-		deploymentMessage := fmt.Sprintf("Deployment is scaling down: %d/%d replicas ready", deployment.Status.ReadyReplicas, instance.Spec.Replicas)
+		deploymentMessage := fmt.Sprintf("Deployment is scaling down: %d/%d replicas ready", readyReplicas, desiredReplicas)
 		SetDeploymentReadyCondition(&instance.Status, false, deploymentMessage)
 	default:
 		instance.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseReady
 		deploymentReady = true
 		SetDeploymentReadyCondition(&instance.Status, true, MessageDeploymentReady)
 	}
-	instance.Status.AvailableReplicas = deployment.Status.ReadyReplicas
+	instance.Status.AvailableReplicas = readyReplicas
 	return deploymentReady, nil
 }
 
@@ -1036,6 +1213,8 @@ func (r *LlamaStackDistributionReconciler) updateDistributionConfig(instance *ll
 		activeDistribution = instance.Spec.Server.Distribution.Name
 	} else if instance.Spec.Server.Distribution.Image != "" {
 		activeDistribution = "custom"
+	} else {
+		activeDistribution = r.Settings().DefaultDistribution
 	}
 	instance.Status.DistributionConfig.ActiveDistribution = activeDistribution
 }
@@ -1270,103 +1449,76 @@ func (r *LlamaStackDistributionReconciler) detectODHTrustedCABundle(ctx context.
 	return configMap, keys, nil
 }
 
-// createDefaultConfigMap creates a ConfigMap with default feature flag values.
-func createDefaultConfigMap(configMapName types.NamespacedName) (*corev1.ConfigMap, error) {
-	featureFlags := featureflags.FeatureFlags{
-		EnableNetworkPolicy: featureflags.FeatureFlag{
-			Enabled: featureflags.NetworkPolicyDefaultValue,
-		},
-	}
-
-	featureFlagsYAML, err := yaml.Marshal(featureFlags)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal default feature flags: %w", err)
+// defaultFeatureFlags returns the feature flags used when the legacy ConfigMap does not set them.
+func defaultFeatureFlags() featureflags.FeatureFlags {
+	return featureflags.FeatureFlags{
+		EnableNetworkPolicy: featureflags.FeatureFlag{Enabled: featureflags.NetworkPolicyDefaultValue},
 	}
-
-	return &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      configMapName.Name,
-			Namespace: configMapName.Namespace,
-		},
-		Data: map[string]string{
-			featureflags.FeatureFlagsKey: string(featureFlagsYAML),
-		},
-	}, nil
 }
 
 // parseFeatureFlags extracts and parses feature flags from ConfigMap data.
-func parseFeatureFlags(configMapData map[string]string) (bool, error) {
-	enableNetworkPolicy := featureflags.NetworkPolicyDefaultValue
+func parseFeatureFlags(configMapData map[string]string) (featureflags.FeatureFlags, error) {
+	flags := defaultFeatureFlags()
 
 	featureFlagsYAML, exists := configMapData[featureflags.FeatureFlagsKey]
 	if !exists {
-		return enableNetworkPolicy, nil
+		return flags, nil
 	}
 
-	var flags featureflags.FeatureFlags
 	if err := yaml.Unmarshal([]byte(featureFlagsYAML), &flags); err != nil {
-		return false, fmt.Errorf("failed to parse feature flags: %w", err)
+		return featureflags.FeatureFlags{}, fmt.Errorf("failed to parse feature flags: %w", err)
 	}
 
-	return flags.EnableNetworkPolicy.Enabled, nil
+	return flags, nil
 }
 
 // NewLlamaStackDistributionReconciler creates a new reconciler with default image mappings.
 func NewLlamaStackDistributionReconciler(ctx context.Context, client client.Client, scheme *runtime.Scheme,
-	clusterInfo *cluster.ClusterInfo) (*LlamaStackDistributionReconciler, error) {
+	clusterInfo *cluster.ClusterInfo, recorder record.EventRecorder, proberOptions HealthProberOptions) (*LlamaStackDistributionReconciler, error) {
 	// get operator namespace
 	operatorNamespace, err := deploy.GetOperatorNamespace()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get operator namespace: %w", err)
 	}
 
-	// Get the ConfigMap
-	// If the ConfigMap doesn't exist, create it with default feature flags
-	// If the ConfigMap exists, parse the feature flags from the Configmap
-	configMap := &corev1.ConfigMap{}
-	configMapName := types.NamespacedName{
-		Name:      operatorConfigData,
-		Namespace: operatorNamespace,
-	}
-
-	if err = client.Get(ctx, configMapName, configMap); err != nil {
-		if !k8serrors.IsNotFound(err) {
-			return nil, fmt.Errorf("failed to get ConfigMap: %w", err)
-		}
-
-		// ConfigMap doesn't exist, create it with defaults
-		configMap, err = createDefaultConfigMap(configMapName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate default configMap: %w", err)
-		}
-
-		if err = client.Create(ctx, configMap); err != nil {
-			return nil, fmt.Errorf("failed to create ConfigMap: %w", err)
-		}
+	// Get the LlamaStackOperatorConfig, migrating the legacy ConfigMap on the first start.
+	// Later changes are applied by the operator config watch.
+	config, err := loadOperatorConfig(ctx, client, operatorConfigMapKey(operatorNamespace))
+	if err != nil {
+		return nil, err
 	}
-
-	// Parse feature flags from ConfigMap
-	enableNetworkPolicy, err := parseFeatureFlags(configMap.Data)
+	settings, err := newOperatorSettings(config.Spec, clusterInfo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse feature flags: %w", err)
+		// The operator config watch reports the invalid spec in the Applied condition.
+		log.FromContext(ctx).Error(err, "invalid LlamaStackOperatorConfig, starting with the default settings")
+		if settings, err = newOperatorSettings(operatorConfigSpecFromFlags(defaultFeatureFlags()), nil); err != nil {
+			return nil, err
+		}
 	}
-	return &LlamaStackDistributionReconciler{
+	r := &LlamaStackDistributionReconciler{
 		Client:              client,
 		Scheme:              scheme,
-		EnableNetworkPolicy: enableNetworkPolicy,
+		watchOperatorConfig: true,
 		ClusterInfo:         clusterInfo,
+		Recorder:            recorder,
 		httpClient:          &http.Client{Timeout: 5 * time.Second},
-	}, nil
+		tracers:             telemetry.NewTracerProviders(),
+		healthProberOptions: proberOptions,
+	}
+	r.SetSettings(*settings)
+	return r, nil
 }
 
 // NewTestReconciler creates a reconciler for testing, allowing injection of a custom http client and feature flags.
 func NewTestReconciler(client client.Client, scheme *runtime.Scheme, clusterInfo *cluster.ClusterInfo,
 	httpClient *http.Client, enableNetworkPolicy bool) *LlamaStackDistributionReconciler {
-	return &LlamaStackDistributionReconciler{
-		Client:              client,
-		Scheme:              scheme,
-		ClusterInfo:         clusterInfo,
-		httpClient:          httpClient,
-		EnableNetworkPolicy: enableNetworkPolicy,
-	}
+	r := &LlamaStackDistributionReconciler{
+		Client:      client,
+		Scheme:      scheme,
+		ClusterInfo: clusterInfo,
+		Recorder:    &record.FakeRecorder{},
+		httpClient:  httpClient,
+	}
+	r.SetSettings(OperatorSettings{EnableNetworkPolicy: enableNetworkPolicy})
+	return r
 }
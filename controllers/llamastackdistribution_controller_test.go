@@ -12,6 +12,7 @@ import (
 	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
 	controllers "github.com/llamastack/llama-stack-k8s-operator/controllers"
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/cluster"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -22,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -373,6 +375,14 @@ func TestLlamaStackProviderAndVersionInfo(t *testing.T) {
 		Version: expectedLlamaStackVersionInfo,
 	}
 
+	// define the data structure for the mock models response
+	modelData := map[string]any{
+		"data": []map[string]string{
+			{"identifier": "ollama/llama3.2:3b", "model_type": "llm"},
+			{"identifier": "all-MiniLM-L6-v2", "model_type": "embedding"},
+		},
+	}
+
 	// create the mock http client that uses our custom roundtripper
 	mockClient := &http.Client{
 		Transport: &mockRoundTripper{
@@ -384,6 +394,9 @@ func TestLlamaStackProviderAndVersionInfo(t *testing.T) {
 				if req.URL.Path == "/v1/version" {
 					return newMockAPIResponse(t, versionData), nil
 				}
+				if req.URL.Path == "/v1/models" {
+					return newMockAPIResponse(t, modelData), nil
+				}
 				return &http.Response{
 					StatusCode: http.StatusNotFound,
 					Body:       io.NopCloser(strings.NewReader("")),
@@ -453,6 +466,13 @@ func TestLlamaStackProviderAndVersionInfo(t *testing.T) {
 		updatedInstance.Status.Version.LlamaStackServerVersion,
 		"server version should match the mock response")
 
+	// validate registered resources, endpoints that are not served are omitted
+	require.NotNil(t, updatedInstance.Status.DistributionConfig.Models)
+	require.Equal(t, 2, updatedInstance.Status.DistributionConfig.Models.Count)
+	require.Equal(t, []string{"all-MiniLM-L6-v2", "ollama/llama3.2:3b"},
+		updatedInstance.Status.DistributionConfig.Models.Identifiers)
+	require.Nil(t, updatedInstance.Status.DistributionConfig.Shields)
+
 	// validate service URL
 	expectedServiceURL := fmt.Sprintf("http://%s-service.%s.svc.cluster.local:%d",
 		instance.Name, instance.Namespace, llamav1alpha1.DefaultServerPort)
@@ -512,3 +532,166 @@ func TestNetworkPolicyConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkPolicyOptIn(t *testing.T) {
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	// --- arrange ---
+	t.Setenv("OPERATOR_NAMESPACE", "test-operator-namespace")
+	namespace := createTestNamespace(t, "test-networkpolicy-optin")
+	instance := NewDistributionBuilder().
+		WithName("np-optin").
+		WithNamespace(namespace.Name).
+		WithDistribution("starter").
+		Build()
+	backendPort := intstr.FromInt32(8000)
+	instance.Spec.Network = &llamav1alpha1.NetworkSpec{
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "chat"}},
+			}},
+		}},
+		Egress: []networkingv1.NetworkPolicyEgressRule{{
+			To: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vllm"}},
+			}},
+			Ports: []networkingv1.NetworkPolicyPort{{Port: &backendPort}},
+		}},
+	}
+	require.NoError(t, k8sClient.Create(t.Context(), instance))
+	t.Cleanup(func() { _ = k8sClient.Delete(t.Context(), instance) })
+
+	// --- act ---
+	// the rules opt in even though the feature flag is disabled
+	ReconcileDistribution(t, instance, false)
+
+	// --- assert ---
+	policy := &networkingv1.NetworkPolicy{}
+	waitForResource(t, k8sClient, instance.Namespace, instance.Name+"-network-policy", policy)
+	require.Len(t, policy.Spec.Ingress, 3, "the custom rule should be added to the default rules")
+	require.Equal(t, "chat", policy.Spec.Ingress[2].From[0].PodSelector.MatchLabels["app"])
+	require.Contains(t, policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+	require.Len(t, policy.Spec.Egress, 2, "DNS should be allowed in addition to the custom rule")
+
+	// --- act ---
+	updated := &llamav1alpha1.LlamaStackDistribution{}
+	require.NoError(t, k8sClient.Get(t.Context(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, updated))
+	updated.Spec.Network = &llamav1alpha1.NetworkSpec{Enabled: ptr.To(false)}
+	require.NoError(t, k8sClient.Update(t.Context(), updated))
+	ReconcileDistribution(t, updated, true)
+
+	// --- assert ---
+	// opting out takes precedence over the feature flag
+	AssertNetworkPolicyAbsent(t, k8sClient, types.NamespacedName{Name: instance.Name + "-network-policy", Namespace: instance.Namespace})
+}
+
+func TestSuspendAndPauseReconciliation(t *testing.T) {
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	// --- arrange ---
+	t.Setenv("OPERATOR_NAMESPACE", "test-operator-namespace")
+	namespace := createTestNamespace(t, "test-suspend")
+	instance := NewDistributionBuilder().
+		WithName("suspend-test").
+		WithNamespace(namespace.Name).
+		WithDistribution("starter").
+		WithReplicas(2).
+		WithStorage(DefaultTestStorage()).
+		Build()
+	instance.Spec.Suspend = true
+	require.NoError(t, k8sClient.Create(t.Context(), instance))
+	t.Cleanup(func() { _ = k8sClient.Delete(t.Context(), instance) })
+
+	// --- act ---
+	ReconcileDistribution(t, instance, false)
+
+	// --- assert ---
+	// a suspended instance runs no replicas but keeps its storage and Service
+	deployment := &appsv1.Deployment{}
+	waitForResource(t, k8sClient, instance.Namespace, instance.Name, deployment)
+	require.NotNil(t, deployment.Spec.Replicas)
+	require.Equal(t, int32(0), *deployment.Spec.Replicas, "suspended deployment should be scaled to zero")
+	AssertPVCExists(t, k8sClient, instance.Namespace, instance.Name+"-pvc")
+	waitForResource(t, k8sClient, instance.Namespace, instance.Name+"-service", &corev1.Service{})
+
+	suspended := &llamav1alpha1.LlamaStackDistribution{}
+	require.NoError(t, k8sClient.Get(t.Context(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, suspended))
+	require.Equal(t, llamav1alpha1.LlamaStackDistributionPhaseSuspended, suspended.Status.Phase)
+	condition := controllers.GetCondition(&suspended.Status, controllers.ConditionTypeDeploymentReady)
+	require.NotNil(t, condition)
+	require.Equal(t, controllers.ReasonDeploymentSuspended, condition.Reason)
+
+	// --- act ---
+	// resuming while paused must not touch the deployment
+	suspended.Annotations = map[string]string{llamav1alpha1.ReconcileAnnotation: llamav1alpha1.ReconcilePausedValue}
+	suspended.Spec.Suspend = false
+	require.NoError(t, k8sClient.Update(t.Context(), suspended))
+	ReconcileDistribution(t, suspended, false)
+
+	// --- assert ---
+	paused := &llamav1alpha1.LlamaStackDistribution{}
+	require.NoError(t, k8sClient.Get(t.Context(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, paused))
+	require.Equal(t, llamav1alpha1.LlamaStackDistributionPhasePaused, paused.Status.Phase)
+	require.True(t, controllers.IsConditionTrue(&paused.Status, controllers.ConditionTypeReconcilePaused))
+
+	require.NoError(t, k8sClient.Get(t.Context(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, deployment))
+	require.Equal(t, int32(0), *deployment.Spec.Replicas, "paused reconciliation should not scale the deployment")
+
+	// --- act ---
+	delete(paused.Annotations, llamav1alpha1.ReconcileAnnotation)
+	require.NoError(t, k8sClient.Update(t.Context(), paused))
+	ReconcileDistribution(t, paused, false)
+
+	// --- assert ---
+	waitForResourceWithKeyAndCondition(t, k8sClient, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, deployment,
+		func() bool { return deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 2 },
+		"resumed deployment should be scaled back up")
+	resumed := &llamav1alpha1.LlamaStackDistribution{}
+	require.NoError(t, k8sClient.Get(t.Context(), types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, resumed))
+	require.True(t, controllers.IsConditionFalse(&resumed.Status, controllers.ConditionTypeReconcilePaused))
+}
+
+func TestRolloutKeepsStableDeploymentInInventory(t *testing.T) {
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	// --- arrange ---
+	namespace := createTestNamespace(t, "test-rollout-inventory")
+	instance := NewDistributionBuilder().
+		WithName("rollout-inventory").
+		WithNamespace(namespace.Name).
+		WithDistribution("starter").
+		WithReplicas(2).
+		Build()
+	instance.Spec.RolloutStrategy = &llamav1alpha1.RolloutStrategy{
+		Type:   llamav1alpha1.RolloutStrategyCanary,
+		Canary: &llamav1alpha1.CanaryStrategy{Steps: []llamav1alpha1.CanaryStep{{Weight: 50}}},
+	}
+	require.NoError(t, k8sClient.Create(t.Context(), instance))
+	t.Cleanup(func() { _ = k8sClient.Delete(t.Context(), instance) })
+	key := types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}
+	stableEntry := llamav1alpha1.InventoryEntry{APIVersion: "apps/v1", Kind: "Deployment", Name: instance.Name}
+
+	ReconcileDistribution(t, instance, false)
+
+	deployed := &llamav1alpha1.LlamaStackDistribution{}
+	require.NoError(t, k8sClient.Get(t.Context(), key, deployed))
+	require.Contains(t, deployed.Status.Inventory, stableEntry)
+
+	// --- act ---
+	// a new revision starts a Canary rollout that holds back the stable Deployment
+	deployed.Spec.Server.ContainerSpec.Env = []corev1.EnvVar{{Name: "NEW_REVISION", Value: "true"}}
+	require.NoError(t, k8sClient.Update(t.Context(), deployed))
+	ReconcileDistribution(t, deployed, false)
+
+	// --- assert ---
+	waitForResource(t, k8sClient, instance.Namespace, deploy.GetPreviewDeploymentName(instance), &appsv1.Deployment{})
+	stable := &appsv1.Deployment{}
+	require.NoError(t, k8sClient.Get(t.Context(), key, stable), "stable Deployment should survive the rollout")
+	require.True(t, stable.DeletionTimestamp.IsZero(), "stable Deployment should not be pruned")
+
+	rollingOut := &llamav1alpha1.LlamaStackDistribution{}
+	require.NoError(t, k8sClient.Get(t.Context(), key, rollingOut))
+	require.NotNil(t, rollingOut.Status.Rollout)
+	require.Equal(t, llamav1alpha1.RolloutPhaseProgressing, rollingOut.Status.Rollout.Phase)
+	require.Contains(t, rollingOut.Status.Inventory, stableEntry)
+}
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// LlamaStackModelReconciler registers LlamaStackModels in the server of the referenced distribution.
+// The registration is verified periodically and restored when the server lost it, e.g. after its
+// pods restarted without persistent storage.
+type LlamaStackModelReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder publishes Events on the reconciled models
+	Recorder   record.EventRecorder
+	httpClient *http.Client
+}
+
+// NewLlamaStackModelReconciler creates a new model reconciler.
+func NewLlamaStackModelReconciler(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *LlamaStackModelReconciler {
+	return &LlamaStackModelReconciler{
+		Client:     client,
+		Scheme:     scheme,
+		Recorder:   recorder,
+		httpClient: newRegistrationHTTPClient(),
+	}
+}
+
+// NewTestModelReconciler creates a model reconciler for testing, allowing injection of a custom http client.
+func NewTestModelReconciler(client client.Client, scheme *runtime.Scheme, httpClient *http.Client) *LlamaStackModelReconciler {
+	return &LlamaStackModelReconciler{
+		Client:     client,
+		Scheme:     scheme,
+		Recorder:   &record.FakeRecorder{},
+		httpClient: httpClient,
+	}
+}
+
+// Reconcile registers the model in its distribution, or unregisters it when it is deleted.
+func (r *LlamaStackModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("namespace", req.Namespace, "name", req.Name)
+	ctx = logr.NewContext(ctx, logger)
+
+	model := &llamav1alpha1.LlamaStackModel{}
+	if err := r.Get(ctx, req.NamespacedName, model); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch LlamaStackModel: %w", err)
+	}
+
+	if !model.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, model)
+	}
+
+	if controllerutil.AddFinalizer(model, llamav1alpha1.RegistrationFinalizer) {
+		if err := r.Update(ctx, model); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	result, registerErr := r.register(ctx, model)
+
+	model.Status.ObservedGeneration = model.Generation
+	if err := r.Status().Update(ctx, model); err != nil {
+		if registerErr != nil {
+			return ctrl.Result{}, registerErr
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+	return result, registerErr
+}
+
+// register makes sure the model is registered with the desired attributes and sets the Ready condition.
+func (r *LlamaStackModelReconciler) register(ctx context.Context, model *llamav1alpha1.LlamaStackModel) (ctrl.Result, error) {
+	distribution, reason, message, err := getServingDistribution(ctx, r.Client, model.Namespace, model.Spec.DistributionRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if distribution == nil {
+		// The distribution watch requeues the model once it is ready.
+		setModelReady(model, false, reason, message)
+		return ctrl.Result{}, nil
+	}
+
+	desired, err := desiredModel(model)
+	if err != nil {
+		setModelReady(model, false, ReasonInvalidSpec, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	server, err := newServerClient(r.httpClient, distribution)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ensureRegistered(ctx, server, model, desired); err != nil {
+		setModelReady(model, false, ReasonRegistrationFailed, err.Error())
+		r.Recorder.Eventf(model, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+			"Failed to register model %s: %v", desired.ModelID, err)
+		return ctrl.Result{}, err
+	}
+
+	setModelReady(model, true, ReasonRegistered,
+		fmt.Sprintf("Model %s is registered in LlamaStackDistribution %s", desired.ModelID, distribution.Name))
+	return ctrl.Result{RequeueAfter: registrationResyncInterval()}, nil
+}
+
+// ensureRegistered registers the model if the server does not know it or knows it with other attributes.
+func (r *LlamaStackModelReconciler) ensureRegistered(
+	ctx context.Context,
+	server *llamastack.Client,
+	model *llamav1alpha1.LlamaStackModel,
+	desired llamastack.RegisterModelRequest,
+) error {
+	logger := log.FromContext(ctx)
+
+	// The model ID changed, remove the previous registration.
+	if previous := model.Status.RegisteredModelID; previous != "" && previous != desired.ModelID {
+		if err := llamastack.IgnoreNotFound(server.UnregisterModel(ctx, previous)); err != nil {
+			return fmt.Errorf("failed to unregister previous model %s: %w", previous, err)
+		}
+		logger.Info("Unregistered previous model ID", "modelID", previous)
+		model.Status.RegisteredModelID = ""
+	}
+
+	current, err := server.GetModel(ctx, desired.ModelID)
+	switch {
+	case err == nil && modelMatches(current, desired):
+		model.Status.RegisteredModelID = desired.ModelID
+		return nil
+	case err == nil:
+		// Registrations cannot be updated in place.
+		logger.Info("Model registered with other attributes, registering it again", "modelID", desired.ModelID)
+		if err := llamastack.IgnoreNotFound(server.UnregisterModel(ctx, desired.ModelID)); err != nil {
+			return err
+		}
+	case !errors.Is(err, llamastack.ErrNotFound):
+		return err
+	}
+
+	if _, err := server.RegisterModel(ctx, desired); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	model.Status.LastRegistrationTime = &now
+	if model.Status.RegisteredModelID == desired.ModelID && current == nil {
+		logger.Info("Model was missing from the server, registered it again", "modelID", desired.ModelID)
+		r.Recorder.Eventf(model, corev1.EventTypeNormal, EventReasonReregistered,
+			"Model %s was missing from the server and has been registered again", desired.ModelID)
+	} else {
+		logger.Info("Registered model", "modelID", desired.ModelID)
+		r.Recorder.Eventf(model, corev1.EventTypeNormal, EventReasonRegistered, "Registered model %s", desired.ModelID)
+	}
+	model.Status.RegisteredModelID = desired.ModelID
+	return nil
+}
+
+// finalize unregisters the model and removes the finalizer. A registration in a distribution that
+// is gone or not serving is left in place, since nothing could remove it.
+func (r *LlamaStackModelReconciler) finalize(ctx context.Context, model *llamav1alpha1.LlamaStackModel) error {
+	if !controllerutil.ContainsFinalizer(model, llamav1alpha1.RegistrationFinalizer) {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	if modelID := model.Status.RegisteredModelID; modelID != "" {
+		distribution, _, message, err := getServingDistribution(ctx, r.Client, model.Namespace, model.Spec.DistributionRef)
+		if err != nil {
+			return err
+		}
+
+		if distribution == nil {
+			logger.Info("Skipping model unregistration", "modelID", modelID, "reason", message)
+		} else {
+			server, err := newServerClient(r.httpClient, distribution)
+			if err != nil {
+				return err
+			}
+			if err := llamastack.IgnoreNotFound(server.UnregisterModel(ctx, modelID)); err != nil {
+				r.Recorder.Eventf(model, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+					"Failed to unregister model %s: %v", modelID, err)
+				return fmt.Errorf("failed to unregister model %s: %w", modelID, err)
+			}
+			logger.Info("Unregistered model", "modelID", modelID)
+			r.Recorder.Eventf(model, corev1.EventTypeNormal, EventReasonUnregistered, "Unregistered model %s", modelID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(model, llamav1alpha1.RegistrationFinalizer)
+	if err := r.Update(ctx, model); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+// desiredModel builds the registration request of the model.
+func desiredModel(model *llamav1alpha1.LlamaStackModel) (llamastack.RegisterModelRequest, error) {
+	req := llamastack.RegisterModelRequest{
+		ModelID:         model.GetModelID(),
+		ProviderID:      model.Spec.ProviderID,
+		ProviderModelID: model.Spec.ProviderModelID,
+		ModelType:       string(model.Spec.ModelType),
+	}
+	if req.ModelType == "" {
+		req.ModelType = string(llamav1alpha1.ModelTypeLLM)
+	}
+	if model.Spec.Metadata != nil && len(model.Spec.Metadata.Raw) > 0 {
+		if err := json.Unmarshal(model.Spec.Metadata.Raw, &req.Metadata); err != nil {
+			return req, fmt.Errorf("failed to parse metadata: %w", err)
+		}
+	}
+	return req, nil
+}
+
+// modelMatches returns true if the registered model has the desired attributes. The server may
+// add metadata, so only the desired metadata keys are compared.
+func modelMatches(current *llamastack.Model, desired llamastack.RegisterModelRequest) bool {
+	providerModelID := desired.ProviderModelID
+	if providerModelID == "" {
+		providerModelID = desired.ModelID
+	}
+	if current.ProviderID != desired.ProviderID ||
+		current.ProviderResourceID != providerModelID ||
+		current.ModelType != desired.ModelType {
+		return false
+	}
+	for key, value := range desired.Metadata {
+		if !reflect.DeepEqual(current.Metadata[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// setModelReady sets the Ready condition of the model.
+func setModelReady(model *llamav1alpha1.LlamaStackModel, ready bool, reason, message string) {
+	status := metav1.ConditionTrue
+	if !ready {
+		status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
+		Type:               llamav1alpha1.ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: model.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LlamaStackModelReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &llamav1alpha1.LlamaStackModel{}, distributionRefIndex,
+		func(obj client.Object) []string {
+			model, ok := obj.(*llamav1alpha1.LlamaStackModel)
+			if !ok {
+				return nil
+			}
+			return []string{model.Spec.DistributionRef.Name}
+		}); err != nil {
+		return fmt.Errorf("failed to create LlamaStackModel distribution index: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&llamav1alpha1.LlamaStackModel{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&llamav1alpha1.LlamaStackDistribution{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return requestsForDistribution(ctx, r.Client, obj, &llamav1alpha1.LlamaStackModelList{})
+			}),
+			builder.WithPredicates(distributionServingChanged()),
+		).
+		Complete(r)
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLlamaStackModelReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, llamav1alpha1.AddToScheme(scheme))
+
+	distribution := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "models-ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{ContainerSpec: llamav1alpha1.ContainerSpec{Port: 8321}},
+		},
+		Status: llamav1alpha1.LlamaStackDistributionStatus{Phase: llamav1alpha1.LlamaStackDistributionPhasePending},
+	}
+	model := &llamav1alpha1.LlamaStackModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "models-ns", Generation: 1},
+		Spec: llamav1alpha1.LlamaStackModelSpec{
+			DistributionRef: llamav1alpha1.DistributionReference{Name: "llsd"},
+			ModelID:         "llama3.2:1b",
+			ProviderID:      "ollama",
+		},
+	}
+	k8sClient := clientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(distribution, model).
+		WithStatusSubresource(distribution, model).
+		Build()
+
+	server := fake.NewServer()
+	defer server.Close()
+	recorder := record.NewFakeRecorder(10)
+	r := NewTestModelReconciler(k8sClient, scheme, server.HTTPClient())
+	r.Recorder = recorder
+
+	ctx := context.Background() //nolint:usetesting // the reconciler is shared by the subtests
+	key := types.NamespacedName{Name: "llama", Namespace: "models-ns"}
+	reconcile := func(t *testing.T) (ctrl.Result, *llamav1alpha1.LlamaStackModel) {
+		t.Helper()
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		current := &llamav1alpha1.LlamaStackModel{}
+		if err := k8sClient.Get(ctx, key, current); err != nil {
+			require.True(t, client.IgnoreNotFound(err) == nil, "unexpected error: %v", err)
+			return result, nil
+		}
+		return result, current
+	}
+
+	t.Run("waits for the distribution to be ready", func(t *testing.T) {
+		_, current := reconcile(t)
+
+		assert.Contains(t, current.Finalizers, llamav1alpha1.RegistrationFinalizer)
+		condition := meta.FindStatusCondition(current.Status.Conditions, llamav1alpha1.ConditionTypeReady)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, ReasonDistributionNotReady, condition.Reason)
+		assert.Empty(t, registeredModelIDs(server))
+	})
+
+	distribution.Status.Phase = llamav1alpha1.LlamaStackDistributionPhaseReady
+	require.NoError(t, k8sClient.Status().Update(ctx, distribution))
+
+	t.Run("registers the model", func(t *testing.T) {
+		result, current := reconcile(t)
+
+		assert.Equal(t, registrationResyncInterval(), result.RequeueAfter)
+		assert.Equal(t, []string{"llama3.2:1b"}, registeredModelIDs(server))
+		assert.Equal(t, "llama3.2:1b", current.Status.RegisteredModelID)
+		assert.Equal(t, int64(1), current.Status.ObservedGeneration)
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeReady))
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal Registered Registered model llama3.2:1b", <-recorder.Events)
+	})
+
+	t.Run("leaves a matching registration alone", func(t *testing.T) {
+		reconcile(t)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("registers the model again after a server restart", func(t *testing.T) {
+		server.Restart()
+
+		reconcile(t)
+		assert.Equal(t, []string{"llama3.2:1b"}, registeredModelIDs(server))
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal Reregistered Model llama3.2:1b was missing from the server and has been registered again", <-recorder.Events)
+	})
+
+	t.Run("unregisters the model on deletion", func(t *testing.T) {
+		current := &llamav1alpha1.LlamaStackModel{}
+		require.NoError(t, k8sClient.Get(ctx, key, current))
+		require.NoError(t, k8sClient.Delete(ctx, current))
+
+		_, current = reconcile(t)
+		assert.Nil(t, current, "the finalizer is removed once the model is unregistered")
+		assert.Empty(t, registeredModelIDs(server))
+		assert.Equal(t, "Normal Unregistered Unregistered model llama3.2:1b", <-recorder.Events)
+	})
+}
+
+func registeredModelIDs(server *fake.Server) []string {
+	var ids []string
+	for _, model := range server.Models() {
+		ids = append(ids, model.Identifier)
+	}
+	return ids
+}
+
+func TestModelMatches(t *testing.T) {
+	desired := llamastack.RegisterModelRequest{
+		ModelID:    "all-minilm",
+		ProviderID: "sentence-transformers",
+		ModelType:  "embedding",
+		Metadata:   map[string]any{"embedding_dimension": float64(384)},
+	}
+	registered := llamastack.Model{
+		Identifier:         "all-minilm",
+		ProviderID:         "sentence-transformers",
+		ProviderResourceID: "all-minilm",
+		ModelType:          "embedding",
+		Metadata:           map[string]any{"embedding_dimension": float64(384), "added_by_server": true},
+	}
+	assert.True(t, modelMatches(&registered, desired), "metadata added by the server is ignored")
+
+	otherProvider := registered
+	otherProvider.ProviderID = "ollama"
+	assert.False(t, modelMatches(&otherProvider, desired))
+
+	otherMetadata := registered
+	otherMetadata.Metadata = map[string]any{"embedding_dimension": float64(768)}
+	assert.False(t, modelMatches(&otherMetadata, desired))
+}
@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// LlamaStackShieldReconciler registers LlamaStackShields in the server of the referenced distribution.
+// Like models, the registration is verified periodically and restored when the server lost it. The
+// distribution controller reports missing required shields in the ShieldsEnforced condition.
+type LlamaStackShieldReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder publishes Events on the reconciled shields
+	Recorder   record.EventRecorder
+	httpClient *http.Client
+}
+
+// NewLlamaStackShieldReconciler creates a new shield reconciler.
+func NewLlamaStackShieldReconciler(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *LlamaStackShieldReconciler {
+	return &LlamaStackShieldReconciler{
+		Client:     client,
+		Scheme:     scheme,
+		Recorder:   recorder,
+		httpClient: newRegistrationHTTPClient(),
+	}
+}
+
+// Reconcile registers the shield in its distribution, or unregisters it when it is deleted.
+func (r *LlamaStackShieldReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("namespace", req.Namespace, "name", req.Name)
+	ctx = logr.NewContext(ctx, logger)
+
+	shield := &llamav1alpha1.LlamaStackShield{}
+	if err := r.Get(ctx, req.NamespacedName, shield); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch LlamaStackShield: %w", err)
+	}
+
+	if !shield.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, shield)
+	}
+
+	if controllerutil.AddFinalizer(shield, llamav1alpha1.RegistrationFinalizer) {
+		if err := r.Update(ctx, shield); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	result, registerErr := r.register(ctx, shield)
+
+	shield.Status.ObservedGeneration = shield.Generation
+	if err := r.Status().Update(ctx, shield); err != nil {
+		if registerErr != nil {
+			return ctrl.Result{}, registerErr
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+	return result, registerErr
+}
+
+// register makes sure the shield is registered with the desired attributes and sets the Ready condition.
+func (r *LlamaStackShieldReconciler) register(ctx context.Context, shield *llamav1alpha1.LlamaStackShield) (ctrl.Result, error) {
+	distribution, reason, message, err := getServingDistribution(ctx, r.Client, shield.Namespace, shield.Spec.DistributionRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if distribution == nil {
+		// The distribution watch requeues the shield once it is ready.
+		setShieldReady(shield, false, reason, message)
+		return ctrl.Result{}, nil
+	}
+
+	desired, err := desiredShield(shield)
+	if err != nil {
+		setShieldReady(shield, false, ReasonInvalidSpec, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	server, err := newServerClient(r.httpClient, distribution)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ensureRegistered(ctx, server, shield, desired); err != nil {
+		setShieldReady(shield, false, ReasonRegistrationFailed, err.Error())
+		r.Recorder.Eventf(shield, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+			"Failed to register shield %s: %v", desired.ShieldID, err)
+		return ctrl.Result{}, err
+	}
+
+	setShieldReady(shield, true, ReasonRegistered,
+		fmt.Sprintf("Shield %s is registered in LlamaStackDistribution %s", desired.ShieldID, distribution.Name))
+	return ctrl.Result{RequeueAfter: registrationResyncInterval()}, nil
+}
+
+// ensureRegistered registers the shield if the server does not know it or knows it with other attributes.
+func (r *LlamaStackShieldReconciler) ensureRegistered(
+	ctx context.Context,
+	server *llamastack.Client,
+	shield *llamav1alpha1.LlamaStackShield,
+	desired llamastack.RegisterShieldRequest,
+) error {
+	logger := log.FromContext(ctx)
+
+	// The shield ID changed, remove the previous registration.
+	if previous := shield.Status.RegisteredShieldID; previous != "" && previous != desired.ShieldID {
+		if err := llamastack.IgnoreNotFound(server.UnregisterShield(ctx, previous)); err != nil {
+			return fmt.Errorf("failed to unregister previous shield %s: %w", previous, err)
+		}
+		logger.Info("Unregistered previous shield ID", "shieldID", previous)
+		shield.Status.RegisteredShieldID = ""
+	}
+
+	current, err := server.GetShield(ctx, desired.ShieldID)
+	switch {
+	case err == nil && shieldMatches(current, desired):
+		shield.Status.RegisteredShieldID = desired.ShieldID
+		return nil
+	case err == nil:
+		// Registrations cannot be updated in place.
+		logger.Info("Shield registered with other attributes, registering it again", "shieldID", desired.ShieldID)
+		if err := llamastack.IgnoreNotFound(server.UnregisterShield(ctx, desired.ShieldID)); err != nil {
+			return err
+		}
+	case !errors.Is(err, llamastack.ErrNotFound):
+		return err
+	}
+
+	if _, err := server.RegisterShield(ctx, desired); err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	shield.Status.LastRegistrationTime = &now
+	if shield.Status.RegisteredShieldID == desired.ShieldID && current == nil {
+		logger.Info("Shield was missing from the server, registered it again", "shieldID", desired.ShieldID)
+		r.Recorder.Eventf(shield, corev1.EventTypeNormal, EventReasonReregistered,
+			"Shield %s was missing from the server and has been registered again", desired.ShieldID)
+	} else {
+		logger.Info("Registered shield", "shieldID", desired.ShieldID)
+		r.Recorder.Eventf(shield, corev1.EventTypeNormal, EventReasonRegistered, "Registered shield %s", desired.ShieldID)
+	}
+	shield.Status.RegisteredShieldID = desired.ShieldID
+	return nil
+}
+
+// finalize unregisters the shield and removes the finalizer. A registration in a distribution that
+// is gone or not serving is left in place, since nothing could remove it.
+func (r *LlamaStackShieldReconciler) finalize(ctx context.Context, shield *llamav1alpha1.LlamaStackShield) error {
+	if !controllerutil.ContainsFinalizer(shield, llamav1alpha1.RegistrationFinalizer) {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	if shieldID := shield.Status.RegisteredShieldID; shieldID != "" {
+		distribution, _, message, err := getServingDistribution(ctx, r.Client, shield.Namespace, shield.Spec.DistributionRef)
+		if err != nil {
+			return err
+		}
+
+		if distribution == nil {
+			logger.Info("Skipping shield unregistration", "shieldID", shieldID, "reason", message)
+		} else {
+			server, err := newServerClient(r.httpClient, distribution)
+			if err != nil {
+				return err
+			}
+			if err := llamastack.IgnoreNotFound(server.UnregisterShield(ctx, shieldID)); err != nil {
+				r.Recorder.Eventf(shield, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+					"Failed to unregister shield %s: %v", shieldID, err)
+				return fmt.Errorf("failed to unregister shield %s: %w", shieldID, err)
+			}
+			logger.Info("Unregistered shield", "shieldID", shieldID)
+			r.Recorder.Eventf(shield, corev1.EventTypeNormal, EventReasonUnregistered, "Unregistered shield %s", shieldID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(shield, llamav1alpha1.RegistrationFinalizer)
+	if err := r.Update(ctx, shield); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+// desiredShield builds the registration request of the shield.
+func desiredShield(shield *llamav1alpha1.LlamaStackShield) (llamastack.RegisterShieldRequest, error) {
+	req := llamastack.RegisterShieldRequest{
+		ShieldID:         shield.GetShieldID(),
+		ProviderID:       shield.Spec.ProviderID,
+		ProviderShieldID: shield.Spec.ProviderShieldID,
+	}
+	if shield.Spec.Params != nil && len(shield.Spec.Params.Raw) > 0 {
+		if err := json.Unmarshal(shield.Spec.Params.Raw, &req.Params); err != nil {
+			return req, fmt.Errorf("failed to parse params: %w", err)
+		}
+	}
+	return req, nil
+}
+
+// shieldMatches returns true if the registered shield has the desired attributes. Only the desired
+// params are compared, since the server may add defaults.
+func shieldMatches(current *llamastack.Shield, desired llamastack.RegisterShieldRequest) bool {
+	providerShieldID := desired.ProviderShieldID
+	if providerShieldID == "" {
+		providerShieldID = desired.ShieldID
+	}
+	if current.ProviderID != desired.ProviderID || current.ProviderResourceID != providerShieldID {
+		return false
+	}
+	for key, value := range desired.Params {
+		if !reflect.DeepEqual(current.Params[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// setShieldReady sets the Ready condition of the shield.
+func setShieldReady(shield *llamav1alpha1.LlamaStackShield, ready bool, reason, message string) {
+	status := metav1.ConditionTrue
+	if !ready {
+		status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(&shield.Status.Conditions, metav1.Condition{
+		Type:               llamav1alpha1.ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: shield.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LlamaStackShieldReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &llamav1alpha1.LlamaStackShield{}, distributionRefIndex,
+		func(obj client.Object) []string {
+			shield, ok := obj.(*llamav1alpha1.LlamaStackShield)
+			if !ok {
+				return nil
+			}
+			return []string{shield.Spec.DistributionRef.Name}
+		}); err != nil {
+		return fmt.Errorf("failed to create LlamaStackShield distribution index: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&llamav1alpha1.LlamaStackShield{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&llamav1alpha1.LlamaStackDistribution{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return requestsForDistribution(ctx, r.Client, obj, &llamav1alpha1.LlamaStackShieldList{})
+			}),
+			builder.WithPredicates(distributionServingChanged()),
+		).
+		Complete(r)
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLlamaStackShieldReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, llamav1alpha1.AddToScheme(scheme))
+
+	distribution := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "shield-ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{ContainerSpec: llamav1alpha1.ContainerSpec{Port: 8321}},
+		},
+		Status: llamav1alpha1.LlamaStackDistributionStatus{Phase: llamav1alpha1.LlamaStackDistributionPhaseReady},
+	}
+	shield := newTestShield("llama-guard", "llsd", nil)
+	shield.Generation = 1
+	shield.Spec.ProviderShieldID = "llama-guard3:1b"
+	shield.Spec.Params = &apiextensionsv1.JSON{Raw: []byte(`{"excluded_categories":["S6"]}`)}
+	k8sClient := clientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(distribution, shield).
+		WithStatusSubresource(distribution, shield).
+		Build()
+
+	server := fake.NewServer()
+	defer server.Close()
+	recorder := record.NewFakeRecorder(10)
+	r := NewLlamaStackShieldReconciler(k8sClient, scheme, recorder)
+	r.httpClient = server.HTTPClient()
+
+	ctx := context.Background() //nolint:usetesting // the reconciler is shared by the subtests
+	key := types.NamespacedName{Name: "llama-guard", Namespace: "shield-ns"}
+	reconcile := func(t *testing.T) *llamav1alpha1.LlamaStackShield {
+		t.Helper()
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		current := &llamav1alpha1.LlamaStackShield{}
+		if err := k8sClient.Get(ctx, key, current); err != nil {
+			require.True(t, client.IgnoreNotFound(err) == nil, "unexpected error: %v", err)
+			return nil
+		}
+		return current
+	}
+
+	t.Run("registers the shield", func(t *testing.T) {
+		current := reconcile(t)
+
+		require.Len(t, server.Shields(), 1)
+		registered := server.Shields()[0]
+		assert.Equal(t, "llama-guard", registered.Identifier)
+		assert.Equal(t, "llama-guard3:1b", registered.ProviderResourceID)
+		assert.Equal(t, []any{"S6"}, registered.Params["excluded_categories"])
+		assert.Equal(t, "llama-guard", current.Status.RegisteredShieldID)
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeReady))
+		assert.Equal(t, "Normal Registered Registered shield llama-guard", <-recorder.Events)
+	})
+
+	t.Run("registers the shield again after a server restart", func(t *testing.T) {
+		server.Restart()
+		reconcile(t)
+
+		require.Len(t, server.Shields(), 1)
+		assert.Equal(t, "Normal Reregistered Shield llama-guard was missing from the server and has been registered again", <-recorder.Events)
+	})
+
+	t.Run("unregisters the shield on deletion", func(t *testing.T) {
+		current := &llamav1alpha1.LlamaStackShield{}
+		require.NoError(t, k8sClient.Get(ctx, key, current))
+		require.NoError(t, k8sClient.Delete(ctx, current))
+
+		assert.Nil(t, reconcile(t), "the finalizer is removed once the shield is unregistered")
+		assert.Empty(t, server.Shields())
+		assert.Equal(t, "Normal Unregistered Unregistered shield llama-guard", <-recorder.Events)
+	})
+}
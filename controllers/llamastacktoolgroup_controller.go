@@ -0,0 +1,342 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/go-logr/logr"
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Condition reasons of LlamaStackToolGroups.
+const (
+	// ReasonMCPServerNotReady indicates the MCP server deployed for the tool group is not available yet.
+	ReasonMCPServerNotReady = "MCPServerNotReady"
+	// ReasonToolsAvailable indicates the server lists the tools of the tool group.
+	ReasonToolsAvailable = "ToolsAvailable"
+	// ReasonToolsUnavailable indicates the server cannot list the tools of the tool group, usually
+	// because it cannot reach the MCP server.
+	ReasonToolsUnavailable = "ToolsUnavailable"
+)
+
+// Event reasons emitted on LlamaStackToolGroups.
+const (
+	// EventReasonToolsAvailable is emitted when the tools of the tool group become available.
+	EventReasonToolsAvailable = "ToolsAvailable"
+	// EventReasonToolsUnavailable is emitted when the tools of the tool group become unavailable.
+	EventReasonToolsUnavailable = "ToolsUnavailable"
+)
+
+// LlamaStackToolGroupReconciler registers LlamaStackToolGroups in the server of the referenced
+// distribution, deploying their MCP server when requested. Like models, the registration is
+// verified periodically, which also tracks whether the tools of the MCP server are available.
+type LlamaStackToolGroupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder publishes Events on the reconciled tool groups
+	Recorder   record.EventRecorder
+	httpClient *http.Client
+}
+
+// NewLlamaStackToolGroupReconciler creates a new tool group reconciler.
+func NewLlamaStackToolGroupReconciler(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *LlamaStackToolGroupReconciler {
+	return &LlamaStackToolGroupReconciler{
+		Client:     client,
+		Scheme:     scheme,
+		Recorder:   recorder,
+		httpClient: newRegistrationHTTPClient(),
+	}
+}
+
+// Reconcile deploys the MCP server of the tool group and registers the tool group in its
+// distribution, or unregisters it when it is deleted.
+func (r *LlamaStackToolGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("namespace", req.Namespace, "name", req.Name)
+	ctx = logr.NewContext(ctx, logger)
+
+	toolGroup := &llamav1alpha1.LlamaStackToolGroup{}
+	if err := r.Get(ctx, req.NamespacedName, toolGroup); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch LlamaStackToolGroup: %w", err)
+	}
+
+	if !toolGroup.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, toolGroup)
+	}
+
+	if controllerutil.AddFinalizer(toolGroup, llamav1alpha1.RegistrationFinalizer) {
+		if err := r.Update(ctx, toolGroup); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	result, registerErr := r.register(ctx, toolGroup)
+
+	toolGroup.Status.ObservedGeneration = toolGroup.Generation
+	if err := r.Status().Update(ctx, toolGroup); err != nil {
+		if registerErr != nil {
+			return ctrl.Result{}, registerErr
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+	return result, registerErr
+}
+
+// register resolves the MCP endpoint, makes sure the tool group is registered with it and checks
+// the availability of its tools.
+func (r *LlamaStackToolGroupReconciler) register(ctx context.Context, toolGroup *llamav1alpha1.LlamaStackToolGroup) (ctrl.Result, error) {
+	endpoint := toolGroup.Spec.Endpoint
+	if toolGroup.Spec.Server != nil {
+		available, err := r.ensureMCPServer(ctx, toolGroup)
+		if err != nil {
+			setToolGroupCondition(toolGroup, llamav1alpha1.ConditionTypeReady, false, ReasonMCPServerNotReady, err.Error())
+			return ctrl.Result{}, err
+		}
+		endpoint = mcpServerEndpoint(toolGroup)
+		toolGroup.Status.Endpoint = endpoint
+		if !available {
+			// The Deployment watch requeues the tool group once the server is available.
+			setToolGroupCondition(toolGroup, llamav1alpha1.ConditionTypeReady, false, ReasonMCPServerNotReady, mcpServerMessage(toolGroup))
+			return ctrl.Result{}, nil
+		}
+	} else {
+		if err := r.deleteMCPServer(ctx, toolGroup); err != nil {
+			return ctrl.Result{}, err
+		}
+		toolGroup.Status.Endpoint = endpoint
+	}
+
+	distribution, reason, message, err := getServingDistribution(ctx, r.Client, toolGroup.Namespace, toolGroup.Spec.DistributionRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if distribution == nil {
+		// The distribution watch requeues the tool group once it is ready.
+		setToolGroupCondition(toolGroup, llamav1alpha1.ConditionTypeReady, false, reason, message)
+		return ctrl.Result{}, nil
+	}
+
+	server, err := newServerClient(r.httpClient, distribution)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired := desiredToolGroup(toolGroup, endpoint)
+	if err := r.ensureRegistered(ctx, server, toolGroup, desired); err != nil {
+		setToolGroupCondition(toolGroup, llamav1alpha1.ConditionTypeReady, false, ReasonRegistrationFailed, err.Error())
+		r.Recorder.Eventf(toolGroup, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+			"Failed to register tool group %s: %v", desired.ToolGroupID, err)
+		return ctrl.Result{}, err
+	}
+	setToolGroupCondition(toolGroup, llamav1alpha1.ConditionTypeReady, true, ReasonRegistered,
+		fmt.Sprintf("Tool group %s is registered in LlamaStackDistribution %s", desired.ToolGroupID, distribution.Name))
+
+	r.checkTools(ctx, server, toolGroup)
+	return ctrl.Result{RequeueAfter: registrationResyncInterval()}, nil
+}
+
+// ensureRegistered registers the tool group if the server does not know it or knows it with
+// another provider or endpoint.
+func (r *LlamaStackToolGroupReconciler) ensureRegistered(
+	ctx context.Context,
+	server *llamastack.Client,
+	toolGroup *llamav1alpha1.LlamaStackToolGroup,
+	desired llamastack.RegisterToolGroupRequest,
+) error {
+	logger := log.FromContext(ctx)
+
+	// The tool group ID changed, remove the previous registration.
+	if previous := toolGroup.Status.RegisteredToolGroupID; previous != "" && previous != desired.ToolGroupID {
+		if err := llamastack.IgnoreNotFound(server.UnregisterToolGroup(ctx, previous)); err != nil {
+			return fmt.Errorf("failed to unregister previous tool group %s: %w", previous, err)
+		}
+		logger.Info("Unregistered previous tool group ID", "toolGroupID", previous)
+		toolGroup.Status.RegisteredToolGroupID = ""
+	}
+
+	current, err := server.GetToolGroup(ctx, desired.ToolGroupID)
+	switch {
+	case err == nil && toolGroupMatches(current, desired):
+		toolGroup.Status.RegisteredToolGroupID = desired.ToolGroupID
+		return nil
+	case err == nil:
+		// Registrations cannot be updated in place.
+		logger.Info("Tool group registered with other attributes, registering it again", "toolGroupID", desired.ToolGroupID)
+		if err := llamastack.IgnoreNotFound(server.UnregisterToolGroup(ctx, desired.ToolGroupID)); err != nil {
+			return err
+		}
+	case !errors.Is(err, llamastack.ErrNotFound):
+		return err
+	}
+
+	if err := server.RegisterToolGroup(ctx, desired); err != nil {
+		return err
+	}
+
+	if toolGroup.Status.RegisteredToolGroupID == desired.ToolGroupID && current == nil {
+		logger.Info("Tool group was missing from the server, registered it again", "toolGroupID", desired.ToolGroupID)
+		r.Recorder.Eventf(toolGroup, corev1.EventTypeNormal, EventReasonReregistered,
+			"Tool group %s was missing from the server and has been registered again", desired.ToolGroupID)
+	} else {
+		logger.Info("Registered tool group", "toolGroupID", desired.ToolGroupID)
+		r.Recorder.Eventf(toolGroup, corev1.EventTypeNormal, EventReasonRegistered, "Registered tool group %s", desired.ToolGroupID)
+	}
+	toolGroup.Status.RegisteredToolGroupID = desired.ToolGroupID
+	return nil
+}
+
+// checkTools lists the tools of the registered tool group, which makes the server connect to the
+// MCP server, and sets the Available condition. An Event is emitted when the availability changes.
+func (r *LlamaStackToolGroupReconciler) checkTools(ctx context.Context, server *llamastack.Client, toolGroup *llamav1alpha1.LlamaStackToolGroup) {
+	var previous metav1.ConditionStatus
+	if condition := meta.FindStatusCondition(toolGroup.Status.Conditions, llamav1alpha1.ConditionTypeAvailable); condition != nil {
+		previous = condition.Status
+	}
+
+	tools, err := server.ListTools(ctx, toolGroup.Status.RegisteredToolGroupID)
+	if err != nil {
+		log.FromContext(ctx).Info("Tools of the tool group are unavailable", "error", err.Error())
+		toolGroup.Status.Tools = nil
+		setToolGroupCondition(toolGroup, llamav1alpha1.ConditionTypeAvailable, false, ReasonToolsUnavailable, err.Error())
+		if previous != metav1.ConditionFalse {
+			r.Recorder.Eventf(toolGroup, corev1.EventTypeWarning, EventReasonToolsUnavailable,
+				"Tools of tool group %s are unavailable: %v", toolGroup.Status.RegisteredToolGroupID, err)
+		}
+		return
+	}
+
+	identifiers := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		identifiers = append(identifiers, tool.Identifier)
+	}
+	slices.Sort(identifiers)
+	toolGroup.Status.Tools = summarizeRegistered(registeredQuery{Identifiers: identifiers})
+
+	message := fmt.Sprintf("%d tools available", len(identifiers))
+	setToolGroupCondition(toolGroup, llamav1alpha1.ConditionTypeAvailable, true, ReasonToolsAvailable, message)
+	if previous != metav1.ConditionTrue {
+		r.Recorder.Eventf(toolGroup, corev1.EventTypeNormal, EventReasonToolsAvailable,
+			"Tool group %s has %s", toolGroup.Status.RegisteredToolGroupID, message)
+	}
+}
+
+// finalize unregisters the tool group and removes the finalizer. The MCP server resources are
+// garbage collected through their owner reference.
+func (r *LlamaStackToolGroupReconciler) finalize(ctx context.Context, toolGroup *llamav1alpha1.LlamaStackToolGroup) error {
+	if !controllerutil.ContainsFinalizer(toolGroup, llamav1alpha1.RegistrationFinalizer) {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	if toolGroupID := toolGroup.Status.RegisteredToolGroupID; toolGroupID != "" {
+		distribution, _, message, err := getServingDistribution(ctx, r.Client, toolGroup.Namespace, toolGroup.Spec.DistributionRef)
+		if err != nil {
+			return err
+		}
+
+		if distribution == nil {
+			logger.Info("Skipping tool group unregistration", "toolGroupID", toolGroupID, "reason", message)
+		} else {
+			server, err := newServerClient(r.httpClient, distribution)
+			if err != nil {
+				return err
+			}
+			if err := llamastack.IgnoreNotFound(server.UnregisterToolGroup(ctx, toolGroupID)); err != nil {
+				r.Recorder.Eventf(toolGroup, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+					"Failed to unregister tool group %s: %v", toolGroupID, err)
+				return fmt.Errorf("failed to unregister tool group %s: %w", toolGroupID, err)
+			}
+			logger.Info("Unregistered tool group", "toolGroupID", toolGroupID)
+			r.Recorder.Eventf(toolGroup, corev1.EventTypeNormal, EventReasonUnregistered, "Unregistered tool group %s", toolGroupID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(toolGroup, llamav1alpha1.RegistrationFinalizer)
+	if err := r.Update(ctx, toolGroup); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+// desiredToolGroup builds the registration request of the tool group.
+func desiredToolGroup(toolGroup *llamav1alpha1.LlamaStackToolGroup, endpoint string) llamastack.RegisterToolGroupRequest {
+	providerID := toolGroup.Spec.ProviderID
+	if providerID == "" {
+		providerID = llamastack.ProviderIDModelContextProtocol
+	}
+	return llamastack.RegisterToolGroupRequest{
+		ToolGroupID: toolGroup.GetToolGroupID(),
+		ProviderID:  providerID,
+		MCPEndpoint: &llamastack.URL{URI: endpoint},
+	}
+}
+
+// toolGroupMatches returns true if the registered tool group has the desired provider and endpoint.
+func toolGroupMatches(current *llamastack.ToolGroup, desired llamastack.RegisterToolGroupRequest) bool {
+	return current.ProviderID == desired.ProviderID &&
+		current.MCPEndpoint != nil && current.MCPEndpoint.URI == desired.MCPEndpoint.URI
+}
+
+// setToolGroupCondition sets a condition of the tool group.
+func setToolGroupCondition(toolGroup *llamav1alpha1.LlamaStackToolGroup, conditionType string, value bool, reason, message string) {
+	status := metav1.ConditionTrue
+	if !value {
+		status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(&toolGroup.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: toolGroup.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LlamaStackToolGroupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &llamav1alpha1.LlamaStackToolGroup{}, distributionRefIndex,
+		func(obj client.Object) []string {
+			toolGroup, ok := obj.(*llamav1alpha1.LlamaStackToolGroup)
+			if !ok {
+				return nil
+			}
+			return []string{toolGroup.Spec.DistributionRef.Name}
+		}); err != nil {
+		return fmt.Errorf("failed to create LlamaStackToolGroup distribution index: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&llamav1alpha1.LlamaStackToolGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Watches(
+			&llamav1alpha1.LlamaStackDistribution{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return requestsForDistribution(ctx, r.Client, obj, &llamav1alpha1.LlamaStackToolGroupList{})
+			}),
+			builder.WithPredicates(distributionServingChanged()),
+		).
+		Complete(r)
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLlamaStackToolGroupReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, llamav1alpha1.AddToScheme(scheme))
+
+	distribution := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "tg-ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{ContainerSpec: llamav1alpha1.ContainerSpec{Port: 8321}},
+		},
+		Status: llamav1alpha1.LlamaStackDistributionStatus{Phase: llamav1alpha1.LlamaStackDistributionPhaseReady},
+	}
+	toolGroup := &llamav1alpha1.LlamaStackToolGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "time", Namespace: "tg-ns", UID: "time-uid", Generation: 1},
+		Spec: llamav1alpha1.LlamaStackToolGroupSpec{
+			DistributionRef: llamav1alpha1.DistributionReference{Name: "llsd"},
+			Server:          &llamav1alpha1.MCPServerSpec{Image: "mcp-time:latest"},
+		},
+	}
+	k8sClient := clientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(distribution, toolGroup).
+		WithStatusSubresource(distribution, toolGroup).
+		Build()
+
+	server := fake.NewServer()
+	defer server.Close()
+	deployedEndpoint := "http://time-mcp.tg-ns.svc.cluster.local:8000/sse"
+	externalEndpoint := "http://time.tools.svc:9000/mcp"
+
+	recorder := record.NewFakeRecorder(10)
+	r := NewLlamaStackToolGroupReconciler(k8sClient, scheme, recorder)
+	r.httpClient = server.HTTPClient()
+
+	ctx := context.Background() //nolint:usetesting // the reconciler is shared by the subtests
+	key := types.NamespacedName{Name: "time", Namespace: "tg-ns"}
+	serverKey := types.NamespacedName{Name: "time-mcp", Namespace: "tg-ns"}
+	reconcile := func(t *testing.T) *llamav1alpha1.LlamaStackToolGroup {
+		t.Helper()
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		current := &llamav1alpha1.LlamaStackToolGroup{}
+		if err := k8sClient.Get(ctx, key, current); err != nil {
+			require.True(t, client.IgnoreNotFound(err) == nil, "unexpected error: %v", err)
+			return nil
+		}
+		return current
+	}
+	condition := func(current *llamav1alpha1.LlamaStackToolGroup, conditionType string) *metav1.Condition {
+		c := meta.FindStatusCondition(current.Status.Conditions, conditionType)
+		require.NotNil(t, c, "missing condition %s", conditionType)
+		return c
+	}
+
+	t.Run("deploys the MCP server and waits for it", func(t *testing.T) {
+		current := reconcile(t)
+
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, k8sClient.Get(ctx, serverKey, deployment))
+		assert.Equal(t, "mcp-time:latest", deployment.Spec.Template.Spec.Containers[0].Image)
+		assert.True(t, metav1.IsControlledBy(deployment, current))
+		require.NoError(t, k8sClient.Get(ctx, serverKey, &corev1.Service{}))
+		policy := &networkingv1.NetworkPolicy{}
+		require.NoError(t, k8sClient.Get(ctx, serverKey, policy))
+		assert.Equal(t, "llsd", policy.Spec.Ingress[0].From[0].PodSelector.MatchLabels["app.kubernetes.io/instance"])
+
+		assert.Equal(t, deployedEndpoint, current.Status.Endpoint)
+		assert.Equal(t, ReasonMCPServerNotReady, condition(current, llamav1alpha1.ConditionTypeReady).Reason)
+		assert.Empty(t, server.ToolGroups(), "the tool group is registered once the server is available")
+	})
+
+	t.Run("registers the tool group once the MCP server is available", func(t *testing.T) {
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, k8sClient.Get(ctx, serverKey, deployment))
+		deployment.Status.AvailableReplicas = 1
+		require.NoError(t, k8sClient.Status().Update(ctx, deployment))
+		server.SetMCPTools(deployedEndpoint, []string{"get_current_time", "convert_time"})
+
+		current := reconcile(t)
+
+		require.Len(t, server.ToolGroups(), 1)
+		assert.Equal(t, "mcp::time", server.ToolGroups()[0].Identifier)
+		assert.Equal(t, deployedEndpoint, server.ToolGroups()[0].MCPEndpoint.URI)
+		assert.Equal(t, "mcp::time", current.Status.RegisteredToolGroupID)
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeReady))
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeAvailable))
+		require.NotNil(t, current.Status.Tools)
+		assert.Equal(t, []string{"convert_time", "get_current_time"}, current.Status.Tools.Identifiers)
+
+		require.Len(t, recorder.Events, 2)
+		assert.Equal(t, "Normal Registered Registered tool group mcp::time", <-recorder.Events)
+		assert.Equal(t, "Normal ToolsAvailable Tool group mcp::time has 2 tools available", <-recorder.Events)
+	})
+
+	t.Run("reports unavailable tools", func(t *testing.T) {
+		server.SetMCPTools(deployedEndpoint, nil)
+		current := reconcile(t)
+
+		available := condition(current, llamav1alpha1.ConditionTypeAvailable)
+		assert.Equal(t, metav1.ConditionFalse, available.Status)
+		assert.Equal(t, ReasonToolsUnavailable, available.Reason)
+		assert.Nil(t, current.Status.Tools)
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeReady), "the tool group is still registered")
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning ToolsUnavailable Tools of tool group mcp::time are unavailable")
+
+		reconcile(t)
+		assert.Empty(t, recorder.Events, "no Event without an availability change")
+	})
+
+	t.Run("switches to an external endpoint", func(t *testing.T) {
+		server.SetMCPTools(externalEndpoint, []string{"get_current_time"})
+		current := &llamav1alpha1.LlamaStackToolGroup{}
+		require.NoError(t, k8sClient.Get(ctx, key, current))
+		current.Spec.Server = nil
+		current.Spec.Endpoint = externalEndpoint
+		require.NoError(t, k8sClient.Update(ctx, current))
+
+		current = reconcile(t)
+
+		assert.Equal(t, externalEndpoint, server.ToolGroups()[0].MCPEndpoint.URI, "the tool group is registered again with the new endpoint")
+		assert.Equal(t, externalEndpoint, current.Status.Endpoint)
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeAvailable))
+		err := k8sClient.Get(ctx, serverKey, &appsv1.Deployment{})
+		assert.True(t, client.IgnoreNotFound(err) == nil && err != nil, "the MCP server is deleted")
+		require.Len(t, recorder.Events, 2)
+		assert.Equal(t, "Normal Registered Registered tool group mcp::time", <-recorder.Events)
+		assert.Equal(t, "Normal ToolsAvailable Tool group mcp::time has 1 tools available", <-recorder.Events)
+	})
+
+	t.Run("registers the tool group again after a server restart", func(t *testing.T) {
+		server.Restart()
+		reconcile(t)
+
+		require.Len(t, server.ToolGroups(), 1)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal Reregistered Tool group mcp::time was missing from the server and has been registered again", <-recorder.Events)
+	})
+
+	t.Run("unregisters the tool group on deletion", func(t *testing.T) {
+		current := &llamav1alpha1.LlamaStackToolGroup{}
+		require.NoError(t, k8sClient.Get(ctx, key, current))
+		require.NoError(t, k8sClient.Delete(ctx, current))
+
+		assert.Nil(t, reconcile(t), "the finalizer is removed once the tool group is unregistered")
+		assert.Empty(t, server.ToolGroups())
+		assert.Equal(t, "Normal Unregistered Unregistered tool group mcp::time", <-recorder.Events)
+	})
+}
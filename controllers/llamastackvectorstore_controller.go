@@ -0,0 +1,292 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// vectorStoreOwnerMetadataKey tags the vector stores created by the operator with the UID of their
+// resource, so a store created just before a failed status update is adopted instead of duplicated.
+const vectorStoreOwnerMetadataKey = "llamastack.io/uid"
+
+// Condition reasons of the ingestion in LlamaStackVectorStores.
+const (
+	// ReasonIngesting indicates the ingestion Job is running.
+	ReasonIngesting = "Ingesting"
+	// ReasonIngestionFailed indicates the ingestion Job failed or could not ingest every document.
+	ReasonIngestionFailed = "IngestionFailed"
+)
+
+// Event reasons of the ingestion in LlamaStackVectorStores.
+const (
+	// EventReasonIngestionStarted is emitted when an ingestion Job is created.
+	EventReasonIngestionStarted = "IngestionStarted"
+	// EventReasonIngestionSucceeded is emitted when every document was ingested.
+	EventReasonIngestionSucceeded = "IngestionSucceeded"
+	// EventReasonIngestionFailed is emitted when the ingestion Job failed.
+	EventReasonIngestionFailed = "IngestionFailed"
+)
+
+// LlamaStackVectorStoreReconciler creates LlamaStackVectorStores in the server of the referenced
+// distribution and ingests their documents with a Job. A vector store lost by the server, e.g.
+// after its pods restarted without persistent storage, is created again and its documents ingested again.
+type LlamaStackVectorStoreReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Recorder publishes Events on the reconciled vector stores
+	Recorder record.EventRecorder
+	// apiReader reads the pods of the ingestion Jobs, which are not cached
+	apiReader client.Reader
+	// ingestionImage is the image of the ingestion Jobs, the operator image
+	ingestionImage string
+	httpClient     *http.Client
+}
+
+// NewLlamaStackVectorStoreReconciler creates a new vector store reconciler.
+func NewLlamaStackVectorStoreReconciler(client client.Client, apiReader client.Reader, scheme *runtime.Scheme,
+	recorder record.EventRecorder, ingestionImage string) *LlamaStackVectorStoreReconciler {
+	return &LlamaStackVectorStoreReconciler{
+		Client:         client,
+		Scheme:         scheme,
+		Recorder:       recorder,
+		apiReader:      apiReader,
+		ingestionImage: ingestionImage,
+		httpClient:     newRegistrationHTTPClient(),
+	}
+}
+
+// Reconcile creates the vector store and ingests its documents, or deletes it when it is deleted.
+func (r *LlamaStackVectorStoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("namespace", req.Namespace, "name", req.Name)
+	ctx = logr.NewContext(ctx, logger)
+
+	store := &llamav1alpha1.LlamaStackVectorStore{}
+	if err := r.Get(ctx, req.NamespacedName, store); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch LlamaStackVectorStore: %w", err)
+	}
+
+	if !store.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, store)
+	}
+
+	if controllerutil.AddFinalizer(store, llamav1alpha1.RegistrationFinalizer) {
+		if err := r.Update(ctx, store); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	result, reconcileErr := r.reconcileVectorStore(ctx, store)
+
+	store.Status.ObservedGeneration = store.Generation
+	if err := r.Status().Update(ctx, store); err != nil {
+		if reconcileErr != nil {
+			return ctrl.Result{}, reconcileErr
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+	return result, reconcileErr
+}
+
+// reconcileVectorStore makes sure the vector store exists and its documents are ingested, and sets the Ready condition.
+func (r *LlamaStackVectorStoreReconciler) reconcileVectorStore(ctx context.Context, store *llamav1alpha1.LlamaStackVectorStore) (ctrl.Result, error) {
+	distribution, reason, message, err := getServingDistribution(ctx, r.Client, store.Namespace, store.Spec.DistributionRef)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if distribution == nil {
+		// The distribution watch requeues the vector store once it is ready.
+		setVectorStoreReady(store, false, reason, message)
+		return ctrl.Result{}, nil
+	}
+
+	server, err := newServerClient(r.httpClient, distribution)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.ensureVectorStore(ctx, server, store); err != nil {
+		setVectorStoreReady(store, false, ReasonRegistrationFailed, err.Error())
+		r.Recorder.Eventf(store, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+			"Failed to create vector store %s: %v", store.GetVectorStoreName(), err)
+		return ctrl.Result{}, err
+	}
+
+	if store.Spec.Ingestion == nil {
+		store.Status.Ingestion = nil
+	} else {
+		if r.ingestionImage == "" {
+			setVectorStoreReady(store, false, ReasonIngestionFailed,
+				"The ingestion image is unknown, start the operator with --ingestion-image")
+			return ctrl.Result{}, nil
+		}
+		if err := r.ensureIngestion(ctx, store, distribution); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		switch ingestion := store.Status.Ingestion; ingestion.Phase {
+		case llamav1alpha1.IngestionPhaseFailed:
+			setVectorStoreReady(store, false, ReasonIngestionFailed, ingestion.Message)
+			return ctrl.Result{RequeueAfter: registrationResyncInterval()}, nil
+		case llamav1alpha1.IngestionPhasePending, llamav1alpha1.IngestionPhaseRunning:
+			// The Job watch requeues the vector store when the Job progresses.
+			setVectorStoreReady(store, false, ReasonIngesting, fmt.Sprintf("Ingestion Job %s is %s", ingestion.JobName, ingestion.Phase))
+			return ctrl.Result{}, nil
+		}
+	}
+
+	setVectorStoreReady(store, true, ReasonRegistered,
+		fmt.Sprintf("Vector store %s is ready in LlamaStackDistribution %s", store.Status.VectorStoreID, distribution.Name))
+	return ctrl.Result{RequeueAfter: registrationResyncInterval()}, nil
+}
+
+// ensureVectorStore creates the vector store if the server does not know it.
+func (r *LlamaStackVectorStoreReconciler) ensureVectorStore(ctx context.Context, server *llamastack.Client, store *llamav1alpha1.LlamaStackVectorStore) error {
+	logger := log.FromContext(ctx)
+
+	previous := store.Status.VectorStoreID
+	if previous != "" {
+		_, err := server.GetVectorStore(ctx, previous)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, llamastack.ErrNotFound) {
+			return err
+		}
+	}
+
+	stores, err := server.ListVectorStores(ctx)
+	if err != nil {
+		return err
+	}
+	for _, existing := range stores {
+		if existing.Metadata[vectorStoreOwnerMetadataKey] == string(store.UID) {
+			logger.Info("Adopted existing vector store", "vectorStoreID", existing.ID)
+			store.Status.VectorStoreID = existing.ID
+			return nil
+		}
+	}
+
+	created, err := server.CreateVectorStore(ctx, llamastack.CreateVectorStoreRequest{
+		Name:               store.GetVectorStoreName(),
+		EmbeddingModel:     store.Spec.EmbeddingModel,
+		EmbeddingDimension: store.Spec.EmbeddingDimension,
+		ProviderID:         store.Spec.ProviderID,
+		Metadata:           map[string]any{vectorStoreOwnerMetadataKey: string(store.UID)},
+	})
+	if err != nil {
+		return err
+	}
+	store.Status.VectorStoreID = created.ID
+
+	if previous != "" {
+		logger.Info("Vector store was missing from the server, created it again", "previous", previous, "vectorStoreID", created.ID)
+		r.Recorder.Eventf(store, corev1.EventTypeNormal, EventReasonReregistered,
+			"Vector store %s was missing from the server and has been created again as %s", previous, created.ID)
+	} else {
+		logger.Info("Created vector store", "vectorStoreID", created.ID)
+		r.Recorder.Eventf(store, corev1.EventTypeNormal, EventReasonRegistered, "Created vector store %s", created.ID)
+	}
+	return nil
+}
+
+// finalize deletes the vector store and removes the finalizer. A vector store in a distribution
+// that is gone or not serving is left in place, since nothing could remove it. The ingestion Jobs
+// are owned by the resource and garbage collected.
+func (r *LlamaStackVectorStoreReconciler) finalize(ctx context.Context, store *llamav1alpha1.LlamaStackVectorStore) error {
+	if !controllerutil.ContainsFinalizer(store, llamav1alpha1.RegistrationFinalizer) {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	if id := store.Status.VectorStoreID; id != "" {
+		distribution, _, message, err := getServingDistribution(ctx, r.Client, store.Namespace, store.Spec.DistributionRef)
+		if err != nil {
+			return err
+		}
+
+		if distribution == nil {
+			logger.Info("Skipping vector store deletion", "vectorStoreID", id, "reason", message)
+		} else {
+			server, err := newServerClient(r.httpClient, distribution)
+			if err != nil {
+				return err
+			}
+			if err := llamastack.IgnoreNotFound(server.DeleteVectorStore(ctx, id)); err != nil {
+				r.Recorder.Eventf(store, corev1.EventTypeWarning, EventReasonRegistrationFailed,
+					"Failed to delete vector store %s: %v", id, err)
+				return fmt.Errorf("failed to delete vector store %s: %w", id, err)
+			}
+			logger.Info("Deleted vector store", "vectorStoreID", id)
+			r.Recorder.Eventf(store, corev1.EventTypeNormal, EventReasonUnregistered, "Deleted vector store %s", id)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(store, llamav1alpha1.RegistrationFinalizer)
+	if err := r.Update(ctx, store); err != nil {
+		return fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+// setVectorStoreReady sets the Ready condition of the vector store.
+func setVectorStoreReady(store *llamav1alpha1.LlamaStackVectorStore, ready bool, reason, message string) {
+	status := metav1.ConditionTrue
+	if !ready {
+		status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(&store.Status.Conditions, metav1.Condition{
+		Type:               llamav1alpha1.ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: store.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LlamaStackVectorStoreReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &llamav1alpha1.LlamaStackVectorStore{}, distributionRefIndex,
+		func(obj client.Object) []string {
+			store, ok := obj.(*llamav1alpha1.LlamaStackVectorStore)
+			if !ok {
+				return nil
+			}
+			return []string{store.Spec.DistributionRef.Name}
+		}); err != nil {
+		return fmt.Errorf("failed to create LlamaStackVectorStore distribution index: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&llamav1alpha1.LlamaStackVectorStore{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&batchv1.Job{}).
+		Watches(
+			&llamav1alpha1.LlamaStackDistribution{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return requestsForDistribution(ctx, r.Client, obj, &llamav1alpha1.LlamaStackVectorStoreList{})
+			}),
+			builder.WithPredicates(distributionServingChanged()),
+		).
+		Complete(r)
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestVectorStore() *llamav1alpha1.LlamaStackVectorStore {
+	return &llamav1alpha1.LlamaStackVectorStore{
+		ObjectMeta: metav1.ObjectMeta{Name: "docs", Namespace: "vs-ns", UID: "docs-uid", Generation: 1},
+		Spec: llamav1alpha1.LlamaStackVectorStoreSpec{
+			DistributionRef: llamav1alpha1.DistributionReference{Name: "llsd"},
+			EmbeddingModel:  "all-minilm",
+			ProviderID:      "faiss",
+			Ingestion: &llamav1alpha1.IngestionSpec{
+				ChunkSize: 256,
+				Sources: []llamav1alpha1.IngestionSource{
+					{ConfigMap: &llamav1alpha1.ConfigMapIngestionSource{Name: "guides", Keys: []string{"install.md"}}},
+					{PersistentVolumeClaim: &llamav1alpha1.PVCIngestionSource{ClaimName: "corpus", Path: "manuals"}},
+					{URLs: []string{"https://example.com/faq.txt"}},
+				},
+			},
+		},
+	}
+}
+
+func TestLlamaStackVectorStoreReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, llamav1alpha1.AddToScheme(scheme))
+
+	distribution := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "vs-ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{ContainerSpec: llamav1alpha1.ContainerSpec{Port: 8321}},
+		},
+		Status: llamav1alpha1.LlamaStackDistributionStatus{Phase: llamav1alpha1.LlamaStackDistributionPhaseReady},
+	}
+	store := newTestVectorStore()
+	k8sClient := clientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(distribution, store).
+		WithStatusSubresource(distribution, store).
+		Build()
+
+	server := fake.NewServer()
+	defer server.Close()
+	registerEmbeddingModel := func(t *testing.T) {
+		t.Helper()
+		_, err := server.Client().RegisterModel(t.Context(), llamastack.RegisterModelRequest{ModelID: "all-minilm", ModelType: llamastack.ModelTypeEmbedding})
+		require.NoError(t, err)
+	}
+	registerEmbeddingModel(t)
+
+	recorder := record.NewFakeRecorder(10)
+	r := NewLlamaStackVectorStoreReconciler(k8sClient, k8sClient, scheme, recorder, "operator:test")
+	r.httpClient = server.HTTPClient()
+
+	ctx := context.Background() //nolint:usetesting // the reconciler is shared by the subtests
+	key := types.NamespacedName{Name: "docs", Namespace: "vs-ns"}
+	reconcile := func(t *testing.T) *llamav1alpha1.LlamaStackVectorStore {
+		t.Helper()
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		current := &llamav1alpha1.LlamaStackVectorStore{}
+		if err := k8sClient.Get(ctx, key, current); err != nil {
+			require.True(t, client.IgnoreNotFound(err) == nil, "unexpected error: %v", err)
+			return nil
+		}
+		return current
+	}
+	completeJob := func(t *testing.T, jobName, result string) {
+		t.Helper()
+		job := &batchv1.Job{}
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: jobName, Namespace: "vs-ns"}, job))
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})
+		require.NoError(t, k8sClient.Status().Update(ctx, job))
+		require.NoError(t, k8sClient.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: jobName + "-pod", Namespace: "vs-ns", Labels: map[string]string{jobNameLabel: jobName}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  ingestionContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: result}},
+			}}},
+		}))
+	}
+
+	var firstJob string
+	t.Run("creates the vector store and starts the ingestion", func(t *testing.T) {
+		current := reconcile(t)
+
+		require.Len(t, server.VectorStores(), 1)
+		assert.Equal(t, server.VectorStores()[0].ID, current.Status.VectorStoreID)
+		require.NotNil(t, current.Status.Ingestion)
+		assert.Equal(t, llamav1alpha1.IngestionPhasePending, current.Status.Ingestion.Phase)
+		firstJob = current.Status.Ingestion.JobName
+
+		condition := meta.FindStatusCondition(current.Status.Conditions, llamav1alpha1.ConditionTypeReady)
+		require.NotNil(t, condition)
+		assert.Equal(t, ReasonIngesting, condition.Reason)
+		assert.Equal(t, "Normal Registered Created vector store vs_1", <-recorder.Events)
+		assert.Equal(t, "Normal IngestionStarted Created ingestion Job "+firstJob, <-recorder.Events)
+	})
+
+	t.Run("reports the ingestion result", func(t *testing.T) {
+		completeJob(t, firstJob, `{"documents":3,"failedDocuments":0,"chunks":42}`)
+		current := reconcile(t)
+
+		ingestion := current.Status.Ingestion
+		assert.Equal(t, llamav1alpha1.IngestionPhaseSucceeded, ingestion.Phase)
+		assert.Equal(t, int32(3), ingestion.Documents)
+		assert.Equal(t, int32(42), ingestion.Chunks)
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeReady))
+		assert.Equal(t, "Normal IngestionSucceeded Ingested 3 documents in 42 chunks", <-recorder.Events)
+	})
+
+	t.Run("creates the vector store again and ingests again after a server restart", func(t *testing.T) {
+		server.Restart()
+		registerEmbeddingModel(t)
+		current := reconcile(t)
+
+		assert.Equal(t, "vs_2", current.Status.VectorStoreID)
+		assert.NotEqual(t, firstJob, current.Status.Ingestion.JobName, "a new vector store gets a new ingestion")
+		assert.Equal(t, llamav1alpha1.IngestionPhasePending, current.Status.Ingestion.Phase)
+		assert.Equal(t, "Normal Reregistered Vector store vs_1 was missing from the server and has been created again as vs_2", <-recorder.Events)
+		assert.Equal(t, "Normal IngestionStarted Created ingestion Job "+current.Status.Ingestion.JobName, <-recorder.Events)
+
+		jobs := &batchv1.JobList{}
+		require.NoError(t, k8sClient.List(ctx, jobs, client.InNamespace("vs-ns")))
+		require.Len(t, jobs.Items, 1, "the Job of the previous ingestion is deleted")
+		assert.Equal(t, current.Status.Ingestion.JobName, jobs.Items[0].Name)
+	})
+
+	t.Run("deletes the vector store on deletion", func(t *testing.T) {
+		current := &llamav1alpha1.LlamaStackVectorStore{}
+		require.NoError(t, k8sClient.Get(ctx, key, current))
+		require.NoError(t, k8sClient.Delete(ctx, current))
+
+		assert.Nil(t, reconcile(t), "the finalizer is removed once the vector store is deleted")
+		assert.Empty(t, server.VectorStores())
+		assert.Equal(t, "Normal Unregistered Deleted vector store vs_2", <-recorder.Events)
+	})
+}
+
+func TestBuildIngestionJob(t *testing.T) {
+	distribution := &llamav1alpha1.LlamaStackDistribution{ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "vs-ns"}}
+	store := newTestVectorStore()
+	store.Status.VectorStoreID = "vs_1"
+
+	job := buildIngestionJob(store, distribution, "docs-ingest-0123456789", "operator:test")
+
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{
+		"ingest",
+		"--server-url=http://llsd-service.vs-ns.svc.cluster.local:8321",
+		"--vector-store-id=vs_1",
+		"--chunk-size=256",
+		"--chunk-overlap=0",
+		"--path=/ingest/source-0",
+		"--path=/ingest/source-1/manuals",
+		"--url=https://example.com/faq.txt",
+	}, container.Args)
+	require.Len(t, job.Spec.Template.Spec.Volumes, 2)
+	assert.Equal(t, []corev1.KeyToPath{{Key: "install.md", Path: "install.md"}}, job.Spec.Template.Spec.Volumes[0].ConfigMap.Items)
+	assert.True(t, job.Spec.Template.Spec.Volumes[1].PersistentVolumeClaim.ReadOnly)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit, "a retry would insert the chunks twice")
+	assert.Equal(t, "llama-stack", job.Spec.Template.Labels["app.kubernetes.io/part-of"], "allowed by the distribution NetworkPolicy")
+}
+
+func TestIngestionJobName(t *testing.T) {
+	store := newTestVectorStore()
+	store.Status.VectorStoreID = "vs_1"
+	name, err := ingestionJobName(store)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(name, "docs-ingest-"))
+
+	store.Spec.Ingestion.ChunkSize = 128
+	changed, err := ingestionJobName(store)
+	require.NoError(t, err)
+	assert.NotEqual(t, name, changed, "a spec change runs the ingestion again")
+
+	store.Name = strings.Repeat("a", 80)
+	long, err := ingestionJobName(store)
+	require.NoError(t, err)
+	assert.Len(t, long, maxJobNameLength)
+}
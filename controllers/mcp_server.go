@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// toolGroupLabel selects the MCP server resources of a tool group.
+	toolGroupLabel = "llamastack.io/toolgroup"
+	// mcpServerContainerName is the name of the MCP server container.
+	mcpServerContainerName = "mcp-server"
+	// mcpServerSuffix is appended to the tool group name to name the MCP server resources.
+	mcpServerSuffix = "-mcp"
+)
+
+// mcpServerName returns the name of the Deployment, Service and NetworkPolicy of the MCP server.
+func mcpServerName(toolGroup *llamav1alpha1.LlamaStackToolGroup) string {
+	return toolGroup.Name + mcpServerSuffix
+}
+
+// mcpServerEndpoint returns the in-cluster URL of the MCP server deployed for the tool group.
+func mcpServerEndpoint(toolGroup *llamav1alpha1.LlamaStackToolGroup) string {
+	server := toolGroup.Spec.Server
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d%s",
+		mcpServerName(toolGroup), toolGroup.Namespace, mcpServerPort(server), mcpServerPath(server))
+}
+
+func mcpServerPort(server *llamav1alpha1.MCPServerSpec) int32 {
+	if server.Port == 0 {
+		return llamav1alpha1.DefaultMCPServerPort
+	}
+	return server.Port
+}
+
+func mcpServerPath(server *llamav1alpha1.MCPServerSpec) string {
+	if server.Path == "" {
+		return llamav1alpha1.DefaultMCPServerPath
+	}
+	return server.Path
+}
+
+func mcpServerLabels(toolGroup *llamav1alpha1.LlamaStackToolGroup) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "mcp-server",
+		"app.kubernetes.io/managed-by": "llama-stack-operator",
+		toolGroupLabel:                 toolGroup.Name,
+	}
+}
+
+// ensureMCPServer creates or updates the Deployment, Service and NetworkPolicy of the MCP server of
+// the tool group, and returns whether the server has an available replica.
+func (r *LlamaStackToolGroupReconciler) ensureMCPServer(ctx context.Context, toolGroup *llamav1alpha1.LlamaStackToolGroup) (bool, error) {
+	desiredDeployment := buildMCPServerDeployment(toolGroup)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: desiredDeployment.Name, Namespace: desiredDeployment.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		deployment.Labels = desiredDeployment.Labels
+		// The selector is immutable, keep the one of an existing Deployment.
+		if deployment.Spec.Selector == nil {
+			deployment.Spec.Selector = desiredDeployment.Spec.Selector
+		}
+		deployment.Spec.Replicas = desiredDeployment.Spec.Replicas
+		deployment.Spec.Template = desiredDeployment.Spec.Template
+		return controllerutil.SetControllerReference(toolGroup, deployment, r.Scheme)
+	}); err != nil {
+		return false, fmt.Errorf("failed to reconcile MCP server Deployment: %w", err)
+	}
+
+	desiredService := buildMCPServerService(toolGroup)
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: desiredService.Name, Namespace: desiredService.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		service.Labels = desiredService.Labels
+		service.Spec.Selector = desiredService.Spec.Selector
+		service.Spec.Ports = desiredService.Spec.Ports
+		return controllerutil.SetControllerReference(toolGroup, service, r.Scheme)
+	}); err != nil {
+		return false, fmt.Errorf("failed to reconcile MCP server Service: %w", err)
+	}
+
+	desiredPolicy := buildMCPServerNetworkPolicy(toolGroup)
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: desiredPolicy.Name, Namespace: desiredPolicy.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		policy.Labels = desiredPolicy.Labels
+		policy.Spec = desiredPolicy.Spec
+		return controllerutil.SetControllerReference(toolGroup, policy, r.Scheme)
+	}); err != nil {
+		return false, fmt.Errorf("failed to reconcile MCP server NetworkPolicy: %w", err)
+	}
+
+	return deployment.Status.AvailableReplicas > 0, nil
+}
+
+// deleteMCPServer deletes the MCP server resources of a tool group that switched to an external endpoint.
+func (r *LlamaStackToolGroupReconciler) deleteMCPServer(ctx context.Context, toolGroup *llamav1alpha1.LlamaStackToolGroup) error {
+	objects := []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &networkingv1.NetworkPolicy{}}
+	for _, obj := range objects {
+		if err := r.Get(ctx, client.ObjectKey{Name: mcpServerName(toolGroup), Namespace: toolGroup.Namespace}, obj); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return fmt.Errorf("failed to fetch MCP server resource: %w", err)
+		}
+		if !metav1.IsControlledBy(obj, toolGroup) {
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Delete(ctx, obj)); err != nil {
+			return fmt.Errorf("failed to delete MCP server resource %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// buildMCPServerDeployment returns the Deployment running the MCP server container.
+func buildMCPServerDeployment(toolGroup *llamav1alpha1.LlamaStackToolGroup) *appsv1.Deployment {
+	server := toolGroup.Spec.Server
+	labels := mcpServerLabels(toolGroup)
+
+	resources := server.Resources
+	if resources.Requests == nil && resources.Limits == nil {
+		resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		}
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName(toolGroup),
+			Namespace: toolGroup.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{toolGroupLabel: toolGroup.Name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers: []corev1.Container{{
+						Name:    mcpServerContainerName,
+						Image:   server.Image,
+						Command: server.Command,
+						Args:    server.Args,
+						Env:     server.Env,
+						Ports: []corev1.ContainerPort{{
+							Name:          "mcp",
+							ContainerPort: mcpServerPort(server),
+							Protocol:      corev1.ProtocolTCP,
+						}},
+						Resources: resources,
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(mcpServerPort(server))},
+							},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// buildMCPServerService returns the Service the distribution reaches the MCP server through.
+func buildMCPServerService(toolGroup *llamav1alpha1.LlamaStackToolGroup) *corev1.Service {
+	port := mcpServerPort(toolGroup.Spec.Server)
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName(toolGroup),
+			Namespace: toolGroup.Namespace,
+			Labels:    mcpServerLabels(toolGroup),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{toolGroupLabel: toolGroup.Name},
+			Ports: []corev1.ServicePort{{
+				Name:       "mcp",
+				Port:       port,
+				TargetPort: intstr.FromString("mcp"),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// buildMCPServerNetworkPolicy returns the NetworkPolicy only allowing the pods of the referenced
+// distribution to reach the MCP server.
+func buildMCPServerNetworkPolicy(toolGroup *llamav1alpha1.LlamaStackToolGroup) *networkingv1.NetworkPolicy {
+	port := intstr.FromInt32(mcpServerPort(toolGroup.Spec.Server))
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServerName(toolGroup),
+			Namespace: toolGroup.Namespace,
+			Labels:    mcpServerLabels(toolGroup),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{toolGroupLabel: toolGroup.Name}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+						"app":                        "llama-stack",
+						"app.kubernetes.io/instance": toolGroup.Spec.DistributionRef.Name,
+					}},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{{
+					Protocol: ptr.To(corev1.ProtocolTCP),
+					Port:     &port,
+				}},
+			}},
+		},
+	}
+}
+
+// mcpServerMessage describes a deployed MCP server that is not available yet.
+func mcpServerMessage(toolGroup *llamav1alpha1.LlamaStackToolGroup) string {
+	return "MCP server Deployment " + mcpServerName(toolGroup) + " has no available replica on port " +
+		strconv.Itoa(int(mcpServerPort(toolGroup.Spec.Server)))
+}
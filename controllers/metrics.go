@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// providerHealthOK is the health status reported by a healthy provider.
+	providerHealthOK = "OK"
+
+	// Status poll endpoints used as metric label values.
+	statusPollProviders = "providers"
+	statusPollVersion   = "version"
+	statusPollModels    = "models"
+	statusPollShields   = "shields"
+	statusPollVectorDBs = "vector_dbs"
+
+	// ConfigMap kinds used as metric label values.
+	configMapKindUserConfig = "user-config"
+	configMapKindCABundle   = "ca-bundle"
+)
+
+// allPhases lists every phase so the phase gauge reports 0 for the phases a distribution is not in.
+var allPhases = []llamav1alpha1.DistributionPhase{
+	llamav1alpha1.LlamaStackDistributionPhasePending,
+	llamav1alpha1.LlamaStackDistributionPhaseInitializing,
+	llamav1alpha1.LlamaStackDistributionPhaseReady,
+	llamav1alpha1.LlamaStackDistributionPhaseFailed,
+	llamav1alpha1.LlamaStackDistributionPhaseTerminating,
+	llamav1alpha1.LlamaStackDistributionPhaseSuspended,
+	llamav1alpha1.LlamaStackDistributionPhasePaused,
+}
+
+var (
+	distributionPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "llamastack_distribution_phase",
+		Help: "Phase of the LlamaStackDistribution, 1 for the current phase and 0 for the others.",
+	}, []string{"namespace", "name", "phase"})
+
+	distributionReadyReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "llamastack_distribution_ready_replicas",
+		Help: "Number of ready server replicas of the LlamaStackDistribution.",
+	}, []string{"namespace", "name"})
+
+	distributionDesiredReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "llamastack_distribution_desired_replicas",
+		Help: "Number of server replicas the LlamaStackDistribution should run.",
+	}, []string{"namespace", "name"})
+
+	providerHealth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "llamastack_distribution_provider_health",
+		Help: "Health of a provider reported by the LlamaStack server, 1 when OK and 0 otherwise.",
+	}, []string{"namespace", "name", "api", "provider_id"})
+
+	statusPollDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "llamastack_operator_status_poll_duration_seconds",
+		Help:    "Latency of the HTTP requests polling the LlamaStack server for status, by endpoint and result.",
+		Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"endpoint", "result"})
+
+	statusPollErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "llamastack_operator_status_poll_errors_total",
+		Help: "Number of failed HTTP requests polling the LlamaStack server for status.",
+	}, []string{"namespace", "name", "endpoint"})
+
+	configMapRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "llamastack_distribution_configmap_restarts_total",
+		Help: "Number of server restarts triggered by a change to a referenced ConfigMap.",
+	}, []string{"namespace", "name", "configmap_kind"})
+)
+
+func init() { //nolint:gochecknoinits
+	// Register with the controller-runtime registry so the collectors are served on the manager's metrics endpoint.
+	metrics.Registry.MustRegister(
+		distributionPhase,
+		distributionReadyReplicas,
+		distributionDesiredReplicas,
+		providerHealth,
+		statusPollDuration,
+		statusPollErrors,
+		configMapRestarts,
+	)
+}
+
+// recordDistributionMetrics publishes the gauges derived from the status of a distribution.
+func recordDistributionMetrics(instance *llamav1alpha1.LlamaStackDistribution, desiredReplicas int32) {
+	for _, phase := range allPhases {
+		value := 0.0
+		if instance.Status.Phase == phase {
+			value = 1
+		}
+		distributionPhase.WithLabelValues(instance.Namespace, instance.Name, string(phase)).Set(value)
+	}
+	distributionReadyReplicas.WithLabelValues(instance.Namespace, instance.Name).Set(float64(instance.Status.AvailableReplicas))
+	distributionDesiredReplicas.WithLabelValues(instance.Namespace, instance.Name).Set(float64(desiredReplicas))
+
+	// Drop providers that are no longer reported.
+	providerHealth.DeletePartialMatch(prometheus.Labels{"namespace": instance.Namespace, "name": instance.Name})
+	for _, provider := range instance.Status.DistributionConfig.Providers {
+		value := 0.0
+		if provider.Health.Status == providerHealthOK {
+			value = 1
+		}
+		providerHealth.WithLabelValues(instance.Namespace, instance.Name, provider.API, provider.ProviderID).Set(value)
+	}
+}
+
+// deleteDistributionMetrics removes all series of a deleted distribution.
+func deleteDistributionMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	distributionPhase.DeletePartialMatch(labels)
+	distributionReadyReplicas.DeletePartialMatch(labels)
+	distributionDesiredReplicas.DeletePartialMatch(labels)
+	providerHealth.DeletePartialMatch(labels)
+	statusPollErrors.DeletePartialMatch(labels)
+	configMapRestarts.DeletePartialMatch(labels)
+}
+
+// observeStatusPoll records the latency and outcome of a request polling the server status.
+func observeStatusPoll(instance *llamav1alpha1.LlamaStackDistribution, endpoint string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+		statusPollErrors.WithLabelValues(instance.Namespace, instance.Name, endpoint).Inc()
+	}
+	statusPollDuration.WithLabelValues(endpoint, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordDistributionMetrics(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics-test", Namespace: "metrics-ns"},
+		Status: llamav1alpha1.LlamaStackDistributionStatus{
+			Phase:             llamav1alpha1.LlamaStackDistributionPhaseReady,
+			AvailableReplicas: 2,
+			DistributionConfig: llamav1alpha1.DistributionConfig{
+				Providers: []llamav1alpha1.ProviderInfo{
+					{API: "inference", ProviderID: "ollama", Health: llamav1alpha1.ProviderHealthStatus{Status: "OK"}},
+					{API: "safety", ProviderID: "llama-guard", Health: llamav1alpha1.ProviderHealthStatus{Status: "Error"}},
+				},
+			},
+		},
+	}
+	t.Cleanup(func() { deleteDistributionMetrics(instance.Namespace, instance.Name) })
+
+	recordDistributionMetrics(instance, 3)
+
+	assert.InDelta(t, 1, testutil.ToFloat64(distributionPhase.WithLabelValues("metrics-ns", "metrics-test", "Ready")), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(distributionPhase.WithLabelValues("metrics-ns", "metrics-test", "Failed")), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(distributionReadyReplicas.WithLabelValues("metrics-ns", "metrics-test")), 0)
+	assert.InDelta(t, 3, testutil.ToFloat64(distributionDesiredReplicas.WithLabelValues("metrics-ns", "metrics-test")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(providerHealth.WithLabelValues("metrics-ns", "metrics-test", "inference", "ollama")), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(providerHealth.WithLabelValues("metrics-ns", "metrics-test", "safety", "llama-guard")), 0)
+
+	// DeletePartialMatch returns the number of series of the distribution
+	labels := prometheus.Labels{"namespace": "metrics-ns", "name": "metrics-test"}
+
+	// providers that are no longer reported are dropped
+	instance.Status.DistributionConfig.Providers = instance.Status.DistributionConfig.Providers[:1]
+	recordDistributionMetrics(instance, 3)
+	assert.Equal(t, 1, providerHealth.DeletePartialMatch(labels))
+
+	recordDistributionMetrics(instance, 3)
+	deleteDistributionMetrics(instance.Namespace, instance.Name)
+	assert.Zero(t, distributionPhase.DeletePartialMatch(labels))
+	assert.Zero(t, providerHealth.DeletePartialMatch(labels))
+}
+
+func TestObserveStatusPoll(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "poll-test", Namespace: "metrics-ns"},
+	}
+	t.Cleanup(func() { deleteDistributionMetrics(instance.Namespace, instance.Name) })
+
+	observeStatusPoll(instance, statusPollProviders, time.Now(), nil)
+	observeStatusPoll(instance, statusPollProviders, time.Now(), errors.New("connection refused"))
+
+	assert.InDelta(t, 1, testutil.ToFloat64(statusPollErrors.WithLabelValues("metrics-ns", "poll-test", statusPollProviders)), 0)
+	assert.GreaterOrEqual(t, testutil.CollectAndCount(statusPollDuration), 2, "successes and errors are separate series")
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EventReasonMonitoringUnavailable is emitted when monitoring is enabled but the Prometheus Operator CRDs are missing.
+const EventReasonMonitoringUnavailable = "MonitoringUnavailable"
+
+// monitoringKinds lists the Prometheus Operator kinds rendered for a distribution.
+var monitoringKinds = []string{deploy.ServiceMonitorKind, deploy.PrometheusRuleKind}
+
+// hasMonitoringCRDs returns true if the monitoring.coreos.com CRDs are discoverable. The lookup
+// goes through the client's REST mapper, which rediscovers the group once the CRDs get installed.
+func (r *LlamaStackDistributionReconciler) hasMonitoringCRDs() bool {
+	for _, kind := range monitoringKinds {
+		gk := schema.GroupKind{Group: deploy.MonitoringGroup, Kind: kind}
+		if _, err := r.RESTMapper().RESTMapping(gk, "v1"); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// warnIfMonitoringUnavailable emits an Event when monitoring is requested but cannot be rendered.
+func (r *LlamaStackDistributionReconciler) warnIfMonitoringUnavailable(instance *llamav1alpha1.LlamaStackDistribution) {
+	if instance.IsMonitoringEnabled() && !r.hasMonitoringCRDs() {
+		r.Recorder.Event(instance, corev1.EventTypeWarning, EventReasonMonitoringUnavailable,
+			"Monitoring is enabled but the monitoring.coreos.com CRDs are not installed, skipping ServiceMonitor and PrometheusRule")
+	}
+}
@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/cluster"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/featureflags"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/redact"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// operatorConfigEventBuffer bounds the distributions waiting to be enqueued after a reload.
+const operatorConfigEventBuffer = 128
+
+// Condition reasons of the LlamaStackOperatorConfig.
+const (
+	// ReasonOperatorConfigApplied indicates the running operator applied the latest spec.
+	ReasonOperatorConfigApplied = "ConfigApplied"
+	// ReasonOperatorConfigInvalid indicates the latest spec is invalid and the previous one is kept.
+	ReasonOperatorConfigInvalid = "InvalidConfig"
+)
+
+// OperatorSettings are the settings of the LlamaStackOperatorConfig. They are replaced as a whole
+// when the config changes, so a reconciliation never sees a partial update.
+type OperatorSettings struct {
+	// EnableNetworkPolicy creates a NetworkPolicy for every distribution
+	EnableNetworkPolicy bool
+	// ProviderConfigRedaction masks credentials in the provider configs written to the status
+	ProviderConfigRedaction ProviderConfigRedaction
+	// DefaultDistribution is used by distributions setting neither a name nor an image
+	DefaultDistribution string
+	// ImageMirrors rewrite the resolved server images
+	ImageMirrors []llamav1alpha1.ImageMirror
+	// DefaultResources are used by server containers without requests and limits
+	DefaultResources *corev1.ResourceRequirements
+	// HealthProbeInterval overrides the interval of the health prober when set
+	HealthProbeInterval time.Duration
+	// RegistrationResyncInterval overrides how often registrations are verified when set
+	RegistrationResyncInterval time.Duration
+	// PruneDryRun reports the resources that would be pruned instead of deleting them
+	PruneDryRun bool
+	// spec is the applied spec, compared to detect changes
+	spec llamav1alpha1.LlamaStackOperatorConfigSpec
+}
+
+// newOperatorSettings validates the spec of the operator config. The default distribution is only
+// checked against the supported distributions when the cluster info is known.
+func newOperatorSettings(spec llamav1alpha1.LlamaStackOperatorConfigSpec, clusterInfo *cluster.ClusterInfo) (*OperatorSettings, error) {
+	redaction := spec.FeatureFlags.ProviderConfigRedaction
+	redactor, err := redact.New(redaction.KeyPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure provider config redaction: %w", err)
+	}
+	if spec.DefaultDistribution != "" && clusterInfo != nil {
+		if _, exists := clusterInfo.DistributionImages[spec.DefaultDistribution]; !exists {
+			return nil, fmt.Errorf("failed to validate default distribution: %s is not a supported distribution", spec.DefaultDistribution)
+		}
+	}
+
+	settings := &OperatorSettings{
+		EnableNetworkPolicy: spec.FeatureFlags.EnableNetworkPolicy,
+		ProviderConfigRedaction: ProviderConfigRedaction{
+			Redactor:   redactor,
+			DropConfig: redaction.DropConfig,
+		},
+		DefaultDistribution: spec.DefaultDistribution,
+		ImageMirrors:        spec.ImageMirrors,
+		DefaultResources:    spec.DefaultResources,
+		PruneDryRun:         spec.Pruning.DryRun,
+		spec:                *spec.DeepCopy(),
+	}
+	if interval := spec.Polling.HealthProbeInterval; interval != nil {
+		settings.HealthProbeInterval = interval.Duration
+	}
+	if interval := spec.Polling.RegistrationResyncInterval; interval != nil {
+		settings.RegistrationResyncInterval = interval.Duration
+	}
+	return settings, nil
+}
+
+// Settings returns the current operator settings.
+func (r *LlamaStackDistributionReconciler) Settings() OperatorSettings {
+	if settings := r.settings.Load(); settings != nil {
+		return *settings
+	}
+	return OperatorSettings{}
+}
+
+// SetSettings replaces the operator settings used by the next reconciliations, and applies the
+// polling intervals to the health prober and the controllers of the registered resources.
+func (r *LlamaStackDistributionReconciler) SetSettings(settings OperatorSettings) {
+	r.settings.Store(&settings)
+	registrationResync.Store(int64(settings.RegistrationResyncInterval))
+	if r.healthProber != nil {
+		r.healthProber.SetInterval(r.healthProbeInterval())
+	}
+}
+
+// healthProbeInterval returns the interval of the health prober, the operator config overriding
+// the command line. Zero means the prober is disabled, which the operator config cannot change.
+func (r *LlamaStackDistributionReconciler) healthProbeInterval() time.Duration {
+	if r.healthProberOptions.Interval <= 0 {
+		return 0
+	}
+	if interval := r.Settings().HealthProbeInterval; interval > 0 {
+		return interval
+	}
+	return r.healthProberOptions.Interval
+}
+
+// operatorConfigMapKey returns the key of the legacy feature flags ConfigMap in the operator namespace.
+func operatorConfigMapKey(operatorNamespace string) types.NamespacedName {
+	return types.NamespacedName{Name: operatorConfigData, Namespace: operatorNamespace}
+}
+
+// loadOperatorConfig returns the LlamaStackOperatorConfig, creating it on the first start. The spec
+// is migrated from the feature flags of the legacy ConfigMap when it exists, which is then ignored.
+func loadOperatorConfig(ctx context.Context, c client.Client, configMapKey types.NamespacedName) (*llamav1alpha1.LlamaStackOperatorConfig, error) {
+	logger := log.FromContext(ctx)
+
+	config := &llamav1alpha1.LlamaStackOperatorConfig{}
+	err := c.Get(ctx, client.ObjectKey{Name: llamav1alpha1.OperatorConfigName}, config)
+	if err == nil {
+		return config, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get LlamaStackOperatorConfig: %w", err)
+	}
+
+	config = &llamav1alpha1.LlamaStackOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: llamav1alpha1.OperatorConfigName},
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, configMapKey, configMap); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get ConfigMap: %w", err)
+		}
+		config.Spec = operatorConfigSpecFromFlags(defaultFeatureFlags())
+	} else {
+		flags, err := parseFeatureFlags(configMap.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate ConfigMap %s: %w", configMapKey, err)
+		}
+		config.Spec = operatorConfigSpecFromFlags(flags)
+		config.Annotations = map[string]string{
+			llamav1alpha1.MigratedFromAnnotation: "ConfigMap/" + configMapKey.String(),
+		}
+	}
+
+	if err := c.Create(ctx, config); err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create LlamaStackOperatorConfig: %w", err)
+		}
+		// Another replica created it first.
+		if err := c.Get(ctx, client.ObjectKeyFromObject(config), config); err != nil {
+			return nil, fmt.Errorf("failed to get LlamaStackOperatorConfig: %w", err)
+		}
+		return config, nil
+	}
+	logger.Info("Created LlamaStackOperatorConfig", "name", config.Name,
+		"migratedFrom", config.Annotations[llamav1alpha1.MigratedFromAnnotation])
+	return config, nil
+}
+
+// operatorConfigSpecFromFlags converts the feature flags of the legacy ConfigMap.
+func operatorConfigSpecFromFlags(flags featureflags.FeatureFlags) llamav1alpha1.LlamaStackOperatorConfigSpec {
+	return llamav1alpha1.LlamaStackOperatorConfigSpec{
+		FeatureFlags: llamav1alpha1.OperatorFeatureFlags{
+			EnableNetworkPolicy: flags.EnableNetworkPolicy.Enabled,
+			ProviderConfigRedaction: llamav1alpha1.ProviderConfigRedactionSpec{
+				KeyPatterns: flags.ProviderConfigRedaction.KeyPatterns,
+				DropConfig:  flags.ProviderConfigRedaction.DropConfig,
+			},
+		},
+	}
+}
+
+// reconcileOperatorConfig applies the LlamaStackOperatorConfig when its spec changes and reconciles
+// every distribution so that, e.g., NetworkPolicies are created or deleted. An invalid spec is
+// reported in the Applied condition and with an Event, and the previous settings are kept.
+func (r *LlamaStackDistributionReconciler) reconcileOperatorConfig(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	config := &llamav1alpha1.LlamaStackOperatorConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			logger.Info("LlamaStackOperatorConfig deleted, keeping the current settings")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to fetch LlamaStackOperatorConfig: %w", err)
+	}
+
+	settings, err := newOperatorSettings(config.Spec, r.ClusterInfo)
+	if err != nil {
+		// Retrying cannot fix the spec, the next edit triggers a new reconciliation.
+		logger.Error(err, "invalid LlamaStackOperatorConfig, keeping the current settings")
+		r.Recorder.Eventf(config, corev1.EventTypeWarning, EventReasonOperatorConfigInvalid,
+			"Keeping the current settings: %v", err)
+		setOperatorConfigApplied(config, false, ReasonOperatorConfigInvalid,
+			fmt.Sprintf("Keeping the settings of generation %d: %v", config.Status.AppliedGeneration, err))
+		return ctrl.Result{}, r.updateOperatorConfigStatus(ctx, config)
+	}
+
+	changed := !reflect.DeepEqual(r.Settings().spec, settings.spec)
+	if changed {
+		r.SetSettings(*settings)
+		logger.Info("Applied LlamaStackOperatorConfig", "generation", config.Generation)
+		r.Recorder.Eventf(config, corev1.EventTypeNormal, EventReasonOperatorConfigReloaded,
+			"Applied generation %d", config.Generation)
+	}
+
+	message := "The operator applied the latest spec"
+	if settings.HealthProbeInterval > 0 && r.healthProberOptions.Interval <= 0 {
+		message += "; polling.healthProbeInterval is ignored since --distribution-health-interval disabled the health prober"
+	}
+	config.Status.Applied = settings.spec.DeepCopy()
+	config.Status.AppliedGeneration = config.Generation
+	setOperatorConfigApplied(config, true, ReasonOperatorConfigApplied, message)
+	if err := r.updateOperatorConfigStatus(ctx, config); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.enqueueAllDistributions(ctx)
+}
+
+// setOperatorConfigApplied sets the Applied condition of the operator config.
+func setOperatorConfigApplied(config *llamav1alpha1.LlamaStackOperatorConfig, applied bool, reason, message string) {
+	status := metav1.ConditionTrue
+	if !applied {
+		status = metav1.ConditionFalse
+	}
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:               llamav1alpha1.ConditionTypeApplied,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: config.Generation,
+	})
+}
+
+// updateOperatorConfigStatus writes the status of the operator config.
+func (r *LlamaStackDistributionReconciler) updateOperatorConfigStatus(ctx context.Context, config *llamav1alpha1.LlamaStackOperatorConfig) error {
+	config.Status.ObservedGeneration = config.Generation
+	if err := r.Status().Update(ctx, config); err != nil {
+		return fmt.Errorf("failed to update LlamaStackOperatorConfig status: %w", err)
+	}
+	return nil
+}
+
+// enqueueAllDistributions requests a reconciliation of every distribution.
+func (r *LlamaStackDistributionReconciler) enqueueAllDistributions(ctx context.Context) error {
+	list := &llamav1alpha1.LlamaStackDistributionList{}
+	if err := r.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list distributions: %w", err)
+	}
+	for i := range list.Items {
+		select {
+		case r.operatorConfigEvents <- event.GenericEvent{Object: &list.Items[i]}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// setupOperatorConfigWatch registers the controller applying the LlamaStackOperatorConfig. It
+// returns the channel on which the distributions to reconcile after a change are published.
+func (r *LlamaStackDistributionReconciler) setupOperatorConfigWatch(mgr ctrl.Manager) (<-chan event.GenericEvent, error) {
+	r.operatorConfigEvents = make(chan event.GenericEvent, operatorConfigEventBuffer)
+	isOperatorConfig := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == llamav1alpha1.OperatorConfigName
+	})
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("operator-config").
+		For(&llamav1alpha1.LlamaStackOperatorConfig{}, builder.WithPredicates(isOperatorConfig, predicate.GenerationChangedPredicate{})).
+		Complete(reconcile.Func(r.reconcileOperatorConfig)); err != nil {
+		return nil, fmt.Errorf("failed to create operator config controller: %w", err)
+	}
+	return r.operatorConfigEvents, nil
+}
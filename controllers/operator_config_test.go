@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/featureflags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func newOperatorConfigScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, llamav1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+func TestLoadOperatorConfig(t *testing.T) {
+	configMapKey := operatorConfigMapKey("operator-ns")
+	configKey := client.ObjectKey{Name: llamav1alpha1.OperatorConfigName}
+
+	t.Run("migrates the legacy ConfigMap", func(t *testing.T) {
+		k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+			WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configMapKey.Name, Namespace: configMapKey.Namespace},
+				Data: map[string]string{featureflags.FeatureFlagsKey: `
+enableNetworkPolicy:
+  enabled: true
+providerConfigRedaction:
+  keyPatterns: ["api_key"]
+  dropConfig: true
+`},
+			}).
+			Build()
+
+		config, err := loadOperatorConfig(t.Context(), k8sClient, configMapKey)
+		require.NoError(t, err)
+
+		stored := &llamav1alpha1.LlamaStackOperatorConfig{}
+		require.NoError(t, k8sClient.Get(t.Context(), configKey, stored))
+		assert.Equal(t, config.Spec, stored.Spec)
+		assert.True(t, stored.Spec.FeatureFlags.EnableNetworkPolicy)
+		assert.Equal(t, []string{"api_key"}, stored.Spec.FeatureFlags.ProviderConfigRedaction.KeyPatterns)
+		assert.True(t, stored.Spec.FeatureFlags.ProviderConfigRedaction.DropConfig)
+		assert.Equal(t, "ConfigMap/operator-ns/llama-stack-operator-config", stored.Annotations[llamav1alpha1.MigratedFromAnnotation])
+	})
+
+	t.Run("creates the default config without a ConfigMap", func(t *testing.T) {
+		k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).Build()
+
+		_, err := loadOperatorConfig(t.Context(), k8sClient, configMapKey)
+		require.NoError(t, err)
+
+		stored := &llamav1alpha1.LlamaStackOperatorConfig{}
+		require.NoError(t, k8sClient.Get(t.Context(), configKey, stored))
+		assert.Equal(t, featureflags.NetworkPolicyDefaultValue, stored.Spec.FeatureFlags.EnableNetworkPolicy)
+		assert.Empty(t, stored.Annotations)
+	})
+
+	t.Run("ignores the ConfigMap once the config exists", func(t *testing.T) {
+		k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+			WithObjects(
+				&llamav1alpha1.LlamaStackOperatorConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: llamav1alpha1.OperatorConfigName},
+					Spec:       llamav1alpha1.LlamaStackOperatorConfigSpec{DefaultDistribution: "ollama"},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: configMapKey.Name, Namespace: configMapKey.Namespace},
+					Data:       map[string]string{featureflags.FeatureFlagsKey: "enableNetworkPolicy:\n  enabled: true\n"},
+				},
+			).
+			Build()
+
+		config, err := loadOperatorConfig(t.Context(), k8sClient, configMapKey)
+		require.NoError(t, err)
+		assert.Equal(t, "ollama", config.Spec.DefaultDistribution)
+		assert.False(t, config.Spec.FeatureFlags.EnableNetworkPolicy)
+	})
+}
+
+func TestReconcileOperatorConfig(t *testing.T) {
+	scheme := newOperatorConfigScheme(t)
+	config := &llamav1alpha1.LlamaStackOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: llamav1alpha1.OperatorConfigName, Generation: 1},
+	}
+	k8sClient := clientfake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(
+			config,
+			&llamav1alpha1.LlamaStackDistribution{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "ns-a"}},
+			&llamav1alpha1.LlamaStackDistribution{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "ns-b"}},
+		).
+		WithStatusSubresource(config).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	initial, err := newOperatorSettings(config.Spec, nil)
+	require.NoError(t, err)
+	r := &LlamaStackDistributionReconciler{
+		Client:               k8sClient,
+		Scheme:               scheme,
+		ClusterInfo:          setupTestClusterInfo(nil),
+		Recorder:             recorder,
+		operatorConfigEvents: make(chan event.GenericEvent, operatorConfigEventBuffer),
+	}
+	r.SetSettings(*initial)
+	t.Cleanup(func() { registrationResync.Store(0) })
+
+	ctx := context.Background() //nolint:usetesting // the reconciler is shared by the subtests
+	key := client.ObjectKey{Name: llamav1alpha1.OperatorConfigName}
+	reconcile := func(t *testing.T, update func(spec *llamav1alpha1.LlamaStackOperatorConfigSpec)) *llamav1alpha1.LlamaStackOperatorConfig {
+		t.Helper()
+		current := &llamav1alpha1.LlamaStackOperatorConfig{}
+		require.NoError(t, k8sClient.Get(ctx, key, current))
+		if update != nil {
+			update(&current.Spec)
+			current.Generation++
+			require.NoError(t, k8sClient.Update(ctx, current))
+		}
+		_, err := r.reconcileOperatorConfig(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		require.NoError(t, k8sClient.Get(ctx, key, current))
+		return current
+	}
+
+	t.Run("reports the unchanged settings as applied", func(t *testing.T) {
+		current := reconcile(t, nil)
+
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeApplied))
+		assert.Equal(t, current.Generation, current.Status.AppliedGeneration)
+		assert.Empty(t, recorder.Events)
+		assert.Empty(t, r.operatorConfigEvents)
+	})
+
+	t.Run("applies a changed spec and reconciles every distribution", func(t *testing.T) {
+		current := reconcile(t, func(spec *llamav1alpha1.LlamaStackOperatorConfigSpec) {
+			spec.FeatureFlags.EnableNetworkPolicy = true
+			spec.DefaultDistribution = "ollama"
+			spec.Polling.RegistrationResyncInterval = &metav1.Duration{Duration: 5 * time.Minute}
+		})
+
+		settings := r.Settings()
+		assert.True(t, settings.EnableNetworkPolicy)
+		assert.Equal(t, "ollama", settings.DefaultDistribution)
+		assert.Equal(t, 5*time.Minute, registrationResyncInterval())
+
+		require.NotNil(t, current.Status.Applied)
+		assert.Equal(t, "ollama", current.Status.Applied.DefaultDistribution)
+		assert.Equal(t, current.Generation, current.Status.AppliedGeneration)
+		assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, llamav1alpha1.ConditionTypeApplied))
+
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal OperatorConfigReloaded Applied generation 2", <-recorder.Events)
+		require.Len(t, r.operatorConfigEvents, 2)
+		enqueued := []string{(<-r.operatorConfigEvents).Object.GetName(), (<-r.operatorConfigEvents).Object.GetName()}
+		assert.ElementsMatch(t, []string{"first", "second"}, enqueued)
+	})
+
+	t.Run("keeps the applied settings when the spec is invalid", func(t *testing.T) {
+		current := reconcile(t, func(spec *llamav1alpha1.LlamaStackOperatorConfigSpec) {
+			spec.DefaultDistribution = "unknown"
+		})
+
+		assert.Equal(t, "ollama", r.Settings().DefaultDistribution)
+		assert.Equal(t, "ollama", current.Status.Applied.DefaultDistribution)
+		assert.Equal(t, int64(2), current.Status.AppliedGeneration)
+		assert.Equal(t, int64(3), current.Status.ObservedGeneration)
+		applied := meta.FindStatusCondition(current.Status.Conditions, llamav1alpha1.ConditionTypeApplied)
+		require.NotNil(t, applied)
+		assert.Equal(t, metav1.ConditionFalse, applied.Status)
+		assert.Equal(t, ReasonOperatorConfigInvalid, applied.Reason)
+		assert.Contains(t, applied.Message, "Keeping the settings of generation 2")
+
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning OperatorConfigInvalid Keeping the current settings: failed to validate default distribution")
+		assert.Empty(t, r.operatorConfigEvents)
+	})
+
+	t.Run("keeps the applied settings when the config is deleted", func(t *testing.T) {
+		require.NoError(t, k8sClient.Delete(ctx, &llamav1alpha1.LlamaStackOperatorConfig{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}))
+		_, err := r.reconcileOperatorConfig(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+
+		assert.True(t, r.Settings().EnableNetworkPolicy)
+		assert.Empty(t, recorder.Events)
+	})
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/redact"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// emptyProviderConfig replaces provider configs that are not exposed, since the field is required.
+var emptyProviderConfig = []byte("{}")
+
+// ProviderConfigRedaction controls how the provider configs reported by the server are written to the status.
+type ProviderConfigRedaction struct {
+	// Redactor masks sensitive values, the default key patterns are used when nil.
+	Redactor *redact.Redactor
+	// DropConfig removes the provider configs entirely.
+	DropConfig bool
+}
+
+// redactor returns the Redactor of the operator config, which masks every value the operator writes
+// to a status, or the default one.
+func (r *LlamaStackDistributionReconciler) redactor() *redact.Redactor {
+	if redactor := r.Settings().ProviderConfigRedaction.Redactor; redactor != nil {
+		return redactor
+	}
+	return redact.Default()
+}
+
+// redactProviders returns a copy of the providers with their config redacted. A config that cannot
+// be parsed is dropped rather than written unredacted.
+func (r *LlamaStackDistributionReconciler) redactProviders(ctx context.Context, providers []llamav1alpha1.ProviderInfo) []llamav1alpha1.ProviderInfo {
+	if providers == nil {
+		return nil
+	}
+
+	redaction := r.Settings().ProviderConfigRedaction
+	redactor := r.redactor()
+
+	redacted := make([]llamav1alpha1.ProviderInfo, len(providers))
+	for i, provider := range providers {
+		redacted[i] = provider
+		if redaction.DropConfig {
+			redacted[i].Config = apiextensionsv1.JSON{Raw: emptyProviderConfig}
+			continue
+		}
+
+		raw, err := redactor.JSON(provider.Config.Raw)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "failed to redact provider config, dropping it", "provider", providerKey(provider))
+			raw = emptyProviderConfig
+		}
+		redacted[i].Config = apiextensionsv1.JSON{Raw: raw}
+	}
+	return redacted
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/featureflags"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestRedactProviders(t *testing.T) {
+	ctx := context.Background() //nolint:usetesting // logging only
+	providers := []llamav1alpha1.ProviderInfo{
+		{
+			ProviderID: "openai",
+			Config:     apiextensionsv1.JSON{Raw: []byte(`{"api_key":"sk-123","url":"https://api.openai.com/v1"}`)},
+		},
+		{
+			ProviderID: "broken",
+			Config:     apiextensionsv1.JSON{Raw: []byte(`{"password":`)},
+		},
+	}
+
+	t.Run("redacts with the default patterns", func(t *testing.T) {
+		r := &LlamaStackDistributionReconciler{}
+		redacted := r.redactProviders(ctx, providers)
+
+		require.Len(t, redacted, 2)
+		assert.JSONEq(t, `{"api_key":"<redacted>","url":"https://api.openai.com/v1"}`, string(redacted[0].Config.Raw))
+		assert.JSONEq(t, `{}`, string(redacted[1].Config.Raw), "unparsable configs are dropped")
+		assert.Contains(t, string(providers[0].Config.Raw), "sk-123", "the input is not modified")
+	})
+
+	t.Run("uses the configured patterns", func(t *testing.T) {
+		redactor, err := redact.New([]string{"url"})
+		require.NoError(t, err)
+		r := &LlamaStackDistributionReconciler{}
+		r.SetSettings(OperatorSettings{ProviderConfigRedaction: ProviderConfigRedaction{Redactor: redactor}})
+
+		redacted := r.redactProviders(ctx, providers[:1])
+		assert.JSONEq(t, `{"api_key":"sk-123","url":"<redacted>"}`, string(redacted[0].Config.Raw))
+	})
+
+	t.Run("drops configs when requested", func(t *testing.T) {
+		r := &LlamaStackDistributionReconciler{}
+		r.SetSettings(OperatorSettings{ProviderConfigRedaction: ProviderConfigRedaction{DropConfig: true}})
+
+		redacted := r.redactProviders(ctx, providers)
+		for _, provider := range redacted {
+			assert.JSONEq(t, `{}`, string(provider.Config.Raw))
+		}
+	})
+}
+
+func TestParseFeatureFlags(t *testing.T) {
+	flags, err := parseFeatureFlags(nil)
+	require.NoError(t, err)
+	assert.Equal(t, featureflags.NetworkPolicyDefaultValue, flags.EnableNetworkPolicy.Enabled)
+	assert.Empty(t, flags.ProviderConfigRedaction.KeyPatterns)
+
+	flags, err = parseFeatureFlags(map[string]string{featureflags.FeatureFlagsKey: `
+enableNetworkPolicy:
+  enabled: true
+providerConfigRedaction:
+  keyPatterns: ["api_key", "^url$"]
+  dropConfig: true
+`})
+	require.NoError(t, err)
+	assert.True(t, flags.EnableNetworkPolicy.Enabled)
+	assert.Equal(t, []string{"api_key", "^url$"}, flags.ProviderConfigRedaction.KeyPatterns)
+	assert.True(t, flags.ProviderConfigRedaction.DropConfig)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// providerKey identifies a provider across polls.
+func providerKey(provider llamav1alpha1.ProviderInfo) string {
+	return provider.API + "/" + provider.ProviderID
+}
+
+// trackProviderTransitions sets the lastTransitionTime of each current provider, keeping the time
+// of the previous poll when the health status did not change.
+func trackProviderTransitions(previous, current []llamav1alpha1.ProviderInfo, now metav1.Time) {
+	known := make(map[string]llamav1alpha1.ProviderHealthStatus, len(previous))
+	for _, provider := range previous {
+		known[providerKey(provider)] = provider.Health
+	}
+
+	for i := range current {
+		health := &current[i].Health
+		if old, ok := known[providerKey(current[i])]; ok && old.Status == health.Status && old.LastTransitionTime != nil {
+			health.LastTransitionTime = old.LastTransitionTime
+			continue
+		}
+		transition := now
+		health.LastTransitionTime = &transition
+	}
+}
+
+// degradedProviders returns the providers reporting an error, sorted by API and ID.
+func degradedProviders(providers []llamav1alpha1.ProviderInfo) []llamav1alpha1.ProviderInfo {
+	var degraded []llamav1alpha1.ProviderInfo
+	for _, provider := range providers {
+		if provider.Health.Status == providerHealthError {
+			degraded = append(degraded, provider)
+		}
+	}
+	sort.Slice(degraded, func(i, j int) bool {
+		return providerKey(degraded[i]) < providerKey(degraded[j])
+	})
+	return degraded
+}
+
+// SetProvidersHealthyCondition aggregates the provider health into the ProvidersHealthy condition.
+// The transition time is only updated when the condition status changes, since polls refresh it often.
+func SetProvidersHealthyCondition(status *llamav1alpha1.LlamaStackDistributionStatus, providers []llamav1alpha1.ProviderInfo) {
+	condition := metav1.Condition{
+		Type:    ConditionTypeProvidersHealthy,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonProvidersHealthy,
+		Message: fmt.Sprintf("All %d providers are healthy", len(providers)),
+	}
+
+	if degraded := degradedProviders(providers); len(degraded) > 0 {
+		entries := make([]string, 0, len(degraded))
+		for _, provider := range degraded {
+			entry := providerKey(provider)
+			if provider.Health.Message != "" {
+				entry = fmt.Sprintf("%s (%s)", entry, provider.Health.Message)
+			}
+			entries = append(entries, entry)
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonProvidersDegraded
+		condition.Message = fmt.Sprintf("%d of %d providers are degraded: %s", len(degraded), len(providers), strings.Join(entries, ", "))
+	}
+
+	setConditionKeepingTransitionTime(status, condition)
+}
+
+// SetProvidersUnknownCondition reports that the provider health is unknown.
+func SetProvidersUnknownCondition(status *llamav1alpha1.LlamaStackDistributionStatus, message string) {
+	setConditionKeepingTransitionTime(status, metav1.Condition{
+		Type:    ConditionTypeProvidersHealthy,
+		Status:  metav1.ConditionUnknown,
+		Reason:  ReasonProvidersUnknown,
+		Message: message,
+	})
+}
+
+// setConditionKeepingTransitionTime sets the condition, keeping the existing transition time if its status did not change.
+func setConditionKeepingTransitionTime(status *llamav1alpha1.LlamaStackDistributionStatus, condition metav1.Condition) {
+	condition.LastTransitionTime = metav1.NewTime(metav1.Now().UTC())
+	if existing := GetCondition(status, condition.Type); existing != nil && existing.Status == condition.Status {
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	SetCondition(status, condition)
+}
+
+// recordProviderHealthChanges emits an Event for every provider that changed between OK and Error
+// since the previous status. Providers that appear or disappear are not reported.
+func (r *LlamaStackDistributionReconciler) recordProviderHealthChanges(
+	instance *llamav1alpha1.LlamaStackDistribution,
+	previous []llamav1alpha1.ProviderInfo,
+) {
+	known := make(map[string]string, len(previous))
+	for _, provider := range previous {
+		known[providerKey(provider)] = provider.Health.Status
+	}
+
+	for _, provider := range instance.Status.DistributionConfig.Providers {
+		old, ok := known[providerKey(provider)]
+		current := provider.Health.Status
+		if !ok || old == current {
+			continue
+		}
+
+		switch {
+		case old == providerHealthOK && current == providerHealthError:
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, EventReasonProviderHealthChanged,
+				"Provider %s changed from %s to %s: %s", providerKey(provider), old, current, provider.Health.Message)
+		case old == providerHealthError && current == providerHealthOK:
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, EventReasonProviderHealthChanged,
+				"Provider %s changed from %s to %s", providerKey(provider), old, current)
+		}
+	}
+}
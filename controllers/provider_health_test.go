@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func newTestProvider(api, id, status, message string) llamav1alpha1.ProviderInfo {
+	return llamav1alpha1.ProviderInfo{
+		API:        api,
+		ProviderID: id,
+		Health:     llamav1alpha1.ProviderHealthStatus{Status: status, Message: message},
+	}
+}
+
+func TestTrackProviderTransitions(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(start.Add(time.Minute))
+
+	previous := []llamav1alpha1.ProviderInfo{
+		newTestProvider("inference", "ollama", providerHealthOK, ""),
+		newTestProvider("safety", "llama-guard", providerHealthOK, ""),
+	}
+	trackProviderTransitions(nil, previous, start)
+	require.Equal(t, start, *previous[0].Health.LastTransitionTime, "new providers start their transition time")
+
+	current := []llamav1alpha1.ProviderInfo{
+		newTestProvider("inference", "ollama", providerHealthOK, ""),
+		newTestProvider("safety", "llama-guard", providerHealthError, "connection refused"),
+	}
+	trackProviderTransitions(previous, current, later)
+
+	assert.Equal(t, start, *current[0].Health.LastTransitionTime, "an unchanged status keeps its transition time")
+	assert.Equal(t, later, *current[1].Health.LastTransitionTime, "a changed status gets a new transition time")
+}
+
+func TestSetProvidersHealthyCondition(t *testing.T) {
+	t.Run("healthy providers", func(t *testing.T) {
+		status := &llamav1alpha1.LlamaStackDistributionStatus{}
+		SetProvidersHealthyCondition(status, []llamav1alpha1.ProviderInfo{
+			newTestProvider("inference", "ollama", providerHealthOK, ""),
+			newTestProvider("telemetry", "meta-reference", "Not Implemented", ""),
+		})
+
+		condition := GetCondition(status, ConditionTypeProvidersHealthy)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "All 2 providers are healthy", condition.Message)
+	})
+
+	t.Run("degraded providers are listed", func(t *testing.T) {
+		status := &llamav1alpha1.LlamaStackDistributionStatus{}
+		SetProvidersHealthyCondition(status, []llamav1alpha1.ProviderInfo{
+			newTestProvider("vector_io", "faiss", providerHealthError, ""),
+			newTestProvider("inference", "ollama", providerHealthError, "connection refused"),
+			newTestProvider("safety", "llama-guard", providerHealthOK, ""),
+		})
+
+		condition := GetCondition(status, ConditionTypeProvidersHealthy)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, ReasonProvidersDegraded, condition.Reason)
+		assert.Equal(t, "2 of 3 providers are degraded: inference/ollama (connection refused), vector_io/faiss", condition.Message)
+	})
+
+	t.Run("keeps the transition time while the status is unchanged", func(t *testing.T) {
+		status := &llamav1alpha1.LlamaStackDistributionStatus{}
+		SetProvidersUnknownCondition(status, "Deployment not ready")
+		transition := metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		status.Conditions[0].LastTransitionTime = transition
+
+		SetProvidersUnknownCondition(status, "Failed to query providers")
+		assert.Equal(t, transition, GetCondition(status, ConditionTypeProvidersHealthy).LastTransitionTime)
+		assert.Equal(t, "Failed to query providers", GetCondition(status, ConditionTypeProvidersHealthy).Message)
+	})
+}
+
+func TestRecordProviderHealthChanges(t *testing.T) {
+	recorder := record.NewFakeRecorder(3)
+	r := &LlamaStackDistributionReconciler{Recorder: recorder}
+	instance := &llamav1alpha1.LlamaStackDistribution{}
+	instance.Status.DistributionConfig.Providers = []llamav1alpha1.ProviderInfo{
+		newTestProvider("inference", "ollama", providerHealthError, "connection refused"),
+		newTestProvider("safety", "llama-guard", providerHealthOK, ""),
+		newTestProvider("vector_io", "faiss", providerHealthOK, ""),
+		newTestProvider("tool_runtime", "mcp", providerHealthError, "timeout"),
+	}
+
+	r.recordProviderHealthChanges(instance, []llamav1alpha1.ProviderInfo{
+		newTestProvider("inference", "ollama", providerHealthOK, ""),
+		newTestProvider("safety", "llama-guard", providerHealthError, "unreachable"),
+		newTestProvider("vector_io", "faiss", providerHealthOK, ""),
+	})
+
+	require.Len(t, recorder.Events, 2, "unchanged and new providers are not reported")
+	assert.Equal(t, "Warning ProviderHealthChanged Provider inference/ollama changed from OK to Error: connection refused", <-recorder.Events)
+	assert.Equal(t, "Normal ProviderHealthChanged Provider safety/llama-guard changed from Error to OK", <-recorder.Events)
+}
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+const (
+	// managedByLabel and managedByValue are set on every resource rendered from the manifests.
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "llama-stack-operator"
+)
+
+// excludedGVKs returns the group, version and kind of the rendered resources whose kind is
+// excluded, in the order of the manifests. The kinds are resolved from the manifests, so new
+// optional kinds are pruned without changes here.
+func excludedGVKs(resMap *resmap.ResMap, kindsToExclude []string) []schema.GroupVersionKind {
+	var gvks []schema.GroupVersionKind
+	for _, res := range (*resMap).Resources() {
+		if !slices.Contains(kindsToExclude, res.GetKind()) {
+			continue
+		}
+		kGvk := res.GetGvk()
+		gvk := schema.GroupVersionKind{Group: kGvk.Group, Version: kGvk.Version, Kind: kGvk.Kind}
+		if !slices.Contains(gvks, gvk) {
+			gvks = append(gvks, gvk)
+		}
+	}
+	return gvks
+}
+
+// pruneExcludedResources deletes the resources of the excluded kinds that the instance controls,
+// e.g. the Service once the distribution has no port. In dry-run mode, an Event reports each
+// resource that would be deleted instead.
+func (r *LlamaStackDistributionReconciler) pruneExcludedResources(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	resMap *resmap.ResMap,
+	kindsToExclude []string,
+) error {
+	dryRun := r.Settings().PruneDryRun
+	for _, gvk := range excludedGVKs(resMap, kindsToExclude) {
+		objects, err := r.listControlledResources(ctx, instance, gvk)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objects {
+			if err := r.pruneResource(ctx, instance, gvk.Kind, obj, dryRun); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// listControlledResources lists the resources of a kind rendered by the operator and controlled by
+// the instance. Kinds whose CRD is not installed have no resources.
+func (r *LlamaStackDistributionReconciler) listControlledResources(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	gvk schema.GroupVersionKind,
+) ([]client.Object, error) {
+	// Typed lists are served by the cache, the other kinds are listed from the API server.
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	var list client.ObjectList
+	if typed, err := r.Scheme.New(listGVK); err == nil {
+		if objectList, ok := typed.(client.ObjectList); ok {
+			list = objectList
+		}
+	}
+	if list == nil {
+		unstructuredList := &unstructured.UnstructuredList{}
+		unstructuredList.SetGroupVersionKind(listGVK)
+		list = unstructuredList
+	}
+
+	if err := r.List(ctx, list, client.InNamespace(instance.Namespace), client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s list: %w", gvk.Kind, err)
+	}
+	var objects []client.Object
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok && metav1.IsControlledBy(obj, instance) {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// buildInventory returns the resources of the render, in the order of the manifests.
+func buildInventory(resMap *resmap.ResMap) []llamav1alpha1.InventoryEntry {
+	resources := (*resMap).Resources()
+	inventory := make([]llamav1alpha1.InventoryEntry, 0, len(resources))
+	for _, res := range resources {
+		inventory = append(inventory, llamav1alpha1.InventoryEntry{
+			APIVersion: res.GetApiVersion(),
+			Kind:       res.GetKind(),
+			Name:       res.GetName(),
+		})
+	}
+	return inventory
+}
+
+// sameInventoryResource compares the group, kind and name of two entries, so a resource moving to
+// another version of its API is not pruned.
+func sameInventoryResource(a, b llamav1alpha1.InventoryEntry) bool {
+	return a.Kind == b.Kind && a.Name == b.Name &&
+		schema.FromAPIVersionAndKind(a.APIVersion, a.Kind).Group == schema.FromAPIVersionAndKind(b.APIVersion, b.Kind).Group
+}
+
+// pruneInventory deletes the resources of the previous inventory that are missing from the rendered
+// ones, e.g. after a manifest was renamed or dropped by an operator upgrade, and returns the new
+// inventory. The excluded kinds are left to pruneExcludedResources. In dry-run mode, the resources
+// that would be deleted stay in the inventory so they are pruned once dry-run is disabled.
+func (r *LlamaStackDistributionReconciler) pruneInventory(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	rendered []llamav1alpha1.InventoryEntry,
+	kindsToExclude []string,
+) ([]llamav1alpha1.InventoryEntry, error) {
+	dryRun := r.Settings().PruneDryRun
+	inventory := rendered
+	for _, entry := range instance.Status.Inventory {
+		if slices.Contains(kindsToExclude, entry.Kind) || slices.ContainsFunc(rendered, func(e llamav1alpha1.InventoryEntry) bool {
+			return sameInventoryResource(e, entry)
+		}) {
+			continue
+		}
+
+		obj, err := r.getInventoryResource(ctx, instance.Namespace, entry)
+		if err != nil {
+			return nil, err
+		}
+		if obj == nil || !metav1.IsControlledBy(obj, instance) {
+			continue
+		}
+		if err := r.pruneResource(ctx, instance, entry.Kind, obj, dryRun); err != nil {
+			return nil, err
+		}
+		if dryRun {
+			inventory = append(inventory, entry)
+		}
+	}
+	return inventory, nil
+}
+
+// getInventoryResource fetches the resource of an inventory entry, or returns nil when it no longer
+// exists or its CRD is not installed.
+func (r *LlamaStackDistributionReconciler) getInventoryResource(
+	ctx context.Context,
+	namespace string,
+	entry llamav1alpha1.InventoryEntry,
+) (client.Object, error) {
+	gvk := schema.FromAPIVersionAndKind(entry.APIVersion, entry.Kind)
+	var obj client.Object
+	if typed, err := r.Scheme.New(gvk); err == nil {
+		if object, ok := typed.(client.Object); ok {
+			obj = object
+		}
+	}
+	if obj == nil {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		obj = u
+	}
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: entry.Name}, obj); err != nil {
+		if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", entry.Kind, entry.Name, err)
+	}
+	return obj, nil
+}
+
+// pruneResource deletes a resource the instance no longer renders, unless it is annotated to be kept.
+func (r *LlamaStackDistributionReconciler) pruneResource(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	kind string,
+	obj client.Object,
+	dryRun bool,
+) error {
+	logger := log.FromContext(ctx).WithValues("kind", kind, "resource", obj.GetName())
+	if obj.GetAnnotations()[llamav1alpha1.PruneAnnotation] == llamav1alpha1.PruneKeepValue {
+		logger.V(1).Info("Keeping resource that is no longer rendered", "annotation", llamav1alpha1.PruneAnnotation)
+		return nil
+	}
+	if !obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	if dryRun {
+		logger.Info("Would prune resource that is no longer rendered")
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, EventReasonPruneDryRun,
+			"Would delete %s %s, it is no longer rendered", kind, obj.GetName())
+		return nil
+	}
+
+	logger.Info("Pruning resource that is no longer rendered")
+	uid := obj.GetUID()
+	if err := r.Delete(ctx, obj, client.Preconditions{UID: &uid}); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete %s %s: %w", kind, obj.GetName(), err)
+	}
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, EventReasonResourcePruned,
+		"Deleted %s %s, it is no longer rendered", kind, obj.GetName())
+	return nil
+}
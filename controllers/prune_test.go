@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestExcludedGVKs(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns"}}
+	resMap, err := deploy.RenderManifest(filesys.MakeFsOnDisk(), manifestsBasePath, instance)
+	require.NoError(t, err)
+
+	gvks := excludedGVKs(resMap, []string{"Service", deploy.ServiceMonitorKind, "PersistentVolumeClaim"})
+
+	assert.Equal(t, []schema.GroupVersionKind{
+		{Version: "v1", Kind: "PersistentVolumeClaim"},
+		{Version: "v1", Kind: "Service"},
+		{Group: deploy.MonitoringGroup, Version: "v1", Kind: deploy.ServiceMonitorKind},
+	}, gvks)
+}
+
+func TestPruneExcludedResources(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns", UID: "llsd-uid"},
+	}
+	resMap, err := deploy.RenderManifest(filesys.MakeFsOnDisk(), manifestsBasePath, instance)
+	require.NoError(t, err)
+	kindsToExclude := []string{"PersistentVolumeClaim", "Service"}
+
+	managedLabels := map[string]string{managedByLabel: managedByValue}
+	controlledBy := []metav1.OwnerReference{{
+		APIVersion: llamav1alpha1.GroupVersion.String(),
+		Kind:       llamav1alpha1.LlamaStackDistributionKind,
+		Name:       "llsd",
+		UID:        "llsd-uid",
+		Controller: ptr.To(true),
+	}}
+	objects := func() []client.Object {
+		return []client.Object{
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+				Name: "llsd-pvc", Namespace: "ns", Labels: managedLabels, OwnerReferences: controlledBy,
+			}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+				Name: "llsd-service", Namespace: "ns", Labels: managedLabels, OwnerReferences: controlledBy,
+				Annotations: map[string]string{llamav1alpha1.PruneAnnotation: llamav1alpha1.PruneKeepValue},
+			}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+				Name: "other-service", Namespace: "ns", Labels: managedLabels,
+			}},
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+				Name: "unmanaged-pvc", Namespace: "ns", OwnerReferences: controlledBy,
+			}},
+		}
+	}
+	exists := func(t *testing.T, c client.Client, obj client.Object, name string) bool {
+		t.Helper()
+		err := c.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: name}, obj)
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+
+	t.Run("deletes the controlled resources of the excluded kinds", func(t *testing.T) {
+		k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).WithObjects(objects()...).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &LlamaStackDistributionReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+
+		require.NoError(t, r.pruneExcludedResources(t.Context(), instance, resMap, kindsToExclude))
+
+		assert.False(t, exists(t, k8sClient, &corev1.PersistentVolumeClaim{}, "llsd-pvc"))
+		assert.True(t, exists(t, k8sClient, &corev1.Service{}, "llsd-service"), "annotated resources are kept")
+		assert.True(t, exists(t, k8sClient, &corev1.Service{}, "other-service"), "resources of other owners are kept")
+		assert.True(t, exists(t, k8sClient, &corev1.PersistentVolumeClaim{}, "unmanaged-pvc"), "resources without the managed-by label are kept")
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal ResourcePruned Deleted PersistentVolumeClaim llsd-pvc, it is no longer rendered", <-recorder.Events)
+	})
+
+	t.Run("only reports the resources in dry-run mode", func(t *testing.T) {
+		k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).WithObjects(objects()...).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &LlamaStackDistributionReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+		r.SetSettings(OperatorSettings{PruneDryRun: true})
+
+		require.NoError(t, r.pruneExcludedResources(t.Context(), instance, resMap, kindsToExclude))
+
+		assert.True(t, exists(t, k8sClient, &corev1.PersistentVolumeClaim{}, "llsd-pvc"))
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal PruneDryRun Would delete PersistentVolumeClaim llsd-pvc, it is no longer rendered", <-recorder.Events)
+	})
+}
+
+func TestPruneInventory(t *testing.T) {
+	owner := []metav1.OwnerReference{{
+		APIVersion: llamav1alpha1.GroupVersion.String(),
+		Kind:       llamav1alpha1.LlamaStackDistributionKind,
+		Name:       "llsd",
+		UID:        "llsd-uid",
+		Controller: ptr.To(true),
+	}}
+	applied := []llamav1alpha1.InventoryEntry{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "llsd"},
+		{APIVersion: "v1", Kind: "Service", Name: "llsd-service"},
+	}
+	newInstance := func() *llamav1alpha1.LlamaStackDistribution {
+		return &llamav1alpha1.LlamaStackDistribution{
+			ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns", UID: "llsd-uid"},
+			Status: llamav1alpha1.LlamaStackDistributionStatus{
+				Inventory: []llamav1alpha1.InventoryEntry{
+					// moved to another API version, still rendered
+					{APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "llsd"},
+					{APIVersion: "v1", Kind: "Service", Name: "llsd-service"},
+					// renamed by an operator upgrade
+					{APIVersion: "v1", Kind: "ServiceAccount", Name: "llsd-old-sa"},
+					// controlled by another owner
+					{APIVersion: "v1", Kind: "ConfigMap", Name: "shared"},
+					// already deleted
+					{APIVersion: "v1", Kind: "Secret", Name: "gone"},
+					// excluded kinds are pruned by pruneExcludedResources
+					{APIVersion: "v1", Kind: "PersistentVolumeClaim", Name: "llsd-pvc"},
+				},
+			},
+		}
+	}
+	newClient := func(t *testing.T) client.Client {
+		t.Helper()
+		return clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+			WithObjects(
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns", OwnerReferences: owner}},
+				&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "llsd-old-sa", Namespace: "ns", OwnerReferences: owner}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "ns"}},
+				&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "llsd-pvc", Namespace: "ns", OwnerReferences: owner}},
+			).
+			Build()
+	}
+	kindsToExclude := []string{"PersistentVolumeClaim"}
+
+	t.Run("deletes the resources missing from the render", func(t *testing.T) {
+		k8sClient := newClient(t)
+		recorder := record.NewFakeRecorder(10)
+		r := &LlamaStackDistributionReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+
+		inventory, err := r.pruneInventory(t.Context(), newInstance(), applied, kindsToExclude)
+		require.NoError(t, err)
+
+		assert.Equal(t, applied, inventory)
+		err = k8sClient.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: "llsd-old-sa"}, &corev1.ServiceAccount{})
+		assert.True(t, k8serrors.IsNotFound(err))
+		require.NoError(t, k8sClient.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: "llsd"}, &appsv1.Deployment{}))
+		require.NoError(t, k8sClient.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: "shared"}, &corev1.ConfigMap{}))
+		require.NoError(t, k8sClient.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: "llsd-pvc"}, &corev1.PersistentVolumeClaim{}))
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal ResourcePruned Deleted ServiceAccount llsd-old-sa, it is no longer rendered", <-recorder.Events)
+	})
+
+	t.Run("keeps the resources in the inventory in dry-run mode", func(t *testing.T) {
+		k8sClient := newClient(t)
+		recorder := record.NewFakeRecorder(10)
+		r := &LlamaStackDistributionReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+		r.SetSettings(OperatorSettings{PruneDryRun: true})
+
+		inventory, err := r.pruneInventory(t.Context(), newInstance(), applied, kindsToExclude)
+		require.NoError(t, err)
+
+		assert.Equal(t, append(applied, llamav1alpha1.InventoryEntry{APIVersion: "v1", Kind: "ServiceAccount", Name: "llsd-old-sa"}), inventory)
+		require.NoError(t, k8sClient.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: "llsd-old-sa"}, &corev1.ServiceAccount{}))
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal PruneDryRun Would delete ServiceAccount llsd-old-sa, it is no longer rendered", <-recorder.Events)
+	})
+}
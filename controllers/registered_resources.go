@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+)
+
+// maxRegisteredIdentifierLength truncates identifiers so a summary stays small.
+const maxRegisteredIdentifierLength = 128
+
+// registeredQuery is the outcome of listing the resources of one kind registered in the server.
+type registeredQuery struct {
+	Identifiers []string
+	Err         error
+}
+
+func (q registeredQuery) equal(other registeredQuery) bool {
+	return (q.Err == nil) == (other.Err == nil) && slices.Equal(q.Identifiers, other.Identifiers)
+}
+
+// getRegisteredIdentifiers lists the identifiers of the resources registered at path, e.g. /v1/models.
+func (r *LlamaStackDistributionReconciler) getRegisteredIdentifiers(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	pollEndpoint, path string,
+) registeredQuery {
+	start := time.Now()
+	identifiers, err := r.queryIdentifiers(ctx, r.getServerURL(instance, path).String())
+	observeStatusPoll(instance, pollEndpoint, start, err)
+	return registeredQuery{Identifiers: identifiers, Err: err}
+}
+
+// queryIdentifiers makes an HTTP request to a llama-stack list endpoint and returns the sorted identifiers.
+func (r *LlamaStackDistributionReconciler) queryIdentifiers(ctx context.Context, endpoint string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", endpoint, err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request to %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query %s: returned status code %d", endpoint, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", endpoint, err)
+	}
+
+	var response struct {
+		Data []struct {
+			Identifier string `json:"identifier"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response from %s: %w", endpoint, err)
+	}
+
+	identifiers := make([]string, 0, len(response.Data))
+	for _, item := range response.Data {
+		identifiers = append(identifiers, item.Identifier)
+	}
+	slices.Sort(identifiers)
+	return identifiers, nil
+}
+
+// summarizeRegistered builds the status summary of a query, or nil if the query failed.
+func summarizeRegistered(query registeredQuery) *llamav1alpha1.RegisteredResources {
+	if query.Err != nil {
+		return nil
+	}
+
+	summary := &llamav1alpha1.RegisteredResources{Count: len(query.Identifiers)}
+	for _, identifier := range query.Identifiers {
+		if len(summary.Identifiers) == llamav1alpha1.MaxRegisteredIdentifiers {
+			summary.Truncated = true
+			break
+		}
+		if len(identifier) > maxRegisteredIdentifierLength {
+			identifier = identifier[:maxRegisteredIdentifierLength]
+			summary.Truncated = true
+		}
+		summary.Identifiers = append(summary.Identifiers, identifier)
+	}
+	return summary
+}
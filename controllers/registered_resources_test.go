@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeRegistered(t *testing.T) {
+	t.Run("failed query is omitted", func(t *testing.T) {
+		assert.Nil(t, summarizeRegistered(registeredQuery{Err: errors.New("not found")}))
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		summary := summarizeRegistered(registeredQuery{Identifiers: []string{}})
+		require.NotNil(t, summary)
+		assert.Zero(t, summary.Count)
+		assert.False(t, summary.Truncated)
+	})
+
+	t.Run("identifiers are capped", func(t *testing.T) {
+		identifiers := make([]string, 0, llamav1alpha1.MaxRegisteredIdentifiers+5)
+		for i := range llamav1alpha1.MaxRegisteredIdentifiers + 5 {
+			identifiers = append(identifiers, fmt.Sprintf("model-%02d", i))
+		}
+
+		summary := summarizeRegistered(registeredQuery{Identifiers: identifiers})
+		assert.Equal(t, llamav1alpha1.MaxRegisteredIdentifiers+5, summary.Count)
+		assert.Len(t, summary.Identifiers, llamav1alpha1.MaxRegisteredIdentifiers)
+		assert.True(t, summary.Truncated)
+	})
+
+	t.Run("long identifiers are shortened", func(t *testing.T) {
+		summary := summarizeRegistered(registeredQuery{Identifiers: []string{strings.Repeat("a", 300)}})
+		assert.Len(t, summary.Identifiers[0], maxRegisteredIdentifierLength)
+		assert.True(t, summary.Truncated)
+	})
+}
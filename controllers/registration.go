@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// distributionRefIndex indexes registered resources by the distribution they reference.
+	distributionRefIndex = "spec.distributionRef.name"
+
+	// defaultRegistrationResyncInterval is how often registrations are verified, so resources lost
+	// by a server restart are registered again, unless the operator config sets another interval.
+	defaultRegistrationResyncInterval = 2 * time.Minute
+	// registrationTimeout bounds the requests to the server API.
+	registrationTimeout = 10 * time.Second
+)
+
+// registrationResync is the resync interval set by the operator config, shared by the controllers
+// of all the registered resources. The default is used when zero.
+var registrationResync atomic.Int64
+
+// registrationResyncInterval returns how often registrations are verified.
+func registrationResyncInterval() time.Duration {
+	if interval := time.Duration(registrationResync.Load()); interval > 0 {
+		return interval
+	}
+	return defaultRegistrationResyncInterval
+}
+
+// Condition reasons of the resources registered in a distribution.
+const (
+	// ReasonRegistered indicates the resource is registered in the server.
+	ReasonRegistered = "Registered"
+	// ReasonRegistrationFailed indicates the server rejected or failed the registration.
+	ReasonRegistrationFailed = "RegistrationFailed"
+	// ReasonDistributionNotFound indicates the referenced distribution does not exist.
+	ReasonDistributionNotFound = "DistributionNotFound"
+	// ReasonDistributionNotReady indicates the referenced distribution cannot serve API requests yet.
+	ReasonDistributionNotReady = "DistributionNotReady"
+	// ReasonInvalidSpec indicates the spec cannot be turned into a registration request.
+	ReasonInvalidSpec = "InvalidSpec"
+)
+
+// Event reasons emitted on the resources registered in a distribution.
+const (
+	// EventReasonRegistered is emitted when the resource is registered in the server.
+	EventReasonRegistered = "Registered"
+	// EventReasonReregistered is emitted when a registration lost by the server is restored.
+	EventReasonReregistered = "Reregistered"
+	// EventReasonUnregistered is emitted when the resource is removed from the server.
+	EventReasonUnregistered = "Unregistered"
+	// EventReasonRegistrationFailed is emitted when registering or unregistering the resource fails.
+	EventReasonRegistrationFailed = "RegistrationFailed"
+)
+
+// newRegistrationHTTPClient returns the HTTP client used to call the server API.
+func newRegistrationHTTPClient() *http.Client {
+	return &http.Client{Timeout: registrationTimeout}
+}
+
+// getServingDistribution returns the referenced distribution if it can serve API requests. Otherwise
+// it returns nil with the reason and message to report on the registered resource.
+func getServingDistribution(
+	ctx context.Context,
+	reader client.Reader,
+	namespace string,
+	ref llamav1alpha1.DistributionReference,
+) (*llamav1alpha1.LlamaStackDistribution, string, string, error) {
+	distribution := &llamav1alpha1.LlamaStackDistribution{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, distribution); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, ReasonDistributionNotFound, fmt.Sprintf("LlamaStackDistribution %s not found", ref.Name), nil
+		}
+		return nil, "", "", fmt.Errorf("failed to fetch LlamaStackDistribution %s: %w", ref.Name, err)
+	}
+
+	switch {
+	case !distribution.DeletionTimestamp.IsZero():
+		return nil, ReasonDistributionNotReady, fmt.Sprintf("LlamaStackDistribution %s is being deleted", ref.Name), nil
+	case !distribution.HasPorts():
+		return nil, ReasonDistributionNotReady, fmt.Sprintf("LlamaStackDistribution %s does not expose a Service", ref.Name), nil
+	case distribution.Status.Phase != llamav1alpha1.LlamaStackDistributionPhaseReady:
+		return nil, ReasonDistributionNotReady,
+			fmt.Sprintf("LlamaStackDistribution %s is in phase %q", ref.Name, distribution.Status.Phase), nil
+	}
+	return distribution, "", "", nil
+}
+
+// newServerClient returns a client for the API of the distribution server.
+func newServerClient(httpClient *http.Client, distribution *llamav1alpha1.LlamaStackDistribution) (*llamastack.Client, error) {
+	return llamastack.NewClient(httpClient, serverURL(distribution, "").String())
+}
+
+// distributionServingChanged filters distribution updates down to those that may affect the
+// registrations: a phase change, e.g. after the server restarted, or a change in available replicas.
+func distributionServingChanged() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj, oldOK := e.ObjectOld.(*llamav1alpha1.LlamaStackDistribution)
+			newObj, newOK := e.ObjectNew.(*llamav1alpha1.LlamaStackDistribution)
+			if !oldOK || !newOK {
+				return false
+			}
+			return oldObj.Status.Phase != newObj.Status.Phase ||
+				oldObj.Status.AvailableReplicas != newObj.Status.AvailableReplicas
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// requestsForDistribution lists the resources of list referencing the distribution through the
+// distributionRef index, and returns a reconcile request for each of them.
+func requestsForDistribution(ctx context.Context, reader client.Reader, distribution client.Object, list client.ObjectList) []reconcile.Request {
+	if err := reader.List(ctx, list,
+		client.InNamespace(distribution.GetNamespace()),
+		client.MatchingFields{distributionRefIndex: distribution.GetName()},
+	); err != nil {
+		return nil
+	}
+
+	objects, err := meta.ExtractList(list)
+	if err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(objects))
+	for _, obj := range objects {
+		if item, ok := obj.(client.Object); ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(item)})
+		}
+	}
+	return requests
+}
@@ -133,12 +133,25 @@ func getStartupProbe(instance *llamav1alpha1.LlamaStackDistribution) *corev1.Pro
 	}
 }
 
+// containerResources returns the resources of the server container, defaulting to the resources of
+// the operator config when the distribution sets neither requests nor limits.
+func containerResources(r *LlamaStackDistributionReconciler, instance *llamav1alpha1.LlamaStackDistribution) corev1.ResourceRequirements {
+	resources := instance.Spec.Server.ContainerSpec.Resources
+	if len(resources.Requests) > 0 || len(resources.Limits) > 0 || r == nil {
+		return resources
+	}
+	if defaults := r.Settings().DefaultResources; defaults != nil {
+		return *defaults.DeepCopy()
+	}
+	return resources
+}
+
 // buildContainerSpec creates the container specification.
 func buildContainerSpec(ctx context.Context, r *LlamaStackDistributionReconciler, instance *llamav1alpha1.LlamaStackDistribution, image string) corev1.Container {
 	container := corev1.Container{
 		Name:         getContainerName(instance),
 		Image:        image,
-		Resources:    instance.Spec.Server.ContainerSpec.Resources,
+		Resources:    containerResources(r, instance),
 		Ports:        []corev1.ContainerPort{{ContainerPort: getContainerPort(instance)}},
 		StartupProbe: getStartupProbe(instance),
 	}
@@ -198,6 +211,9 @@ func configureContainerEnvironment(ctx context.Context, r *LlamaStackDistributio
 		}
 	}
 
+	// Configure OpenTelemetry export, except for the variables the user env vars override
+	container.Env = append(container.Env, telemetryEnvVars(instance)...)
+
 	// Finally, add the user provided env vars
 	container.Env = append(container.Env, instance.Spec.Server.ContainerSpec.Env...)
 }
@@ -554,13 +570,19 @@ func configureUserConfig(instance *llamav1alpha1.LlamaStackDistribution, podSpec
 		return
 	}
 
+	// Mount the copy with the telemetry provider merged into the run.yaml when telemetry is enabled
+	configMapName := userConfig.ConfigMapName
+	if instance.Spec.Server.Telemetry != nil {
+		configMapName = telemetryRunConfigMapName(instance)
+	}
+
 	// Add ConfigMap volume if user config is specified
 	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
 		Name: "user-config",
 		VolumeSource: corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
 				LocalObjectReference: corev1.LocalObjectReference{
-					Name: userConfig.ConfigMapName,
+					Name: configMapName,
 				},
 			},
 		},
@@ -600,6 +622,11 @@ func configurePodOverrides(instance *llamav1alpha1.LlamaStackDistribution, podSp
 				podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, instance.Spec.Server.PodOverrides.VolumeMounts...)
 			}
 		}
+
+		// Apply the scheduling constraints
+		podSpec.NodeSelector = instance.Spec.Server.PodOverrides.NodeSelector
+		podSpec.Tolerations = instance.Spec.Server.PodOverrides.Tolerations
+		podSpec.Affinity = instance.Spec.Server.PodOverrides.Affinity
 	}
 }
 
@@ -619,18 +646,38 @@ func (r *LlamaStackDistributionReconciler) validateDistribution(instance *llamav
 }
 
 // resolveImage determines the container image to use based on the distribution configuration.
+// Distributions setting neither a name nor an image use the default distribution of the operator
+// config, and the image mirrors of the operator config are applied to the result.
 // It returns the resolved image and any error encountered.
 func (r *LlamaStackDistributionReconciler) resolveImage(distribution llamav1alpha1.DistributionType) (string, error) {
+	settings := r.Settings()
 	distributionMap := r.ClusterInfo.DistributionImages
+	if distribution.Name == "" && distribution.Image == "" {
+		distribution.Name = settings.DefaultDistribution
+	}
 	switch {
 	case distribution.Name != "":
 		if _, exists := distributionMap[distribution.Name]; !exists {
 			return "", fmt.Errorf("failed to validate distribution name: %s", distribution.Name)
 		}
-		return distributionMap[distribution.Name], nil
+		return mirrorImage(distributionMap[distribution.Name], settings.ImageMirrors), nil
 	case distribution.Image != "":
-		return distribution.Image, nil
+		return mirrorImage(distribution.Image, settings.ImageMirrors), nil
 	default:
 		return "", errors.New("failed to validate distribution: either distribution.name or distribution.image must be set")
 	}
 }
+
+// mirrorImage replaces the prefix of the image with the mirror of the first matching rule. A
+// source only matches whole path components, so docker.io/llama does not match docker.io/llamastack.
+func mirrorImage(image string, mirrors []llamav1alpha1.ImageMirror) string {
+	for _, rule := range mirrors {
+		source := strings.TrimSuffix(rule.Source, "/")
+		rest, found := strings.CutPrefix(image, source)
+		if !found || (rest != "" && !strings.ContainsAny(rest[:1], "/:@")) {
+			continue
+		}
+		return strings.TrimSuffix(rule.Mirror, "/") + rest
+	}
+	return image
+}
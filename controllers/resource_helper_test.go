@@ -138,6 +138,53 @@ func TestBuildContainerSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with telemetry",
+			instance: &llamav1alpha1.LlamaStackDistribution{
+				ObjectMeta: metav1.ObjectMeta{Name: "traced"},
+				Spec: llamav1alpha1.LlamaStackDistributionSpec{
+					Server: llamav1alpha1.ServerSpec{
+						ContainerSpec: llamav1alpha1.ContainerSpec{
+							Env: []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: "user-override"}},
+						},
+						Telemetry: &llamav1alpha1.TelemetrySpec{
+							Endpoint:      "http://otel-collector.observability:4318/",
+							SamplingRatio: "0.25",
+							HeadersSecretRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "otel-auth"},
+								Key:                  "headers",
+							},
+						},
+					},
+				},
+			},
+			image: "test-image:latest",
+			expectedResult: corev1.Container{
+				Name:         llamav1alpha1.DefaultContainerName,
+				Image:        "test-image:latest",
+				Ports:        []corev1.ContainerPort{{ContainerPort: llamav1alpha1.DefaultServerPort}},
+				StartupProbe: newDefaultStartupProbe(llamav1alpha1.DefaultServerPort),
+				VolumeMounts: []corev1.VolumeMount{{
+					Name:      "lls-storage",
+					MountPath: llamav1alpha1.DefaultMountPath,
+				}},
+				Env: []corev1.EnvVar{
+					{Name: "HF_HOME", Value: "/.llama"},
+					{Name: "TELEMETRY_SINKS", Value: "console,sqlite,otel_trace,otel_metric"},
+					{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector.observability:4318"},
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_TRACES_SAMPLER", Value: "parentbased_traceidratio"},
+					{Name: "OTEL_TRACES_SAMPLER_ARG", Value: "0.25"},
+					{Name: "OTEL_EXPORTER_OTLP_HEADERS", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "otel-auth"},
+							Key:                  "headers",
+						},
+					}},
+					{Name: "OTEL_SERVICE_NAME", Value: "user-override"},
+				},
+			},
+		},
 		{
 			name: "with user config",
 			instance: &llamav1alpha1.LlamaStackDistribution{
@@ -446,6 +493,73 @@ func TestResolveImage(t *testing.T) {
 	}
 }
 
+func TestResolveImageWithOperatorSettings(t *testing.T) {
+	r := &LlamaStackDistributionReconciler{ClusterInfo: setupTestClusterInfo(map[string]string{
+		"ollama": "docker.io/llamastack/distribution-ollama:latest",
+	})}
+	r.SetSettings(OperatorSettings{
+		DefaultDistribution: "ollama",
+		ImageMirrors: []llamav1alpha1.ImageMirror{
+			{Source: "docker.io/llama", Mirror: "never.example.com"},
+			{Source: "docker.io/llamastack", Mirror: "registry.example.com/mirror/"},
+		},
+	})
+
+	image, err := r.resolveImage(createLSD("", "").Spec.Server.Distribution)
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/mirror/distribution-ollama:latest", image, "the default distribution is mirrored")
+
+	image, err = r.resolveImage(createLSD("", "quay.io/custom/image:v1").Spec.Server.Distribution)
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/custom/image:v1", image, "images without a matching rule are kept")
+}
+
+func TestMirrorImage(t *testing.T) {
+	mirrors := []llamav1alpha1.ImageMirror{
+		{Source: "docker.io/llamastack", Mirror: "registry.example.com/llamastack"},
+		{Source: "quay.io", Mirror: "mirror.example.com/quay"},
+	}
+	testCases := map[string]string{
+		"docker.io/llamastack/distribution-starter:latest": "registry.example.com/llamastack/distribution-starter:latest",
+		"quay.io/org/image@sha256:abc":                     "mirror.example.com/quay/org/image@sha256:abc",
+		"docker.io/llamastack2/image:latest":               "docker.io/llamastack2/image:latest",
+		"ghcr.io/org/image:v1":                             "ghcr.io/org/image:v1",
+	}
+	for image, expected := range testCases {
+		assert.Equal(t, expected, mirrorImage(image, mirrors), image)
+	}
+}
+
+func TestConfigureUserConfigMountsTelemetryRunConfig(t *testing.T) {
+	instance := createLSD("ollama", "")
+	instance.Spec.Server.UserConfig = &llamav1alpha1.UserConfigSpec{ConfigMapName: "user-config"}
+	volumeConfigMap := func() string {
+		podSpec := corev1.PodSpec{}
+		configureUserConfig(instance, &podSpec)
+		require.Len(t, podSpec.Volumes, 1)
+		return podSpec.Volumes[0].ConfigMap.Name
+	}
+
+	assert.Equal(t, "user-config", volumeConfigMap())
+
+	instance.Spec.Server.Telemetry = &llamav1alpha1.TelemetrySpec{Endpoint: "http://otel-collector:4318"}
+	assert.Equal(t, instance.Name+"-run-config", volumeConfigMap())
+}
+
+func TestContainerResourcesDefaults(t *testing.T) {
+	defaults := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	r := &LlamaStackDistributionReconciler{}
+	r.SetSettings(OperatorSettings{DefaultResources: defaults})
+
+	instance := createLSD("ollama", "")
+	assert.Equal(t, *defaults, containerResources(r, instance))
+
+	instance.Spec.Server.ContainerSpec.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}
+	assert.Equal(t, instance.Spec.Server.ContainerSpec.Resources, containerResources(r, instance), "the distribution resources take precedence")
+}
+
 func TestDistributionValidation(t *testing.T) {
 	// Setup test cluster info
 	clusterInfo := setupTestClusterInfo(map[string]string{
@@ -574,6 +688,29 @@ func TestPodOverridesWithoutServiceAccount(t *testing.T) {
 	}
 }
 
+func TestPodOverridesScheduling(t *testing.T) {
+	affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-instance", Namespace: "test-namespace"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{
+				PodOverrides: &llamav1alpha1.PodOverrides{
+					NodeSelector: map[string]string{"gpu": "true"},
+					Tolerations:  []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+					Affinity:     affinity,
+				},
+			},
+		},
+	}
+
+	podSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: "test-container"}}}
+	configurePodOverrides(instance, &podSpec)
+
+	assert.Equal(t, map[string]string{"gpu": "true"}, podSpec.NodeSelector)
+	assert.Equal(t, instance.Spec.Server.PodOverrides.Tolerations, podSpec.Tolerations)
+	assert.Same(t, affinity, podSpec.Affinity)
+}
+
 func TestValidateConfigMapKeys(t *testing.T) {
 	tests := []struct {
 		name        string
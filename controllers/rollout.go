@@ -0,0 +1,423 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+const (
+	// rolloutRequeueInterval is how often a rollout in progress is re-evaluated.
+	rolloutRequeueInterval = 10 * time.Second
+	// providerHealthError is the provider health status reported by llama-stack for unhealthy providers.
+	providerHealthError = "Error"
+	// blueGreenPreviewStep is the BlueGreen step during which the preview revision warms up.
+	blueGreenPreviewStep = 0
+)
+
+// usesProgressiveRollout returns true if the instance uses a Canary or BlueGreen rollout.
+func usesProgressiveRollout(instance *llamav1alpha1.LlamaStackDistribution) bool {
+	if instance.Spec.RolloutStrategy == nil || deploy.GetDesiredReplicas(instance) == 0 {
+		return false
+	}
+	switch instance.Spec.RolloutStrategy.Type {
+	case llamav1alpha1.RolloutStrategyCanary:
+		return instance.Spec.RolloutStrategy.Canary != nil && len(instance.Spec.RolloutStrategy.Canary.Steps) > 0
+	case llamav1alpha1.RolloutStrategyBlueGreen:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRolloutInProgress returns true if a Canary or BlueGreen rollout is running.
+func isRolloutInProgress(instance *llamav1alpha1.LlamaStackDistribution) bool {
+	return instance.Status.Rollout != nil && instance.Status.Rollout.Phase != "" &&
+		instance.Status.Rollout.Phase != llamav1alpha1.RolloutPhaseIdle
+}
+
+// reconcileRollout adjusts the rendered manifests for Canary and BlueGreen rollouts.
+// While a new revision is being rolled out, the stable Deployment keeps its current pod
+// template and the new revision runs in a separate preview Deployment. The returned
+// ResMap is what should be applied for the main resources.
+func (r *LlamaStackDistributionReconciler) reconcileRollout(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	resMap *resmap.ResMap,
+) (*resmap.ResMap, error) {
+	if !usesProgressiveRollout(instance) {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, ConditionTypeRolloutStrategyAccepted)
+		instance.Status.Rollout = nil
+		return resMap, r.deletePreviewResources(ctx, instance)
+	}
+	// The preview pods would mount the ReadWriteOnce claim of the stable ones. Distributions created
+	// before the CRD rejected this combination fall back to the rolling update of the Deployment.
+	if instance.Spec.Server.Storage != nil {
+		SetRolloutStrategyAcceptedCondition(&instance.Status, false, fmt.Sprintf(
+			"%s rollouts cannot be used with server.storage, changes are applied with a rolling update", instance.Spec.RolloutStrategy.Type))
+		instance.Status.Rollout = nil
+		return resMap, r.deletePreviewResources(ctx, instance)
+	}
+	SetRolloutStrategyAcceptedCondition(&instance.Status, true, fmt.Sprintf("Changes are rolled out with a %s rollout", instance.Spec.RolloutStrategy.Type))
+
+	strategy := instance.Spec.RolloutStrategy.Type
+	revision, err := deploy.PrepareStableDeployment(resMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare stable Deployment: %w", err)
+	}
+
+	stable := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, stable)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to fetch stable Deployment: %w", err)
+	}
+	stableExists := err == nil
+
+	// Nothing to roll out when the Deployment is new or already runs the desired revision,
+	// unless a promotion into it is still in progress.
+	if !stableExists || stable.Annotations[deploy.RolloutRevisionAnnotation] == revision {
+		if stableExists && r.isPromotionPending(instance, stable, revision) {
+			return r.applyPromotion(instance, resMap, strategy)
+		}
+		instance.Status.Rollout = &llamav1alpha1.RolloutStatus{
+			Strategy:       strategy,
+			Phase:          llamav1alpha1.RolloutPhaseIdle,
+			StableRevision: revision,
+			Message:        "Stable revision is up to date",
+		}
+		if strategy == llamav1alpha1.RolloutStrategyBlueGreen {
+			if err := deploy.SetServiceTrack(resMap, deploy.RolloutTrackStable); err != nil {
+				return nil, fmt.Errorf("failed to pin Service to the stable track: %w", err)
+			}
+		}
+		return resMap, r.deletePreviewResources(ctx, instance)
+	}
+
+	status := r.startOrContinueRollout(instance, stable, revision)
+
+	previewReplicas, done, err := r.advanceRollout(ctx, instance, status)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return r.applyPromotion(instance, resMap, strategy)
+	}
+
+	// Run the new revision next to the stable one.
+	previewResMap, err := deploy.BuildPreviewResources(resMap, instance, previewReplicas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build preview resources: %w", err)
+	}
+	if err := deploy.ApplyResources(ctx, r.Client, r.Scheme, r.Recorder, instance, previewResMap); err != nil {
+		return nil, fmt.Errorf("failed to apply preview resources: %w", err)
+	}
+
+	// Keep the stable Deployment on its current template until promotion.
+	stableResMap, err := deploy.FilterExcludeKinds(resMap, []string{"Deployment"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter stable Deployment: %w", err)
+	}
+
+	switch strategy {
+	case llamav1alpha1.RolloutStrategyCanary:
+		if err := r.scaleStableDeployment(ctx, stable, deploy.GetDesiredReplicas(instance)-previewReplicas); err != nil {
+			return nil, err
+		}
+	case llamav1alpha1.RolloutStrategyBlueGreen:
+		// Stable pods only carry the track label once they were deployed with a
+		// progressive strategy. Until then the Service cannot exclude the preview pods.
+		if stable.Spec.Template.Labels[deploy.RolloutTrackLabel] == deploy.RolloutTrackStable {
+			if err := deploy.SetServiceTrack(stableResMap, deploy.RolloutTrackStable); err != nil {
+				return nil, fmt.Errorf("failed to pin Service to the stable track: %w", err)
+			}
+		}
+	case llamav1alpha1.RolloutStrategyRollingUpdate:
+		// Not reachable, RollingUpdate does not use a preview Deployment.
+	}
+
+	return stableResMap, nil
+}
+
+// SetRolloutStrategyAcceptedCondition sets the RolloutStrategyAccepted condition.
+func SetRolloutStrategyAcceptedCondition(status *llamav1alpha1.LlamaStackDistributionStatus, accepted bool, message string) {
+	condition := metav1.Condition{
+		Type:    ConditionTypeRolloutStrategyAccepted,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonRolloutStrategyAccepted,
+		Message: message,
+	}
+	if !accepted {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonRolloutStorageConflict
+	}
+	setConditionKeepingTransitionTime(status, condition)
+}
+
+// startOrContinueRollout returns the rollout status for the update revision, resetting
+// the progress if the revision changed since the last reconciliation.
+func (r *LlamaStackDistributionReconciler) startOrContinueRollout(
+	instance *llamav1alpha1.LlamaStackDistribution,
+	stable *appsv1.Deployment,
+	revision string,
+) *llamav1alpha1.RolloutStatus {
+	status := instance.Status.Rollout
+	if status == nil || status.UpdateRevision != revision || status.Strategy != instance.Spec.RolloutStrategy.Type {
+		status = &llamav1alpha1.RolloutStatus{
+			Strategy:       instance.Spec.RolloutStrategy.Type,
+			StableRevision: stable.Annotations[deploy.RolloutRevisionAnnotation],
+			UpdateRevision: revision,
+		}
+		instance.Status.Rollout = status
+	}
+	status.Phase = llamav1alpha1.RolloutPhaseProgressing
+	return status
+}
+
+// advanceRollout evaluates the current step of the rollout and moves to the next one once
+// its checks pass and its pause elapsed. It returns the number of preview replicas for the
+// current step and whether all steps are complete.
+func (r *LlamaStackDistributionReconciler) advanceRollout(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	status *llamav1alpha1.RolloutStatus,
+) (int32, bool, error) {
+	totalSteps, weight, pause := rolloutStep(instance, status.CurrentStep)
+	if status.CurrentStep >= totalSteps {
+		return 0, true, nil
+	}
+	previewReplicas := previewReplicasForWeight(deploy.GetDesiredReplicas(instance), weight)
+	status.CurrentWeight = weight
+
+	if err := r.checkPreviewReady(ctx, instance, previewReplicas); err != nil {
+		status.StepHealthyTime = nil
+		status.Message = fmt.Sprintf("Step %d/%d waiting for new revision: %v", status.CurrentStep+1, totalSteps, err)
+		return previewReplicas, false, nil
+	}
+
+	now := metav1.NewTime(metav1.Now().UTC())
+	if status.StepHealthyTime == nil {
+		status.StepHealthyTime = &now
+	}
+	if remaining := pause - now.Sub(status.StepHealthyTime.Time); remaining > 0 {
+		status.Message = fmt.Sprintf("Step %d/%d healthy at %d%%, pausing for %s",
+			status.CurrentStep+1, totalSteps, weight, remaining.Round(time.Second))
+		return previewReplicas, false, nil
+	}
+
+	log.FromContext(ctx).Info("Rollout step completed",
+		"step", status.CurrentStep+1, "steps", totalSteps, "weight", weight, "revision", status.UpdateRevision)
+	status.CurrentStep++
+	status.StepHealthyTime = nil
+	if status.CurrentStep >= totalSteps {
+		return previewReplicas, true, nil
+	}
+	_, nextWeight, _ := rolloutStep(instance, status.CurrentStep)
+	status.Message = fmt.Sprintf("Step %d/%d started at %d%%", status.CurrentStep+1, totalSteps, nextWeight)
+	return previewReplicasForWeight(deploy.GetDesiredReplicas(instance), nextWeight), false, nil
+}
+
+// rolloutStep returns the number of steps of the rollout strategy together with the
+// weight and pause of the given step.
+func rolloutStep(instance *llamav1alpha1.LlamaStackDistribution, step int32) (int32, int32, time.Duration) {
+	strategy := instance.Spec.RolloutStrategy
+	if strategy.Type == llamav1alpha1.RolloutStrategyBlueGreen {
+		var delay time.Duration
+		if strategy.BlueGreen != nil && strategy.BlueGreen.PromotionDelay != nil {
+			delay = strategy.BlueGreen.PromotionDelay.Duration
+		}
+		// BlueGreen has a single step: the preview runs at full scale until it is promoted.
+		return blueGreenPreviewStep + 1, 100, delay
+	}
+
+	steps := strategy.Canary.Steps
+	totalSteps := int32(len(steps)) //nolint:gosec // bounded by the CRD MaxItems validation
+	if step >= totalSteps {
+		return totalSteps, 100, 0
+	}
+	var pause time.Duration
+	if steps[step].Pause != nil {
+		pause = steps[step].Pause.Duration
+	}
+	return totalSteps, steps[step].Weight, pause
+}
+
+// previewReplicasForWeight returns the number of replicas for the new revision at the
+// given weight. At least one replica is used so that every step can be health checked.
+func previewReplicasForWeight(replicas, weight int32) int32 {
+	const percent = 100
+	preview := (replicas*weight + percent - 1) / percent
+	return min(max(preview, 1), replicas)
+}
+
+// checkPreviewReady verifies that the preview Deployment is fully available and that the
+// new revision passes the same health and provider checks used for the status. Like the
+// status, the checks use the background health probe of the preview when the prober runs.
+func (r *LlamaStackDistributionReconciler) checkPreviewReady(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution, replicas int32) error {
+	preview := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: deploy.GetPreviewDeploymentName(instance), Namespace: instance.Namespace}
+	if err := r.Get(ctx, key, preview); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return errors.New("failed to find preview Deployment")
+		}
+		return fmt.Errorf("failed to fetch preview Deployment: %w", err)
+	}
+
+	if preview.Status.ObservedGeneration < preview.Generation ||
+		preview.Status.UpdatedReplicas < replicas || preview.Status.ReadyReplicas < replicas {
+		return fmt.Errorf("failed to reach readiness: %d/%d replicas ready", preview.Status.ReadyReplicas, replicas)
+	}
+
+	// Without a Service there is no endpoint to query, so readiness is all we can check.
+	if !instance.HasPorts() || r.httpClient == nil {
+		return nil
+	}
+	if r.healthProber == nil {
+		return r.probePreview(ctx, instance)
+	}
+
+	instanceKey := client.ObjectKeyFromObject(instance)
+	result, ok := r.healthProber.Result(instanceKey)
+	if !ok || result.PreviewRevision != instance.Status.Rollout.UpdateRevision {
+		// The probe result of the new revision triggers a reconciliation once it is available.
+		r.healthProber.Trigger(instanceKey)
+		return errors.New("failed to find a health probe of the new revision yet")
+	}
+	return result.PreviewErr
+}
+
+// probePreview queries the health and providers of the preview revision.
+func (r *LlamaStackDistributionReconciler) probePreview(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) error {
+	if err := r.checkHealthEndpoint(ctx, r.getPreviewServerURL(instance, "/v1/health").String()); err != nil {
+		return err
+	}
+	providers, err := r.queryProviders(ctx, r.getPreviewServerURL(instance, "/v1/providers").String())
+	if err != nil {
+		return err
+	}
+	for _, provider := range providers {
+		if provider.Health.Status == providerHealthError {
+			return fmt.Errorf("failed to pass provider health check: provider %s reports %s", provider.ProviderID, provider.Health.Message)
+		}
+	}
+	return nil
+}
+
+// checkHealthEndpoint queries the llama-stack health endpoint and expects a successful response.
+func (r *LlamaStackDistributionReconciler) checkHealthEndpoint(ctx context.Context, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health request: %w", err)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make health request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to query health endpoint: returned status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isPromotionPending returns true if the stable Deployment was updated to the new revision
+// but has not finished rolling it out yet.
+func (r *LlamaStackDistributionReconciler) isPromotionPending(
+	instance *llamav1alpha1.LlamaStackDistribution,
+	stable *appsv1.Deployment,
+	revision string,
+) bool {
+	status := instance.Status.Rollout
+	if status == nil || status.Phase != llamav1alpha1.RolloutPhasePromoting || status.UpdateRevision != revision {
+		return false
+	}
+	replicas := deploy.GetDesiredReplicas(instance)
+	return stable.Status.ObservedGeneration < stable.Generation ||
+		stable.Status.UpdatedReplicas < replicas ||
+		stable.Status.ReadyReplicas < replicas
+}
+
+// applyPromotion applies the new revision to the stable Deployment while the preview keeps
+// serving. For BlueGreen the Service is pointed at the preview until the stable Deployment
+// has caught up.
+func (r *LlamaStackDistributionReconciler) applyPromotion(
+	instance *llamav1alpha1.LlamaStackDistribution,
+	resMap *resmap.ResMap,
+	strategy llamav1alpha1.RolloutStrategyType,
+) (*resmap.ResMap, error) {
+	status := instance.Status.Rollout
+	status.Phase = llamav1alpha1.RolloutPhasePromoting
+	status.CurrentWeight = 100
+	status.StepHealthyTime = nil
+	status.Message = "Promoting new revision to stable"
+
+	if strategy == llamav1alpha1.RolloutStrategyBlueGreen {
+		if err := deploy.SetServiceTrack(resMap, deploy.RolloutTrackPreview); err != nil {
+			return nil, fmt.Errorf("failed to switch Service to the preview track: %w", err)
+		}
+	}
+	return resMap, nil
+}
+
+// scaleStableDeployment sets the number of replicas of the stable Deployment without
+// touching its pod template.
+func (r *LlamaStackDistributionReconciler) scaleStableDeployment(ctx context.Context, stable *appsv1.Deployment, replicas int32) error {
+	if stable.Spec.Replicas != nil && *stable.Spec.Replicas == replicas {
+		return nil
+	}
+	patch := client.MergeFrom(stable.DeepCopy())
+	stable.Spec.Replicas = &replicas
+	if err := r.Patch(ctx, stable, patch); err != nil {
+		return fmt.Errorf("failed to scale stable Deployment: %w", err)
+	}
+	return nil
+}
+
+// deletePreviewResources removes the preview Deployment and Service once no rollout is running.
+func (r *LlamaStackDistributionReconciler) deletePreviewResources(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) error {
+	previews := []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: deploy.GetPreviewDeploymentName(instance), Namespace: instance.Namespace}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: deploy.GetPreviewServiceName(instance), Namespace: instance.Namespace}},
+	}
+
+	for _, obj := range previews {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to fetch preview resource %s: %w", obj.GetName(), err)
+		}
+		if !metav1.IsControlledBy(obj, instance) {
+			continue
+		}
+		log.FromContext(ctx).Info("Deleting preview resource after rollout", "name", obj.GetName())
+		if err := r.Delete(ctx, obj); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete preview resource %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// getPreviewReadyReplicas returns the number of ready replicas of the preview Deployment.
+func (r *LlamaStackDistributionReconciler) getPreviewReadyReplicas(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) int32 {
+	preview := &appsv1.Deployment{}
+	key := types.NamespacedName{Name: deploy.GetPreviewDeploymentName(instance), Namespace: instance.Namespace}
+	if err := r.Get(ctx, key, preview); err != nil {
+		return 0
+	}
+	return preview.Status.ReadyReplicas
+}
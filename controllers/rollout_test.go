@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestPreviewReplicasForWeight(t *testing.T) {
+	testCases := []struct {
+		name     string
+		replicas int32
+		weight   int32
+		expected int32
+	}{
+		{name: "rounds up partial replicas", replicas: 4, weight: 30, expected: 2},
+		{name: "exact share", replicas: 4, weight: 50, expected: 2},
+		{name: "keeps at least one replica", replicas: 10, weight: 1, expected: 1},
+		{name: "single replica", replicas: 1, weight: 20, expected: 1},
+		{name: "full weight", replicas: 3, weight: 100, expected: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, previewReplicasForWeight(tc.replicas, tc.weight))
+		})
+	}
+}
+
+func TestRolloutStep(t *testing.T) {
+	t.Run("canary steps", func(t *testing.T) {
+		instance := &llamav1alpha1.LlamaStackDistribution{
+			Spec: llamav1alpha1.LlamaStackDistributionSpec{
+				Replicas: 4,
+				RolloutStrategy: &llamav1alpha1.RolloutStrategy{
+					Type: llamav1alpha1.RolloutStrategyCanary,
+					Canary: &llamav1alpha1.CanaryStrategy{
+						Steps: []llamav1alpha1.CanaryStep{
+							{Weight: 25, Pause: &metav1.Duration{Duration: time.Minute}},
+							{Weight: 50},
+						},
+					},
+				},
+			},
+		}
+
+		steps, weight, pause := rolloutStep(instance, 0)
+		assert.Equal(t, int32(2), steps)
+		assert.Equal(t, int32(25), weight)
+		assert.Equal(t, time.Minute, pause)
+
+		_, weight, pause = rolloutStep(instance, 1)
+		assert.Equal(t, int32(50), weight)
+		assert.Zero(t, pause)
+
+		_, weight, _ = rolloutStep(instance, 2)
+		assert.Equal(t, int32(100), weight, "completed rollouts run at full weight")
+	})
+
+	t.Run("blue-green runs a single full-scale step", func(t *testing.T) {
+		instance := &llamav1alpha1.LlamaStackDistribution{
+			Spec: llamav1alpha1.LlamaStackDistributionSpec{
+				Replicas: 2,
+				RolloutStrategy: &llamav1alpha1.RolloutStrategy{
+					Type: llamav1alpha1.RolloutStrategyBlueGreen,
+					BlueGreen: &llamav1alpha1.BlueGreenStrategy{
+						PromotionDelay: &metav1.Duration{Duration: 5 * time.Minute},
+					},
+				},
+			},
+		}
+
+		steps, weight, pause := rolloutStep(instance, 0)
+		assert.Equal(t, int32(1), steps)
+		assert.Equal(t, int32(100), weight)
+		assert.Equal(t, 5*time.Minute, pause)
+	})
+}
+
+func TestUsesProgressiveRollout(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{Replicas: 1},
+	}
+	assert.False(t, usesProgressiveRollout(instance), "no strategy configured")
+
+	instance.Spec.RolloutStrategy = &llamav1alpha1.RolloutStrategy{Type: llamav1alpha1.RolloutStrategyRollingUpdate}
+	assert.False(t, usesProgressiveRollout(instance), "rolling updates are handled by the Deployment")
+
+	instance.Spec.RolloutStrategy = &llamav1alpha1.RolloutStrategy{Type: llamav1alpha1.RolloutStrategyBlueGreen}
+	assert.True(t, usesProgressiveRollout(instance))
+
+	instance.Spec.Replicas = 0
+	assert.False(t, usesProgressiveRollout(instance), "nothing to roll out without replicas")
+}
+
+func TestCheckPreviewReadyUsesHealthProbe(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Server: llamav1alpha1.ServerSpec{ContainerSpec: llamav1alpha1.ContainerSpec{Port: llamav1alpha1.DefaultServerPort}},
+		},
+		Status: llamav1alpha1.LlamaStackDistributionStatus{
+			Rollout: &llamav1alpha1.RolloutStatus{Phase: llamav1alpha1.RolloutPhaseProgressing, UpdateRevision: "new"},
+		},
+	}
+	k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+		WithObjects(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "llsd-preview", Namespace: "ns"},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, ReadyReplicas: 1},
+		}).
+		Build()
+	key := client.ObjectKeyFromObject(instance)
+
+	testCases := []struct {
+		name          string
+		result        *healthProbeResult
+		expectedError string
+	}{
+		{
+			name:          "waits for the first probe",
+			expectedError: "failed to find a health probe of the new revision yet",
+		},
+		{
+			name:          "ignores the probe of a previous revision",
+			result:        &healthProbeResult{PreviewRevision: "old"},
+			expectedError: "failed to find a health probe of the new revision yet",
+		},
+		{
+			name:          "reports a failed probe",
+			result:        &healthProbeResult{PreviewRevision: "new", PreviewErr: errors.New("failed to make health request")},
+			expectedError: "failed to make health request",
+		},
+		{
+			name:   "passes a healthy probe",
+			result: &healthProbeResult{PreviewRevision: "new"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prober := newHealthProber(k8sClient, nil, HealthProberOptions{})
+			if tc.result != nil {
+				prober.results[key] = *tc.result
+			}
+			r := &LlamaStackDistributionReconciler{Client: k8sClient, httpClient: &http.Client{}, healthProber: prober}
+
+			err := r.checkPreviewReady(t.Context(), instance, 1)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedError)
+			if tc.result == nil || tc.result.PreviewRevision != "new" {
+				assert.Len(t, prober.triggers, 1, "a probe of the new revision should be requested")
+			}
+		})
+	}
+}
+
+func TestReconcileRolloutRejectsStorage(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Replicas:        2,
+			RolloutStrategy: &llamav1alpha1.RolloutStrategy{Type: llamav1alpha1.RolloutStrategyBlueGreen},
+			Server:          llamav1alpha1.ServerSpec{Storage: &llamav1alpha1.StorageSpec{}},
+		},
+		Status: llamav1alpha1.LlamaStackDistributionStatus{
+			Rollout: &llamav1alpha1.RolloutStatus{Phase: llamav1alpha1.RolloutPhaseProgressing},
+		},
+	}
+	resMap, err := deploy.RenderManifest(filesys.MakeFsOnDisk(), manifestsBasePath, instance)
+	require.NoError(t, err)
+	k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).Build()
+	r := &LlamaStackDistributionReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+	applied, err := r.reconcileRollout(t.Context(), instance, resMap)
+	require.NoError(t, err)
+
+	assert.Same(t, resMap, applied, "the stable Deployment should be rolled out as usual")
+	assert.Nil(t, instance.Status.Rollout)
+	condition := GetCondition(&instance.Status, ConditionTypeRolloutStrategyAccepted)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonRolloutStorageConflict, condition.Reason)
+	assert.Equal(t, "BlueGreen rollouts cannot be used with server.storage, changes are applied with a rolling update", condition.Message)
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/schedule"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// scheduleRequeueDelay is added to the next schedule boundary so that it has passed when reconciling.
+const scheduleRequeueDelay = time.Second
+
+// updateScheduleStatus reports the active schedule entry and when the next one fires.
+func updateScheduleStatus(instance *llamav1alpha1.LlamaStackDistribution) {
+	if len(instance.Spec.Schedule) == 0 {
+		instance.Status.Schedule = nil
+		return
+	}
+
+	evaluation := schedule.Evaluate(instance.Spec.Schedule, time.Now())
+	status := &llamav1alpha1.ScheduleStatus{}
+	if evaluation.Active != nil {
+		replicas := evaluation.Active.Replicas
+		activeSince := metav1.NewTime(evaluation.ActiveSince.UTC())
+		status.ActiveEntry = schedule.EntryName(evaluation.Active)
+		status.Replicas = &replicas
+		status.ActiveSince = &activeSince
+	}
+	if !evaluation.NextTransition.IsZero() {
+		nextTransition := metav1.NewTime(evaluation.NextTransition.UTC())
+		status.NextTransition = &nextTransition
+	}
+	if len(evaluation.Errors) > 0 {
+		status.Message = errors.Join(evaluation.Errors...).Error()
+	}
+	instance.Status.Schedule = status
+}
+
+// scheduleRequeueAfter returns how long to wait for the next schedule boundary, zero if there is none.
+func scheduleRequeueAfter(instance *llamav1alpha1.LlamaStackDistribution) time.Duration {
+	if instance.Status.Schedule == nil || instance.Status.Schedule.NextTransition == nil {
+		return 0
+	}
+	return max(time.Until(instance.Status.Schedule.NextTransition.Time)+scheduleRequeueDelay, scheduleRequeueDelay)
+}
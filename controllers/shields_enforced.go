@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// requiredShields returns the sorted identifiers of the required LlamaStackShields referencing the
+// distribution. Shields being deleted are no longer required.
+func (r *LlamaStackDistributionReconciler) requiredShields(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) ([]string, error) {
+	shields := &llamav1alpha1.LlamaStackShieldList{}
+	if err := r.List(ctx, shields, client.InNamespace(instance.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list LlamaStackShields: %w", err)
+	}
+
+	var required []string
+	for i := range shields.Items {
+		shield := &shields.Items[i]
+		if shield.Spec.DistributionRef.Name != instance.Name || !shield.DeletionTimestamp.IsZero() || !shield.IsRequired() {
+			continue
+		}
+		required = append(required, shield.GetShieldID())
+	}
+	slices.Sort(required)
+	return slices.Compact(required), nil
+}
+
+// applyShieldsEnforced checks the required shields against the shields listed by the last poll.
+// The condition is only reported for distributions with required shields.
+func (r *LlamaStackDistributionReconciler) applyShieldsEnforced(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	registered *registeredQuery,
+	unknownMessage string,
+) {
+	required, err := r.requiredShields(ctx, instance)
+	if err != nil {
+		// Keep the previous condition, the next reconciliation retries.
+		log.FromContext(ctx).Error(err, "failed to check the required shields")
+		return
+	}
+	if len(required) == 0 {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, ConditionTypeShieldsEnforced)
+		return
+	}
+
+	switch {
+	case registered == nil:
+		SetShieldsUnknownCondition(&instance.Status, unknownMessage)
+	case registered.Err != nil:
+		SetShieldsUnknownCondition(&instance.Status, fmt.Sprintf("Failed to list shields: %v", registered.Err))
+	default:
+		SetShieldsEnforcedCondition(&instance.Status, required, registered.Identifiers)
+	}
+}
+
+// SetShieldsEnforcedCondition reports the required shields missing from the registered ones in the
+// ShieldsEnforced condition. The registered identifiers must be sorted.
+func SetShieldsEnforcedCondition(status *llamav1alpha1.LlamaStackDistributionStatus, required, registered []string) {
+	var missing []string
+	for _, shieldID := range required {
+		if _, found := slices.BinarySearch(registered, shieldID); !found {
+			missing = append(missing, shieldID)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    ConditionTypeShieldsEnforced,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonShieldsEnforced,
+		Message: fmt.Sprintf("All %d required shields are registered", len(required)),
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonShieldsMissing
+		condition.Message = fmt.Sprintf("%d of %d required shields are not registered: %s",
+			len(missing), len(required), strings.Join(missing, ", "))
+	}
+	setConditionKeepingTransitionTime(status, condition)
+}
+
+// SetShieldsUnknownCondition reports that the registered shields are unknown.
+func SetShieldsUnknownCondition(status *llamav1alpha1.LlamaStackDistributionStatus, message string) {
+	setConditionKeepingTransitionTime(status, metav1.Condition{
+		Type:    ConditionTypeShieldsEnforced,
+		Status:  metav1.ConditionUnknown,
+		Reason:  ReasonShieldsUnknown,
+		Message: message,
+	})
+}
+
+// recordShieldsEnforcedChange emits an Event when the ShieldsEnforced condition turns False, or
+// back to True after having been False.
+func (r *LlamaStackDistributionReconciler) recordShieldsEnforcedChange(instance *llamav1alpha1.LlamaStackDistribution, previous metav1.ConditionStatus) {
+	condition := GetCondition(&instance.Status, ConditionTypeShieldsEnforced)
+	if condition == nil || condition.Status == previous {
+		return
+	}
+
+	switch {
+	case condition.Status == metav1.ConditionFalse:
+		r.Recorder.Event(instance, corev1.EventTypeWarning, EventReasonShieldsMissing, condition.Message)
+	case condition.Status == metav1.ConditionTrue && previous == metav1.ConditionFalse:
+		r.Recorder.Event(instance, corev1.EventTypeNormal, EventReasonShieldsEnforced, condition.Message)
+	}
+}
+
+// findDistributionForShield maps a LlamaStackShield to the distribution it references, so the
+// ShieldsEnforced condition follows required shields being added or removed.
+func (r *LlamaStackDistributionReconciler) findDistributionForShield(_ context.Context, obj client.Object) []reconcile.Request {
+	shield, ok := obj.(*llamav1alpha1.LlamaStackShield)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: shield.Namespace, Name: shield.Spec.DistributionRef.Name}}}
+}
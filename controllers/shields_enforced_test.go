@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestShield(name, distribution string, required *bool) *llamav1alpha1.LlamaStackShield {
+	return &llamav1alpha1.LlamaStackShield{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shield-ns"},
+		Spec: llamav1alpha1.LlamaStackShieldSpec{
+			DistributionRef: llamav1alpha1.DistributionReference{Name: distribution},
+			ProviderID:      "llama-guard",
+			Required:        required,
+		},
+	}
+}
+
+func TestApplyShieldsEnforced(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, llamav1alpha1.AddToScheme(scheme))
+	k8sClient := clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newTestShield("llama-guard", "llsd", nil),
+		newTestShield("prompt-guard", "llsd", ptr.To(true)),
+		newTestShield("code-scanner", "llsd", ptr.To(false)),
+		newTestShield("other", "other-llsd", nil),
+	).Build()
+	recorder := record.NewFakeRecorder(5)
+	r := &LlamaStackDistributionReconciler{Client: k8sClient, Recorder: recorder}
+	instance := &llamav1alpha1.LlamaStackDistribution{ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "shield-ns"}}
+
+	apply := func(t *testing.T, registered *registeredQuery) *metav1.Condition {
+		t.Helper()
+		var previous metav1.ConditionStatus
+		if condition := GetCondition(&instance.Status, ConditionTypeShieldsEnforced); condition != nil {
+			previous = condition.Status
+		}
+		r.applyShieldsEnforced(t.Context(), instance, registered, "Deployment not ready")
+		r.recordShieldsEnforcedChange(instance, previous)
+		return GetCondition(&instance.Status, ConditionTypeShieldsEnforced)
+	}
+
+	t.Run("required shields are registered", func(t *testing.T) {
+		condition := apply(t, &registeredQuery{Identifiers: []string{"llama-guard", "other", "prompt-guard"}})
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "All 2 required shields are registered", condition.Message, "optional shields are not required")
+		assert.Empty(t, recorder.Events, "no Event until a shield goes missing")
+	})
+
+	t.Run("a required shield is missing", func(t *testing.T) {
+		condition := apply(t, &registeredQuery{Identifiers: []string{"code-scanner", "prompt-guard"}})
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, ReasonShieldsMissing, condition.Reason)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning ShieldsMissing 1 of 2 required shields are not registered: llama-guard", <-recorder.Events)
+	})
+
+	t.Run("unknown while the shields cannot be listed", func(t *testing.T) {
+		condition := apply(t, &registeredQuery{Err: errors.New("connection refused")})
+		assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+		assert.Equal(t, "Failed to list shields: connection refused", condition.Message)
+
+		condition = apply(t, nil)
+		assert.Equal(t, "Deployment not ready", condition.Message)
+	})
+
+	t.Run("enforced again", func(t *testing.T) {
+		apply(t, &registeredQuery{Identifiers: []string{"code-scanner", "prompt-guard"}})
+		<-recorder.Events
+		condition := apply(t, &registeredQuery{Identifiers: []string{"llama-guard", "prompt-guard"}})
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal ShieldsEnforced All 2 required shields are registered", <-recorder.Events)
+	})
+
+	t.Run("no condition without required shields", func(t *testing.T) {
+		other := &llamav1alpha1.LlamaStackDistribution{ObjectMeta: metav1.ObjectMeta{Name: "unguarded", Namespace: "shield-ns"}}
+		SetShieldsUnknownCondition(&other.Status, "stale")
+		r.applyShieldsEnforced(t.Context(), other, &registeredQuery{}, "")
+		assert.Nil(t, GetCondition(&other.Status, ConditionTypeShieldsEnforced))
+	})
+}
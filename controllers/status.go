@@ -15,6 +15,20 @@ const (
 	ConditionTypeStorageReady = "StorageReady"
 	// ConditionTypeServiceReady indicates whether the service is ready.
 	ConditionTypeServiceReady = "ServiceReady"
+	// ConditionTypeReconcilePaused indicates whether reconciliation is paused.
+	ConditionTypeReconcilePaused = "ReconcilePaused"
+	// ConditionTypeProvidersHealthy indicates whether all providers of the server report a healthy status.
+	ConditionTypeProvidersHealthy = "ProvidersHealthy"
+	// ConditionTypeShieldsEnforced indicates whether every required LlamaStackShield is registered in the server.
+	ConditionTypeShieldsEnforced = "ShieldsEnforced"
+	// ConditionTypeDrifted indicates whether managed resources were changed outside the operator and left as they are.
+	ConditionTypeDrifted = "Drifted"
+	// ConditionTypeResourcesOwned indicates whether every existing rendered resource is owned by the distribution.
+	ConditionTypeResourcesOwned = "ResourcesOwned"
+	// ConditionTypeTelemetryConfigured indicates whether the server run.yaml reads the OpenTelemetry settings of spec.server.telemetry.
+	ConditionTypeTelemetryConfigured = "TelemetryConfigured"
+	// ConditionTypeRolloutStrategyAccepted indicates whether the Canary or BlueGreen rollout strategy can be used.
+	ConditionTypeRolloutStrategyAccepted = "RolloutStrategyAccepted"
 )
 
 // Condition reasons.
@@ -25,6 +39,8 @@ const (
 	ReasonDeploymentFailed = "DeploymentFailed"
 	// ReasonDeploymentPending indicates the deployment is pending.
 	ReasonDeploymentPending = "DeploymentPending"
+	// ReasonDeploymentSuspended indicates the deployment is scaled down by spec.suspend.
+	ReasonDeploymentSuspended = "DeploymentSuspended"
 	// ReasonHealthCheckPassed indicates the health check passed.
 	ReasonHealthCheckPassed = "HealthCheckPassed"
 	// ReasonHealthCheckFailed indicates the health check failed.
@@ -37,6 +53,38 @@ const (
 	ReasonServiceReady = "ServiceReady"
 	// ReasonServiceFailed indicates the service failed.
 	ReasonServiceFailed = "ServiceFailed"
+	// ReasonReconcilePaused indicates reconciliation is paused by annotation.
+	ReasonReconcilePaused = "ReconcilePaused"
+	// ReasonReconcileResumed indicates reconciliation resumed after being paused.
+	ReasonReconcileResumed = "ReconcileResumed"
+	// ReasonProvidersHealthy indicates no provider reports an error.
+	ReasonProvidersHealthy = "ProvidersHealthy"
+	// ReasonProvidersDegraded indicates at least one provider reports an error.
+	ReasonProvidersDegraded = "ProvidersDegraded"
+	// ReasonProvidersUnknown indicates the provider health is not known.
+	ReasonProvidersUnknown = "ProvidersUnknown"
+	// ReasonShieldsEnforced indicates every required shield is registered.
+	ReasonShieldsEnforced = "ShieldsEnforced"
+	// ReasonShieldsMissing indicates at least one required shield is not registered.
+	ReasonShieldsMissing = "ShieldsMissing"
+	// ReasonShieldsUnknown indicates the registered shields are not known.
+	ReasonShieldsUnknown = "ShieldsUnknown"
+	// ReasonNoDrift indicates the managed resources match their desired state, or were corrected.
+	ReasonNoDrift = "NoDrift"
+	// ReasonDriftDetected indicates at least one managed resource was changed outside the operator.
+	ReasonDriftDetected = "DriftDetected"
+	// ReasonResourcesOwned indicates every existing rendered resource is owned by the distribution.
+	ReasonResourcesOwned = "ResourcesOwned"
+	// ReasonResourcesNotOwned indicates at least one existing resource was skipped because the distribution does not own it.
+	ReasonResourcesNotOwned = "ResourcesNotOwned"
+	// ReasonTelemetryConfigured indicates the server run.yaml reads the OpenTelemetry settings.
+	ReasonTelemetryConfigured = "TelemetryConfigured"
+	// ReasonTelemetryNotApplied indicates the telemetry provider cannot be merged into the run.yaml provided through userConfig.
+	ReasonTelemetryNotApplied = "TelemetryNotApplied"
+	// ReasonRolloutStrategyAccepted indicates the Canary or BlueGreen rollout strategy is used.
+	ReasonRolloutStrategyAccepted = "RolloutStrategyAccepted"
+	// ReasonRolloutStorageConflict indicates the rollout strategy is ignored because the server uses persistent storage.
+	ReasonRolloutStorageConflict = "RolloutStorageConflict"
 )
 
 // Condition messages.
@@ -47,6 +95,8 @@ const (
 	MessageDeploymentFailed = "Deployment failed"
 	// MessageDeploymentPending indicates the deployment is pending.
 	MessageDeploymentPending = "Deployment is pending"
+	// MessageDeploymentSuspended indicates the deployment is suspended.
+	MessageDeploymentSuspended = "Deployment is suspended"
 	// MessageHealthCheckPassed indicates the health check passed.
 	MessageHealthCheckPassed = "Health check passed"
 	// MessageHealthCheckFailed indicates the health check failed.
@@ -59,6 +109,10 @@ const (
 	MessageServiceReady = "Service is ready"
 	// MessageServiceFailed indicates the service failed.
 	MessageServiceFailed = "Service failed"
+	// MessageReconcilePaused indicates reconciliation is paused.
+	MessageReconcilePaused = "Reconciliation is paused by the " + llamav1alpha1.ReconcileAnnotation + " annotation"
+	// MessageReconcileResumed indicates reconciliation resumed.
+	MessageReconcileResumed = "Reconciliation is active"
 )
 
 // SetDeploymentReadyCondition sets the deployment ready condition.
@@ -80,6 +134,17 @@ func SetDeploymentReadyCondition(status *llamav1alpha1.LlamaStackDistributionSta
 	SetCondition(status, condition)
 }
 
+// SetDeploymentSuspendedCondition marks the deployment as not ready because it is suspended.
+func SetDeploymentSuspendedCondition(status *llamav1alpha1.LlamaStackDistributionStatus, message string) {
+	SetCondition(status, metav1.Condition{
+		Type:               ConditionTypeDeploymentReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonDeploymentSuspended,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(metav1.Now().UTC()),
+	})
+}
+
 // SetHealthCheckCondition sets the health check condition.
 func SetHealthCheckCondition(status *llamav1alpha1.LlamaStackDistributionStatus, healthy bool, message string) {
 	condition := metav1.Condition{
@@ -137,6 +202,25 @@ func SetServiceReadyCondition(status *llamav1alpha1.LlamaStackDistributionStatus
 	SetCondition(status, condition)
 }
 
+// SetReconcilePausedCondition sets the reconcile paused condition.
+func SetReconcilePausedCondition(status *llamav1alpha1.LlamaStackDistributionStatus, paused bool) {
+	condition := metav1.Condition{
+		Type:               ConditionTypeReconcilePaused,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonReconcilePaused,
+		Message:            MessageReconcilePaused,
+		LastTransitionTime: metav1.NewTime(metav1.Now().UTC()),
+	}
+
+	if !paused {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonReconcileResumed
+		condition.Message = MessageReconcileResumed
+	}
+
+	SetCondition(status, condition)
+}
+
 // SetCondition sets a condition in the status.
 func SetCondition(status *llamav1alpha1.LlamaStackDistributionStatus, condition metav1.Condition) {
 	// Initialize conditions if needed
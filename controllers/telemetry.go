@@ -0,0 +1,357 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// telemetrySinks enables the OpenTelemetry sinks of the llama-stack telemetry provider while
+	// keeping the default console and sqlite sinks. The bundled run.yaml files read the sinks,
+	// service name and endpoint of the telemetry provider from these environment variables.
+	telemetrySinks = "console,sqlite,otel_trace,otel_metric"
+	// otelTracesSampler honors the sampling decision of incoming requests.
+	otelTracesSampler = "parentbased_traceidratio"
+	// otelEndpointEnvVar holds the collector endpoint read by the telemetry provider.
+	otelEndpointEnvVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// userConfigRunYAMLKey is the key of the userConfig ConfigMap mounted as the server run.yaml.
+	userConfigRunYAMLKey = "run.yaml"
+	// telemetryRunConfigSuffix is appended to the instance name for the ConfigMap holding the
+	// userConfig run.yaml with the telemetry provider merged into it.
+	telemetryRunConfigSuffix = "-run-config"
+	// telemetryAPI is the llama-stack API, and provider category, of the telemetry provider.
+	telemetryAPI = "telemetry"
+	// telemetryProviderID and telemetryProviderType identify the telemetry provider merged into a user run.yaml.
+	telemetryProviderID   = "meta-reference"
+	telemetryProviderType = "inline::meta-reference"
+)
+
+// telemetryProviderConfig is the config merged into the telemetry provider of a user run.yaml. As in
+// the bundled run.yaml files, it reads the settings from the environment variables.
+var telemetryProviderConfig = [][2]string{
+	{"service_name", "${env.OTEL_SERVICE_NAME:=llama-stack}"},
+	{"sinks", "${env.TELEMETRY_SINKS:=console,sqlite}"},
+	{"otel_exporter_otlp_endpoint", "${env." + otelEndpointEnvVar + ":=}"},
+}
+
+// telemetryEnvVars returns the environment variables configuring OpenTelemetry export in the server.
+// Variables set in the container env are left out, as duplicate names are rejected by server-side apply.
+func telemetryEnvVars(instance *llamav1alpha1.LlamaStackDistribution) []corev1.EnvVar {
+	spec := instance.Spec.Server.Telemetry
+	if spec == nil {
+		return nil
+	}
+
+	protocol := spec.Protocol
+	if protocol == "" {
+		protocol = llamav1alpha1.OTLPProtocolHTTPProtobuf
+	}
+	serviceName := spec.ServiceName
+	if serviceName == "" {
+		serviceName = instance.Name
+	}
+	samplingRatio := spec.SamplingRatio
+	if samplingRatio == "" {
+		samplingRatio = telemetry.DefaultSamplingRatio
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "TELEMETRY_SINKS", Value: telemetrySinks},
+		{Name: otelEndpointEnvVar, Value: strings.TrimSuffix(spec.Endpoint, "/")},
+		{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: string(protocol)},
+		{Name: "OTEL_SERVICE_NAME", Value: serviceName},
+		{Name: "OTEL_TRACES_SAMPLER", Value: otelTracesSampler},
+		{Name: "OTEL_TRACES_SAMPLER_ARG", Value: samplingRatio},
+	}
+	if spec.HeadersSecretRef != nil {
+		env = append(env, corev1.EnvVar{
+			Name:      "OTEL_EXPORTER_OTLP_HEADERS",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: spec.HeadersSecretRef.DeepCopy()},
+		})
+	}
+	return slices.DeleteFunc(env, func(envVar corev1.EnvVar) bool {
+		return slices.ContainsFunc(instance.Spec.Server.ContainerSpec.Env, func(userVar corev1.EnvVar) bool {
+			return userVar.Name == envVar.Name
+		})
+	})
+}
+
+// reconcileTelemetryRunConfig makes the server run.yaml export to the collector of spec.server.telemetry.
+// The bundled run.yaml files read the OpenTelemetry settings already. A run.yaml provided through
+// userConfig is copied to the ConfigMap returned by telemetryRunConfigMapName with the telemetry
+// provider merged into it, and the copy is mounted instead. The outcome is reported in the
+// TelemetryConfigured condition.
+func (r *LlamaStackDistributionReconciler) reconcileTelemetryRunConfig(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) error {
+	if instance.Spec.Server.Telemetry == nil || !r.hasUserConfigMap(instance) {
+		if err := r.deleteTelemetryRunConfig(ctx, instance); err != nil {
+			return err
+		}
+		if instance.Spec.Server.Telemetry == nil {
+			meta.RemoveStatusCondition(&instance.Status.Conditions, ConditionTypeTelemetryConfigured)
+			return nil
+		}
+		SetTelemetryConfiguredCondition(&instance.Status, true, "The bundled run.yaml exports traces and metrics to the collector")
+		return nil
+	}
+
+	namespace, name := r.getUserConfigMapNamespace(instance), instance.Spec.Server.UserConfig.ConfigMapName
+	userConfig := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, userConfig); err != nil {
+		return fmt.Errorf("failed to fetch ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	runYAML, err := mergeTelemetryRunYAML(userConfig.Data[userConfigRunYAMLKey])
+	if err != nil {
+		message := fmt.Sprintf("The telemetry provider cannot be merged into the run.yaml of ConfigMap %s/%s: %v", namespace, name, err)
+		if !IsConditionFalse(&instance.Status, ConditionTypeTelemetryConfigured) {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, EventReasonTelemetryNotApplied, message)
+		}
+		SetTelemetryConfiguredCondition(&instance.Status, false, message)
+		return fmt.Errorf("failed to merge the telemetry provider into the run.yaml of ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	runConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: telemetryRunConfigMapName(instance), Namespace: instance.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, runConfig, func() error {
+		runConfig.Data = maps.Clone(userConfig.Data)
+		runConfig.Data[userConfigRunYAMLKey] = runYAML
+		runConfig.BinaryData = maps.Clone(userConfig.BinaryData)
+		return controllerutil.SetControllerReference(instance, runConfig, r.Scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile ConfigMap %s: %w", runConfig.Name, err)
+	}
+	SetTelemetryConfiguredCondition(&instance.Status, true, fmt.Sprintf(
+		"The telemetry provider is merged into the run.yaml of ConfigMap %s/%s, mounted from ConfigMap %s", namespace, name, runConfig.Name))
+	return nil
+}
+
+// deleteTelemetryRunConfig deletes the run.yaml copy once telemetry or the userConfig is removed. The
+// TelemetryConfigured condition is only set while telemetry is enabled, so the lookup is skipped otherwise.
+func (r *LlamaStackDistributionReconciler) deleteTelemetryRunConfig(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) error {
+	if GetCondition(&instance.Status, ConditionTypeTelemetryConfigured) == nil {
+		return nil
+	}
+	runConfig := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: telemetryRunConfigMapName(instance), Namespace: instance.Namespace}, runConfig); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch ConfigMap %s: %w", telemetryRunConfigMapName(instance), err)
+	}
+	if !metav1.IsControlledBy(runConfig, instance) {
+		return nil
+	}
+	if err := r.Delete(ctx, runConfig); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ConfigMap %s: %w", runConfig.Name, err)
+	}
+	return nil
+}
+
+// telemetryRunConfigMapName returns the name of the ConfigMap holding the run.yaml of the userConfig
+// with the telemetry provider merged into it.
+func telemetryRunConfigMapName(instance *llamav1alpha1.LlamaStackDistribution) string {
+	return instance.Name + telemetryRunConfigSuffix
+}
+
+// mergeTelemetryRunYAML returns the run.yaml with the telemetry API enabled and the meta-reference
+// telemetry provider reading the OpenTelemetry settings from the environment variables set by
+// telemetryEnvVars. The other settings, their order and comments are kept.
+func mergeTelemetryRunYAML(runYAML string) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(runYAML), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse run.yaml: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", errors.New("failed to parse run.yaml: the document is not a mapping")
+	}
+	root := doc.Content[0]
+
+	if apis := yamlMappingValue(root, "apis"); apis != nil && apis.Kind == yaml.SequenceNode &&
+		!slices.ContainsFunc(apis.Content, func(api *yaml.Node) bool { return api.Value == telemetryAPI }) {
+		apis.Content = append(apis.Content, yamlScalar(telemetryAPI))
+	}
+
+	providers, err := yamlEnsureNode(root, "providers", yaml.MappingNode)
+	if err != nil {
+		return "", err
+	}
+	telemetryProviders, err := yamlEnsureNode(providers, telemetryAPI, yaml.SequenceNode)
+	if err != nil {
+		return "", err
+	}
+	var provider *yaml.Node
+	for _, candidate := range telemetryProviders.Content {
+		if candidate.Kind == yaml.MappingNode && yamlScalarValue(candidate, "provider_type") == telemetryProviderType {
+			provider = candidate
+			break
+		}
+	}
+	if provider == nil {
+		provider = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+			yamlScalar("provider_id"), yamlScalar(telemetryProviderID),
+			yamlScalar("provider_type"), yamlScalar(telemetryProviderType),
+		}}
+		telemetryProviders.Content = append(telemetryProviders.Content, provider)
+	}
+	config, err := yamlEnsureNode(provider, "config", yaml.MappingNode)
+	if err != nil {
+		return "", err
+	}
+	for _, setting := range telemetryProviderConfig {
+		if value := yamlMappingValue(config, setting[0]); value != nil {
+			*value = *yamlScalar(setting[1])
+			continue
+		}
+		config.Content = append(config.Content, yamlScalar(setting[0]), yamlScalar(setting[1]))
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return "", fmt.Errorf("failed to encode run.yaml: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode run.yaml: %w", err)
+	}
+	return out.String(), nil
+}
+
+// yamlMappingValue returns the value of key in a mapping node, or nil if the key is not set.
+func yamlMappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlScalarValue returns the scalar value of key in a mapping node, or "" if it is not a scalar.
+func yamlScalarValue(mapping *yaml.Node, key string) string {
+	if value := yamlMappingValue(mapping, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// yamlEnsureNode returns the value of key in a mapping node, adding an empty node of the given kind
+// when the key is not set or null.
+func yamlEnsureNode(mapping *yaml.Node, key string, kind yaml.Kind) (*yaml.Node, error) {
+	value := yamlMappingValue(mapping, key)
+	if value == nil {
+		value = &yaml.Node{Kind: kind}
+		mapping.Content = append(mapping.Content, yamlScalar(key), value)
+	} else if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+		*value = yaml.Node{Kind: kind}
+	}
+	if value.Kind != kind {
+		return nil, fmt.Errorf("failed to merge run.yaml: %s has an unexpected type", key)
+	}
+	return value, nil
+}
+
+func yamlScalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+// SetTelemetryConfiguredCondition sets the TelemetryConfigured condition.
+func SetTelemetryConfiguredCondition(status *llamav1alpha1.LlamaStackDistributionStatus, configured bool, message string) {
+	condition := metav1.Condition{
+		Type:    ConditionTypeTelemetryConfigured,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonTelemetryConfigured,
+		Message: message,
+	}
+	if !configured {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonTelemetryNotApplied
+	}
+	setConditionKeepingTransitionTime(status, condition)
+}
+
+// startSpan starts a span for the instance, exported to the collector configured in its telemetry spec.
+func (r *LlamaStackDistributionReconciler) startSpan(
+	ctx context.Context,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	name string,
+) (context.Context, trace.Span) {
+	// Child spans join the trace started for the reconciliation.
+	if parent := trace.SpanFromContext(ctx); parent.SpanContext().IsValid() {
+		return parent.TracerProvider().Tracer(telemetry.TracerName).Start(ctx, name)
+	}
+
+	tracer := otel.Tracer(telemetry.TracerName)
+	if r.tracers != nil {
+		headers, err := r.telemetryHeaders(ctx, instance)
+		if err == nil {
+			tracer, err = r.tracers.Tracer(ctx, instance.Spec.Server.Telemetry, headers)
+		}
+		if err != nil {
+			log.FromContext(ctx).Error(err, "failed to create tracer, reconcile spans are not exported")
+			tracer = otel.Tracer(telemetry.TracerName)
+		}
+	}
+
+	return tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.String("k8s.namespace.name", instance.Namespace),
+		attribute.String("llamastack.distribution.name", instance.Name),
+		attribute.Int64("llamastack.distribution.generation", instance.Generation),
+	))
+}
+
+// telemetryHeaders returns the export headers of the Secret key selected by spec.server.telemetry, so
+// the reconcile spans are accepted by collectors requiring authentication like those of the server.
+func (r *LlamaStackDistributionReconciler) telemetryHeaders(ctx context.Context, instance *llamav1alpha1.LlamaStackDistribution) (map[string]string, error) {
+	spec := instance.Spec.Server.Telemetry
+	if spec == nil || spec.HeadersSecretRef == nil {
+		return nil, nil
+	}
+
+	ref := spec.HeadersSecretRef
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: instance.Namespace}, secret); err != nil {
+		if k8serrors.IsNotFound(err) && ptr.Deref(ref.Optional, false) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get telemetry headers Secret %s/%s: %w", instance.Namespace, ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		if ptr.Deref(ref.Optional, false) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find key %s in telemetry headers Secret %s/%s", ref.Key, instance.Namespace, ref.Name)
+	}
+	return telemetry.ParseHeaders(string(value))
+}
+
+// endSpan records err on the span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMergeTelemetryRunYAML(t *testing.T) {
+	t.Run("adds the telemetry API and provider", func(t *testing.T) {
+		merged, err := mergeTelemetryRunYAML(`# Llama Stack Configuration
+version: '2'
+apis:
+- inference
+providers:
+  inference:
+  - provider_id: ollama
+    provider_type: remote::ollama
+`)
+		require.NoError(t, err)
+		assert.Equal(t, `# Llama Stack Configuration
+version: '2'
+apis:
+  - inference
+  - telemetry
+providers:
+  inference:
+    - provider_id: ollama
+      provider_type: remote::ollama
+  telemetry:
+    - provider_id: meta-reference
+      provider_type: inline::meta-reference
+      config:
+        service_name: ${env.OTEL_SERVICE_NAME:=llama-stack}
+        sinks: ${env.TELEMETRY_SINKS:=console,sqlite}
+        otel_exporter_otlp_endpoint: ${env.OTEL_EXPORTER_OTLP_ENDPOINT:=}
+`, merged)
+	})
+
+	t.Run("updates the existing telemetry provider", func(t *testing.T) {
+		merged, err := mergeTelemetryRunYAML(`apis: [inference, telemetry]
+providers:
+  telemetry:
+  - provider_id: meta-reference
+    provider_type: inline::meta-reference
+    config:
+      sinks: console
+      sqlite_db_path: /data/trace_store.db
+`)
+		require.NoError(t, err)
+		assert.Equal(t, `apis: [inference, telemetry]
+providers:
+  telemetry:
+    - provider_id: meta-reference
+      provider_type: inline::meta-reference
+      config:
+        sinks: ${env.TELEMETRY_SINKS:=console,sqlite}
+        sqlite_db_path: /data/trace_store.db
+        service_name: ${env.OTEL_SERVICE_NAME:=llama-stack}
+        otel_exporter_otlp_endpoint: ${env.OTEL_EXPORTER_OTLP_ENDPOINT:=}
+`, merged)
+	})
+
+	t.Run("rejects a run.yaml that is not a mapping", func(t *testing.T) {
+		for _, runYAML := range []string{"", "- inference\n", "providers: [telemetry]\n"} {
+			_, err := mergeTelemetryRunYAML(runYAML)
+			require.Error(t, err, runYAML)
+		}
+	})
+}
+
+func TestReconcileTelemetryRunConfig(t *testing.T) {
+	newInstance := func() *llamav1alpha1.LlamaStackDistribution {
+		return &llamav1alpha1.LlamaStackDistribution{
+			ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns", UID: "llsd-uid"},
+			Spec: llamav1alpha1.LlamaStackDistributionSpec{
+				Server: llamav1alpha1.ServerSpec{
+					Telemetry: &llamav1alpha1.TelemetrySpec{Endpoint: "http://otel-collector:4318"},
+				},
+			},
+		}
+	}
+	newReconciler := func(t *testing.T, runYAML string) (*LlamaStackDistributionReconciler, *record.FakeRecorder) {
+		t.Helper()
+		k8sClient := clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+			WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "user-config", Namespace: "ns"},
+				Data:       map[string]string{userConfigRunYAMLKey: runYAML, "extra.yaml": "kept: true\n"},
+			}).
+			Build()
+		recorder := record.NewFakeRecorder(10)
+		return &LlamaStackDistributionReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}, recorder
+	}
+	getRunConfig := func(t *testing.T, r *LlamaStackDistributionReconciler) (*corev1.ConfigMap, error) {
+		t.Helper()
+		runConfig := &corev1.ConfigMap{}
+		err := r.Get(t.Context(), types.NamespacedName{Name: "llsd-run-config", Namespace: "ns"}, runConfig)
+		return runConfig, err
+	}
+
+	t.Run("reports the bundled run.yaml as configured", func(t *testing.T) {
+		r, recorder := newReconciler(t, "")
+		instance := newInstance()
+
+		require.NoError(t, r.reconcileTelemetryRunConfig(t.Context(), instance))
+
+		assert.True(t, IsConditionTrue(&instance.Status, ConditionTypeTelemetryConfigured))
+		assert.Empty(t, recorder.Events)
+		_, err := getRunConfig(t, r)
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("merges the telemetry provider into a copy of the user run.yaml", func(t *testing.T) {
+		r, _ := newReconciler(t, "apis:\n- inference\n")
+		instance := newInstance()
+		instance.Spec.Server.UserConfig = &llamav1alpha1.UserConfigSpec{ConfigMapName: "user-config"}
+
+		require.NoError(t, r.reconcileTelemetryRunConfig(t.Context(), instance))
+
+		condition := GetCondition(&instance.Status, ConditionTypeTelemetryConfigured)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, "The telemetry provider is merged into the run.yaml of ConfigMap ns/user-config, mounted from ConfigMap llsd-run-config", condition.Message)
+		runConfig, err := getRunConfig(t, r)
+		require.NoError(t, err)
+		assert.True(t, metav1.IsControlledBy(runConfig, instance))
+		assert.Equal(t, "kept: true\n", runConfig.Data["extra.yaml"])
+		assert.Contains(t, runConfig.Data[userConfigRunYAMLKey], "otel_exporter_otlp_endpoint: ${env.OTEL_EXPORTER_OTLP_ENDPOINT:=}")
+
+		instance.Spec.Server.Telemetry = nil
+		require.NoError(t, r.reconcileTelemetryRunConfig(t.Context(), instance))
+
+		assert.Nil(t, GetCondition(&instance.Status, ConditionTypeTelemetryConfigured))
+		_, err = getRunConfig(t, r)
+		assert.True(t, k8serrors.IsNotFound(err), "the copy should be deleted once telemetry is disabled")
+	})
+
+	t.Run("warns once about a user run.yaml that cannot be merged", func(t *testing.T) {
+		r, recorder := newReconciler(t, "- inference\n")
+		instance := newInstance()
+		instance.Spec.Server.UserConfig = &llamav1alpha1.UserConfigSpec{ConfigMapName: "user-config"}
+
+		require.Error(t, r.reconcileTelemetryRunConfig(t.Context(), instance))
+		require.Error(t, r.reconcileTelemetryRunConfig(t.Context(), instance))
+
+		condition := GetCondition(&instance.Status, ConditionTypeTelemetryConfigured)
+		require.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, ReasonTelemetryNotApplied, condition.Reason)
+		assert.Contains(t, condition.Message, "The telemetry provider cannot be merged into the run.yaml of ConfigMap ns/user-config")
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning TelemetryNotApplied "+condition.Message, <-recorder.Events)
+	})
+}
+
+func TestTelemetryHeaders(t *testing.T) {
+	newInstance := func(ref *corev1.SecretKeySelector) *llamav1alpha1.LlamaStackDistribution {
+		return &llamav1alpha1.LlamaStackDistribution{
+			ObjectMeta: metav1.ObjectMeta{Name: "llsd", Namespace: "ns"},
+			Spec: llamav1alpha1.LlamaStackDistributionSpec{
+				Server: llamav1alpha1.ServerSpec{
+					Telemetry: &llamav1alpha1.TelemetrySpec{Endpoint: "http://otel-collector:4318", HeadersSecretRef: ref},
+				},
+			},
+		}
+	}
+	r := &LlamaStackDistributionReconciler{
+		Client: clientfake.NewClientBuilder().WithScheme(newOperatorConfigScheme(t)).
+			WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "otel-headers", Namespace: "ns"},
+				Data:       map[string][]byte{"headers": []byte("authorization=Bearer%20token")},
+			}).
+			Build(),
+	}
+	selector := func(name, key string, optional bool) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key, Optional: ptr.To(optional),
+		}
+	}
+
+	t.Run("reads the headers of the Secret", func(t *testing.T) {
+		headers, err := r.telemetryHeaders(t.Context(), newInstance(selector("otel-headers", "headers", false)))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"authorization": "Bearer token"}, headers)
+	})
+
+	t.Run("returns no headers without a Secret reference", func(t *testing.T) {
+		headers, err := r.telemetryHeaders(t.Context(), newInstance(nil))
+		require.NoError(t, err)
+		assert.Nil(t, headers)
+	})
+
+	t.Run("fails for a missing Secret key unless it is optional", func(t *testing.T) {
+		_, err := r.telemetryHeaders(t.Context(), newInstance(selector("otel-headers", "missing", false)))
+		require.Error(t, err)
+
+		headers, err := r.telemetryHeaders(t.Context(), newInstance(selector("missing", "headers", true)))
+		require.NoError(t, err)
+		assert.Nil(t, headers)
+	})
+}
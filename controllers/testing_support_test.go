@@ -19,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -401,7 +402,7 @@ func ReconcileDistribution(t *testing.T, instance *llamav1alpha1.LlamaStackDistr
 	t.Helper()
 	// Create reconciler and run reconciliation
 	reconciler := createTestReconciler()
-	reconciler.EnableNetworkPolicy = enableNetworkPolicy
+	reconciler.SetSettings(controllers.OperatorSettings{EnableNetworkPolicy: enableNetworkPolicy})
 	_, err := reconciler.Reconcile(t.Context(), ctrl.Request{
 		NamespacedName: types.NamespacedName{
 			Name:      instance.Name,
@@ -426,6 +427,7 @@ func createTestReconciler() *controllers.LlamaStackDistributionReconciler {
 		Client:      k8sClient,
 		Scheme:      scheme.Scheme,
 		ClusterInfo: clusterInfo,
+		Recorder:    &record.FakeRecorder{},
 	}
 }
 
@@ -25,8 +25,12 @@ import (
 	llamaxk8siov1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
 	"github.com/llamastack/llama-stack-k8s-operator/controllers"
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/cluster"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/ingest"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -58,17 +62,72 @@ func init() { //nolint:gochecknoinits
 	//+kubebuilder:scaffold:scheme
 }
 
-func setupReconciler(ctx context.Context, cli client.Client, mgr ctrl.Manager, clusterInfo *cluster.ClusterInfo) error {
-	reconciler, err := controllers.NewLlamaStackDistributionReconciler(ctx, cli, scheme, clusterInfo)
+func setupReconciler(ctx context.Context, cli client.Client, mgr ctrl.Manager, clusterInfo *cluster.ClusterInfo,
+	proberOptions controllers.HealthProberOptions, ingestionImage string) error {
+	reconciler, err := controllers.NewLlamaStackDistributionReconciler(ctx, cli, scheme, clusterInfo,
+		mgr.GetEventRecorderFor("llama-stack-operator"), proberOptions)
 	if err != nil {
 		return fmt.Errorf("failed to create reconciler: %w", err)
 	}
 	if err = reconciler.SetupWithManager(ctx, mgr); err != nil {
 		return fmt.Errorf("failed to create controller: %w", err)
 	}
+
+	modelReconciler := controllers.NewLlamaStackModelReconciler(mgr.GetClient(), scheme, mgr.GetEventRecorderFor("llama-stack-operator"))
+	if err = modelReconciler.SetupWithManager(ctx, mgr); err != nil {
+		return fmt.Errorf("failed to create LlamaStackModel controller: %w", err)
+	}
+
+	vectorStoreReconciler := controllers.NewLlamaStackVectorStoreReconciler(mgr.GetClient(), mgr.GetAPIReader(), scheme,
+		mgr.GetEventRecorderFor("llama-stack-operator"), ingestionImage)
+	if err = vectorStoreReconciler.SetupWithManager(ctx, mgr); err != nil {
+		return fmt.Errorf("failed to create LlamaStackVectorStore controller: %w", err)
+	}
+
+	toolGroupReconciler := controllers.NewLlamaStackToolGroupReconciler(mgr.GetClient(), scheme, mgr.GetEventRecorderFor("llama-stack-operator"))
+	if err = toolGroupReconciler.SetupWithManager(ctx, mgr); err != nil {
+		return fmt.Errorf("failed to create LlamaStackToolGroup controller: %w", err)
+	}
+
+	shieldReconciler := controllers.NewLlamaStackShieldReconciler(mgr.GetClient(), scheme, mgr.GetEventRecorderFor("llama-stack-operator"))
+	if err = shieldReconciler.SetupWithManager(ctx, mgr); err != nil {
+		return fmt.Errorf("failed to create LlamaStackShield controller: %w", err)
+	}
 	return nil
 }
 
+// resolveIngestionImage returns the image of the operator container, which also runs the ingestion
+// Jobs. The pod name is the hostname of the container.
+func resolveIngestionImage(ctx context.Context, cli client.Client) (string, error) {
+	podName, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod name: %w", err)
+	}
+	namespace, err := deploy.GetOperatorNamespace()
+	if err != nil {
+		return "", fmt.Errorf("failed to get operator namespace: %w", err)
+	}
+
+	pod := &corev1.Pod{}
+	if err := cli.Get(ctx, types.NamespacedName{Name: podName, Namespace: namespace}, pod); err != nil {
+		return "", fmt.Errorf("failed to fetch operator pod: %w", err)
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "manager" {
+			return container.Image, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find the manager container in pod %s", podName)
+}
+
+// runIngest runs the ingest subcommand executed by the ingestion Jobs.
+func runIngest(args []string) int {
+	ctx := ctrl.SetupSignalHandler()
+	logger := zap.New(zap.UseDevMode(false))
+	ctrl.SetLogger(logger)
+	return ingest.Main(logf.IntoContext(ctx, logger), args)
+}
+
 func setupHealthChecks(mgr ctrl.Manager) error {
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		return fmt.Errorf("failed to set up health check: %w", err)
@@ -80,14 +139,26 @@ func setupHealthChecks(mgr ctrl.Manager) error {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == ingest.Command {
+		os.Exit(runIngest(os.Args[2:]))
+	}
+
 	var metricsAddr string
+	var ingestionImage string
 	var enableLeaderElection bool
 	var probeAddr string
+	var proberOptions controllers.HealthProberOptions
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&proberOptions.Interval, "distribution-health-interval", controllers.DefaultHealthProbeInterval,
+		"How often the providers and version of ready distributions are polled. Set to 0 to poll during reconciliation instead.")
+	flag.IntVar(&proberOptions.Concurrency, "distribution-health-concurrency", controllers.DefaultHealthProbeConcurrency,
+		"The maximum number of distributions polled in parallel.")
+	flag.StringVar(&ingestionImage, "ingestion-image", "",
+		"The image of the document ingestion Jobs. Defaults to the image of the operator.")
 	opts := zap.Options{
 		Development:     false,
 		StacktraceLevel: zapcore.PanicLevel, // Set higher than ErrorLevel to avoid stack traces in logs
@@ -152,7 +223,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := setupReconciler(ctx, setupClient, mgr, clusterInfo); err != nil {
+	if ingestionImage == "" {
+		if ingestionImage, err = resolveIngestionImage(ctx, setupClient); err != nil {
+			setupLog.Error(err, "failed to resolve the ingestion image, document ingestion is disabled")
+		}
+	}
+
+	if err := setupReconciler(ctx, setupClient, mgr, clusterInfo, proberOptions, ingestionImage); err != nil {
 		setupLog.Error(err, "failed to set up reconciler")
 		os.Exit(1)
 	}
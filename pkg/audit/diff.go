@@ -0,0 +1,159 @@
+// Package audit computes redacted, structured diffs of LlamaStackDistribution specs.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/redact"
+)
+
+// envValuePattern matches the literal value of an environment variable, which commonly carries secrets.
+var envValuePattern = regexp.MustCompile(`(^|\.)env\[\d+\]\.value$`)
+
+// Change is a single field that differs between two objects.
+type Change struct {
+	// Path is the dotted path of the field, with list indexes in brackets.
+	Path string `json:"path"`
+	// Old is the previous value, omitted when the field was added.
+	Old any `json:"old,omitempty"`
+	// New is the current value, omitted when the field was removed.
+	New any `json:"new,omitempty"`
+}
+
+// Diff returns the fields that differ between oldObj and newObj, sorted by path. Paths are
+// prefixed with root. Values are redacted by redactor, or by redact.Default when it is nil, and
+// the values of environment variables are always redacted.
+func Diff(root string, oldObj, newObj any, redactor *redact.Redactor) ([]Change, error) {
+	if redactor == nil {
+		redactor = redact.Default()
+	}
+
+	oldFields, err := flatten(root, oldObj)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flatten(root, newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for path, oldValue := range oldFields {
+		newValue, found := newFields[path]
+		if found && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		change := Change{Path: path, Old: oldValue}
+		if found {
+			change.New = newValue
+		}
+		changes = append(changes, redactChange(redactor, change))
+	}
+	for path, newValue := range newFields {
+		if _, found := oldFields[path]; !found {
+			changes = append(changes, redactChange(redactor, Change{Path: path, New: newValue}))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// Paths returns the paths of the changes.
+func Paths(changes []Change) []string {
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return paths
+}
+
+// Summarize renders at most limit changed paths as a single line.
+func Summarize(changes []Change, limit int) string {
+	paths := Paths(changes)
+	if len(paths) <= limit {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:limit], ", "), len(paths)-limit)
+}
+
+// flatten converts obj into a map from field path to scalar value through its JSON form.
+func flatten(root string, obj any) (map[string]any, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object for diff: %w", err)
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal object for diff: %w", err)
+	}
+
+	fields := make(map[string]any)
+	flattenValue(root, generic, fields)
+	return fields, nil
+}
+
+func flattenValue(path string, value any, fields map[string]any) {
+	switch typed := value.(type) {
+	case map[string]any:
+		if len(typed) == 0 {
+			return
+		}
+		for key, child := range typed {
+			flattenValue(joinPath(path, key), child, fields)
+		}
+	case []any:
+		if len(typed) == 0 {
+			return
+		}
+		for i, child := range typed {
+			flattenValue(path+"["+strconv.Itoa(i)+"]", child, fields)
+		}
+	case nil:
+		return
+	default:
+		fields[path] = typed
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// redactChange hides the values of a change that may carry credentials. Values are redacted
+// under the name of the changed field, or of the list holding it.
+func redactChange(redactor *redact.Redactor, change Change) Change {
+	if envValuePattern.MatchString(change.Path) {
+		if change.Old != nil {
+			change.Old = redact.RedactedValue
+		}
+		if change.New != nil {
+			change.New = redact.RedactedValue
+		}
+		return change
+	}
+
+	key := change.Path
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	if i := strings.Index(key, "["); i >= 0 {
+		key = key[:i]
+	}
+	if change.Old != nil {
+		change.Old = redactor.Field(key, change.Old)
+	}
+	if change.New != nil {
+		change.New = redactor.Field(key, change.New)
+	}
+	return change
+}
@@ -1,67 +1,143 @@
 package compare
 
 import (
-	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 
-	"github.com/go-logr/logr"
-	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
-	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// HasUnexpectedServiceChanges checks for any unexpected mutations between two Service objects.
-// It returns true and a diff string if a change is found in any field that is not
-// explicitly managed by the operator or the cluster.
-func HasUnexpectedServiceChanges(desired, current *corev1.Service) (bool, string) {
-	// Ignore fields that we are intentionally managing and expect to be different.
-	managedSpecFields := cmpopts.IgnoreFields(corev1.ServiceSpec{}, "Ports", "Selector")
-
-	// Ignore metadata fields that are managed by the Kubernetes API server.
-	// Comparing these would cause unnecessary diffs on every update.
-	clusterManagedFields := cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion", "UID", "CreationTimestamp", "Generation", "ManagedFields")
-
-	// Ignore the status field, as it is managed by the Kubernetes service controller,
-	// not by our operator.
-	ignoreStatus := cmpopts.IgnoreFields(corev1.Service{}, "Status")
+// clusterManagedMetadata are the metadata fields set by the API server or other controllers.
+// Comparing them would report drift on every update.
+var clusterManagedMetadata = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"finalizers",
+	"generation",
+	"managedFields",
+	"ownerReferences",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
 
-	diff := cmp.Diff(current, desired, managedSpecFields, clusterManagedFields, ignoreStatus)
-	if diff != "" {
-		return true, diff
+// DetectDrift returns the sorted paths of the fields of the live object that differ from the
+// desired state, e.g. spec.template.spec.containers[0].image. Only the fields set in the desired
+// state are compared, so the defaults and the fields added by the API server or other controllers
+// are not drift. The status and the cluster-managed metadata are ignored.
+func DetectDrift(desired, live *unstructured.Unstructured) []string {
+	liveContent := live.UnstructuredContent()
+	var paths []string
+	for key, value := range desired.UnstructuredContent() {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			desiredMeta, _ := value.(map[string]any)
+			liveMeta, _ := liveContent[key].(map[string]any)
+			for metaKey, metaValue := range desiredMeta {
+				if !slices.Contains(clusterManagedMetadata, metaKey) {
+					paths = diffValue(joinPath(key, metaKey), metaValue, liveMeta[metaKey], paths)
+				}
+			}
+		default:
+			paths = diffValue(key, value, liveContent[key], paths)
+		}
 	}
+	slices.Sort(paths)
+	return paths
+}
 
-	return false, ""
+// diffValue appends the paths under path where live differs from desired. Empty desired values
+// are not enforced. Lists are compared by index and must have the same length.
+func diffValue(path string, desired, live any, paths []string) []string {
+	if isEmpty(desired) {
+		return paths
+	}
+	switch desiredValue := desired.(type) {
+	case map[string]any:
+		liveValue, ok := live.(map[string]any)
+		if !ok {
+			return append(paths, path)
+		}
+		for key, value := range desiredValue {
+			paths = diffValue(joinPath(path, key), value, liveValue[key], paths)
+		}
+		return paths
+	case []any:
+		liveValue, ok := live.([]any)
+		if !ok || len(liveValue) != len(desiredValue) {
+			return append(paths, path)
+		}
+		for i := range desiredValue {
+			paths = diffValue(fmt.Sprintf("%s[%d]", path, i), desiredValue[i], liveValue[i], paths)
+		}
+		return paths
+	}
+	if !equalScalar(desired, live) {
+		return append(paths, path)
+	}
+	return paths
 }
 
-// CheckAndLogServiceChanges encapsulates the full logic to fetch the current service,
-// compare it against the desired state, and log any unexpected changes.
-func CheckAndLogServiceChanges(ctx context.Context, c client.Client, desired *unstructured.Unstructured) error {
-	logger := logr.FromContextOrDiscard(ctx)
-	key := client.ObjectKeyFromObject(desired)
-	foundService := &corev1.Service{}
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
 
-	err := c.Get(ctx, key, foundService)
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil
-		}
-		return fmt.Errorf("failed to fetch existing service for comparison: %w", err)
+// equalScalar compares numbers by value, as decoding may produce int64 or float64 for the same number.
+func equalScalar(desired, live any) bool {
+	if desiredNumber, ok := toFloat(desired); ok {
+		liveNumber, ok := toFloat(live)
+		return ok && desiredNumber == liveNumber
 	}
+	return desired == live
+}
 
-	desiredService := &corev1.Service{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(desired.UnstructuredContent(), desiredService); err != nil {
-		return fmt.Errorf("failed to convert desired unstructured object to Service: %w", err)
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
 	}
+	return 0, false
+}
 
-	changed, unexpectedChanges := HasUnexpectedServiceChanges(desiredService, foundService)
-	if changed {
-		logger.Info("Ignoring unexpected changes in Service manifest", "changes", unexpectedChanges)
+// joinPath appends a key to a path, quoting keys such as label names that contain dots or slashes.
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, `./[]"`) {
+		return fmt.Sprintf("%s[%q]", path, key)
 	}
+	return path + "." + key
+}
 
-	return nil
+// DesiredStateHash returns a stable hash of the desired state of an object. Recorded on the applied
+// object, it tells changes of the desired state apart from changes made to the live object.
+func DesiredStateHash(desired *unstructured.Unstructured) (string, error) {
+	// encoding/json sorts map keys, so the output is deterministic.
+	data, err := json.Marshal(desired.UnstructuredContent())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal desired state: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
 }
@@ -5,20 +5,24 @@ import (
 
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/compare"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // baseService is a helper to create a consistent Service object for tests.
 func baseService() *corev1.Service {
 	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            "test-service",
-			Namespace:       "default",
-			ResourceVersion: "1",
+			Name:      "test-service",
+			Namespace: "default",
 			Labels: map[string]string{
-				"app": "my-app",
+				"app":                          "my-app",
+				"app.kubernetes.io/managed-by": "llama-stack-operator",
 			},
 		},
 		Spec: corev1.ServiceSpec{
@@ -32,100 +36,115 @@ func baseService() *corev1.Service {
 			Selector: map[string]string{
 				"app": "my-app",
 			},
-			Type:      corev1.ServiceTypeClusterIP,
-			ClusterIP: "10.0.0.1",
-		},
-		Status: corev1.ServiceStatus{
-			LoadBalancer: corev1.LoadBalancerStatus{
-				Ingress: []corev1.LoadBalancerIngress{
-					{IP: "192.168.1.100"},
-				},
-			},
+			Type: corev1.ServiceTypeClusterIP,
 		},
 	}
 }
 
-func TestHasUnexpectedServiceChanges(t *testing.T) {
+func toUnstructured(t *testing.T, obj runtime.Object) *unstructured.Unstructured {
+	t.Helper()
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: content}
+}
+
+func TestDetectDrift(t *testing.T) {
 	testCases := []struct {
-		name         string
-		modifier     func(s *corev1.Service) // A function to modify the base service
-		expectChange bool
+		name          string
+		modifier      func(s *corev1.Service) // A function to modify the live service
+		expectedPaths []string
 	}{
 		{
-			name:         "no changes detected",
-			modifier:     func(s *corev1.Service) {}, // No changes
-			expectChange: false,
-		},
-		{
-			name: "only managed port changed",
-			modifier: func(s *corev1.Service) {
-				s.Spec.Ports[0].Port = 8081
-			},
-			expectChange: false,
+			name:     "no changes detected",
+			modifier: func(s *corev1.Service) {},
 		},
 		{
-			name: "only managed selector changed",
+			name: "cluster-managed metadata and status changed",
 			modifier: func(s *corev1.Service) {
-				s.Spec.Selector["version"] = "v2"
+				s.ResourceVersion = "2"
+				s.UID = "uid"
+				s.Generation = 3
+				s.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.168.1.100"}}
 			},
-			expectChange: false,
 		},
 		{
-			name: "only cluster-managed metadata changed",
+			name: "fields missing from the desired state are set",
 			modifier: func(s *corev1.Service) {
-				s.ResourceVersion = "2"
+				s.Spec.ClusterIP = "10.0.0.1"
+				s.Spec.SessionAffinity = corev1.ServiceAffinityNone
+				s.Spec.Ports[0].Protocol = corev1.ProtocolTCP
+				s.Labels["team"] = "ai"
+				s.Annotations = map[string]string{"note": "added"}
 			},
-			expectChange: false,
 		},
 		{
-			name: "only status changed",
+			name: "port changed",
 			modifier: func(s *corev1.Service) {
-				s.Status.LoadBalancer.Ingress[0].IP = "192.168.1.101"
+				s.Spec.Ports[0].Port = 8081
 			},
-			expectChange: false,
+			expectedPaths: []string{"spec.ports[0].port"},
 		},
 		{
-			name: "unexpected immutable field changed - ClusterIP",
+			name: "port added",
 			modifier: func(s *corev1.Service) {
-				s.Spec.ClusterIP = "10.0.0.2"
+				s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{Name: "debug", Port: 5678})
 			},
-			expectChange: true,
+			expectedPaths: []string{"spec.ports"},
 		},
 		{
-			name: "unexpected field changed - ServiceType",
+			name: "type and selector changed",
 			modifier: func(s *corev1.Service) {
 				s.Spec.Type = corev1.ServiceTypeNodePort
+				s.Spec.Selector["app"] = "other"
 			},
-			expectChange: true,
+			expectedPaths: []string{"spec.selector.app", "spec.type"},
 		},
 		{
-			name: "combination of managed and unexpected changes",
+			name: "label removed",
 			modifier: func(s *corev1.Service) {
-				s.Spec.Ports[0].Port = 9000    // Managed change
-				s.Spec.ClusterIP = "10.0.0.99" // Unexpected change
+				delete(s.Labels, "app.kubernetes.io/managed-by")
 			},
-			expectChange: true,
+			expectedPaths: []string{`metadata.labels["app.kubernetes.io/managed-by"]`},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			// Arrange
-			current := baseService()
 			desired := baseService()
-			tc.modifier(desired)
-
-			// Act
-			hasChanged, diff := compare.HasUnexpectedServiceChanges(desired, current)
+			live := baseService()
+			tc.modifier(live)
 
-			// Assert
-			assert.Equal(t, tc.expectChange, hasChanged)
+			paths := compare.DetectDrift(toUnstructured(t, desired), toUnstructured(t, live))
 
-			if tc.expectChange {
-				assert.NotEmpty(t, diff, "expected a diff for an unexpected change, but it was empty")
-			} else {
-				assert.Empty(t, diff, "expected no diff, but changes were detected")
-			}
+			assert.Equal(t, tc.expectedPaths, paths)
 		})
 	}
+
+	t.Run("numbers are compared by value", func(t *testing.T) {
+		desired := toUnstructured(t, baseService())
+		live := desired.DeepCopy()
+		ports, _, err := unstructured.NestedSlice(live.Object, "spec", "ports")
+		require.NoError(t, err)
+		port, ok := ports[0].(map[string]any)
+		require.True(t, ok)
+		port["port"] = float64(80)
+		port["targetPort"] = float64(8080)
+		require.NoError(t, unstructured.SetNestedSlice(live.Object, ports, "spec", "ports"))
+
+		assert.Empty(t, compare.DetectDrift(desired, live))
+	})
+}
+
+func TestDesiredStateHash(t *testing.T) {
+	first, err := compare.DesiredStateHash(toUnstructured(t, baseService()))
+	require.NoError(t, err)
+	second, err := compare.DesiredStateHash(toUnstructured(t, baseService()))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	changed := baseService()
+	changed.Spec.Ports[0].Port = 8081
+	third, err := compare.DesiredStateHash(toUnstructured(t, changed))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, third)
 }
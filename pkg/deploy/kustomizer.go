@@ -11,13 +11,16 @@ import (
 	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/compare"
 	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy/plugins"
+	corev1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -28,6 +31,59 @@ import (
 	yamlpkg "sigs.k8s.io/yaml"
 )
 
+const (
+	// UserConfigHashAnnotation is the pod template annotation holding the hash of the user ConfigMap.
+	UserConfigHashAnnotation = "configmap.hash/user-config"
+	// CABundleHashAnnotation is the pod template annotation holding the hash of the CA bundle ConfigMap.
+	CABundleHashAnnotation = "configmap.hash/ca-bundle"
+	// EventReasonResourceNotOwned is the Event reason used when an existing resource is left
+	// untouched because it is not owned by the instance.
+	EventReasonResourceNotOwned = "ResourceNotOwned"
+	// EventReasonResourceAdopted is the Event reason used when an existing resource is adopted by the instance.
+	EventReasonResourceAdopted = "ResourceAdopted"
+	// AppliedHashAnnotation records the hash of the desired state last applied to a resource.
+	AppliedHashAnnotation = "llamastack.io/applied-hash"
+)
+
+// ResourceDrift reports the fields of an applied resource that were changed outside the operator.
+type ResourceDrift struct {
+	Kind string
+	Name string
+	// Paths are the drifted fields, e.g. spec.template.spec.containers[0].image.
+	Paths []string
+	// Corrected is true when the desired state was applied again.
+	Corrected bool
+}
+
+// SkippedResource identifies an existing resource left untouched because the instance does not own it.
+type SkippedResource struct {
+	Kind string
+	Name string
+	// Controller is the kind and name of the controller of the resource, empty when it has none.
+	Controller string
+}
+
+// PatchedResource is an existing resource patched with its desired state.
+type PatchedResource struct {
+	Kind string
+	Name string
+	// Previous is the live state of the resource before the patch.
+	Previous *unstructured.Unstructured
+}
+
+// ApplyOptions control how existing resources are handled by ApplyResourcesWithOptions.
+type ApplyOptions struct {
+	DriftPolicy    llamav1alpha1.DriftPolicy
+	AdoptionPolicy llamav1alpha1.AdoptionPolicy
+}
+
+// ApplyResult reports the existing resources that were drifted, skipped or patched.
+type ApplyResult struct {
+	Drifts  []ResourceDrift
+	Skipped []SkippedResource
+	Patched []PatchedResource
+}
+
 // RenderManifest takes a manifest directory and transforms it through
 // kustomization and plugins to produce final Kubernetes resources.
 func RenderManifest(
@@ -54,30 +110,56 @@ func RenderManifest(
 	return &resMapVal, nil
 }
 
-// ApplyResources takes a Kustomize ResMap and applies the resources to the cluster.
+// ApplyResources takes a Kustomize ResMap and applies the resources to the cluster,
+// correcting any drift.
 func ApplyResources(
 	ctx context.Context,
 	cli client.Client,
 	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 	ownerInstance *llamav1alpha1.LlamaStackDistribution,
 	resMap *resmap.ResMap,
 ) error {
+	_, err := ApplyResourcesWithOptions(ctx, cli, scheme, recorder, ownerInstance, resMap, ApplyOptions{
+		DriftPolicy:    llamav1alpha1.DriftPolicyCorrect,
+		AdoptionPolicy: llamav1alpha1.AdoptionPolicyAnnotated,
+	})
+	return err
+}
+
+// ApplyResourcesWithOptions applies the resources like ApplyResources and reports the existing
+// resources changed outside the operator since they were last applied, and those skipped because
+// the instance does not own them. With the Report drift policy, the drifted resources are left as
+// they are until their desired state changes.
+func ApplyResourcesWithOptions(
+	ctx context.Context,
+	cli client.Client,
+	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
+	ownerInstance *llamav1alpha1.LlamaStackDistribution,
+	resMap *resmap.ResMap,
+	opts ApplyOptions,
+) (*ApplyResult, error) {
+	result := &ApplyResult{}
 	for _, res := range (*resMap).Resources() {
-		if err := manageResource(ctx, cli, scheme, res, ownerInstance); err != nil {
-			return fmt.Errorf("failed to manage resource %s/%s: %w", res.GetKind(), res.GetName(), err)
+		if err := manageResource(ctx, cli, scheme, recorder, res, ownerInstance, opts, result); err != nil {
+			return nil, fmt.Errorf("failed to manage resource %s/%s: %w", res.GetKind(), res.GetName(), err)
 		}
 	}
-	return nil
+	return result, nil
 }
 
 // manageResource acts as a dispatcher, checking if a resource exists and then
-// deciding whether to create it or patch it.
+// deciding whether to create it or patch it. Drifted and skipped resources are added to result.
 func manageResource(
 	ctx context.Context,
 	cli client.Client,
 	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 	res *resource.Resource,
 	ownerInstance *llamav1alpha1.LlamaStackDistribution,
+	opts ApplyOptions,
+	result *ApplyResult,
 ) error {
 	// prevent the controller from trying to apply changes to its own CR
 	if res.GetKind() == llamav1alpha1.LlamaStackDistributionKind && res.GetName() == ownerInstance.Name && res.GetNamespace() == ownerInstance.Namespace {
@@ -100,6 +182,10 @@ func manageResource(
 		}
 	}
 
+	if err := setAppliedHash(u); err != nil {
+		return err
+	}
+
 	kGvk := res.GetGvk()
 	gvk := schema.GroupVersionKind{
 		Group:   kGvk.Group,
@@ -115,7 +201,36 @@ func manageResource(
 		}
 		return createResource(ctx, cli, u, ownerInstance, scheme, gvk)
 	}
-	return patchResource(ctx, cli, u, found, ownerInstance)
+	return patchResource(ctx, cli, scheme, recorder, u, found, ownerInstance, opts, result)
+}
+
+// setAppliedHash records the hash of the desired state in the AppliedHashAnnotation.
+func setAppliedHash(desired *unstructured.Unstructured) error {
+	hash, err := compare.DesiredStateHash(desired)
+	if err != nil {
+		return err
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AppliedHashAnnotation] = hash
+	desired.SetAnnotations(annotations)
+	return nil
+}
+
+// detectDrift returns the fields of the existing resource that were changed since its desired state
+// was applied, or nil. When the desired state changed since, the resource is not drifted: the new
+// desired state is applied.
+func detectDrift(desired, existing *unstructured.Unstructured) *ResourceDrift {
+	if existing.GetAnnotations()[AppliedHashAnnotation] != desired.GetAnnotations()[AppliedHashAnnotation] {
+		return nil
+	}
+	paths := compare.DetectDrift(desired, existing)
+	if len(paths) == 0 {
+		return nil
+	}
+	return &ResourceDrift{Kind: existing.GetKind(), Name: existing.GetName(), Paths: paths}
 }
 
 // createResource creates a new resource, setting an owner reference only if it's namespace-scoped.
@@ -150,8 +265,18 @@ func isClusterScoped(mapper meta.RESTMapper, gvk schema.GroupVersionKind) (bool,
 	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
 }
 
-// patchResource patches an existing resource, but only if we own it.
-func patchResource(ctx context.Context, cli client.Client, desired, existing *unstructured.Unstructured, ownerInstance *llamav1alpha1.LlamaStackDistribution) error {
+// patchResource patches an existing resource, but only if we own it or can adopt it. Drifted
+// resources are left as they are with the Report policy.
+func patchResource(
+	ctx context.Context,
+	cli client.Client,
+	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
+	desired, existing *unstructured.Unstructured,
+	ownerInstance *llamav1alpha1.LlamaStackDistribution,
+	opts ApplyOptions,
+	result *ApplyResult,
+) error {
 	logger := log.FromContext(ctx)
 
 	// Critical safety check to prevent the operator from "stealing" or
@@ -164,11 +289,22 @@ func patchResource(ctx context.Context, cli client.Client, desired, existing *un
 		}
 	}
 	if !isOwner {
-		logger.Info("Skipping resource not owned by this instance",
-			"kind", existing.GetKind(),
-			"name", existing.GetName(),
-			"namespace", existing.GetNamespace())
-		return nil
+		adopted, err := adoptResource(ctx, cli, scheme, existing, ownerInstance, opts.AdoptionPolicy)
+		if err != nil {
+			return err
+		}
+		if !adopted {
+			skipped := SkippedResource{Kind: existing.GetKind(), Name: existing.GetName()}
+			if controller := metav1.GetControllerOfNoCopy(existing); controller != nil {
+				skipped.Controller = controller.Kind + "/" + controller.Name
+			}
+			result.Skipped = append(result.Skipped, skipped)
+			recorder.Eventf(ownerInstance, corev1.EventTypeWarning, EventReasonResourceNotOwned,
+				"Skipped %s %s: the existing resource is not owned by this instance", existing.GetKind(), existing.GetName())
+			return nil
+		}
+		recorder.Eventf(ownerInstance, corev1.EventTypeNormal, EventReasonResourceAdopted,
+			"Adopted existing %s %s", existing.GetKind(), existing.GetName())
 	}
 
 	if existing.GetKind() == "PersistentVolumeClaim" {
@@ -176,10 +312,21 @@ func patchResource(ctx context.Context, cli client.Client, desired, existing *un
 			"name", existing.GetName(),
 			"namespace", existing.GetNamespace())
 		return nil
-	} else if existing.GetKind() == "Service" {
-		if err := compare.CheckAndLogServiceChanges(ctx, cli, desired); err != nil {
-			return fmt.Errorf("failed to validate resource mutations while patching: %w", err)
+	}
+
+	drift := detectDrift(desired, existing)
+	if drift != nil {
+		logger.Info("Detected changes made outside the operator",
+			"kind", existing.GetKind(),
+			"name", existing.GetName(),
+			"paths", drift.Paths,
+			"policy", opts.DriftPolicy)
+		if opts.DriftPolicy == llamav1alpha1.DriftPolicyReport {
+			result.Drifts = append(result.Drifts, *drift)
+			return nil
 		}
+		drift.Corrected = true
+		result.Drifts = append(result.Drifts, *drift)
 	}
 
 	data, err := json.Marshal(desired)
@@ -187,13 +334,55 @@ func patchResource(ctx context.Context, cli client.Client, desired, existing *un
 		return fmt.Errorf("failed to marshal desired state: %w", err)
 	}
 
-	return cli.Patch(
+	previous := existing.DeepCopy()
+	if err := cli.Patch(
 		ctx,
 		existing,
 		client.RawPatch(k8stypes.ApplyPatchType, data),
 		client.ForceOwnership,
 		client.FieldOwner(ownerInstance.GetName()),
-	)
+	); err != nil {
+		return fmt.Errorf("failed to patch %s %s: %w", existing.GetKind(), existing.GetName(), err)
+	}
+	result.Patched = append(result.Patched, PatchedResource{Kind: previous.GetKind(), Name: previous.GetName(), Previous: previous})
+	return nil
+}
+
+// adoptResource makes the instance the controller of an existing resource it does not own, if the
+// adoption policy allows it. Resources controlled by another owner and cluster-scoped resources are
+// never adopted. The owner reference is added by a merge patch rather than by the apply patch, so
+// later apply patches, which do not set it, keep it.
+func adoptResource(
+	ctx context.Context,
+	cli client.Client,
+	scheme *runtime.Scheme,
+	existing *unstructured.Unstructured,
+	ownerInstance *llamav1alpha1.LlamaStackDistribution,
+	policy llamav1alpha1.AdoptionPolicy,
+) (bool, error) {
+	if metav1.GetControllerOfNoCopy(existing) != nil {
+		return false, nil
+	}
+	if policy != llamav1alpha1.AdoptionPolicyUnowned && existing.GetAnnotations()[llamav1alpha1.AdoptAnnotation] != llamav1alpha1.AdoptValue {
+		return false, nil
+	}
+	clusterScoped, err := isClusterScoped(cli.RESTMapper(), existing.GroupVersionKind())
+	if err != nil {
+		return false, fmt.Errorf("failed to determine resource scope: %w", err)
+	}
+	if clusterScoped {
+		return false, nil
+	}
+
+	patch := client.MergeFromWithOptions(existing.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if err := ctrl.SetControllerReference(ownerInstance, existing, scheme); err != nil {
+		return false, fmt.Errorf("failed to set controller reference for %s: %w", existing.GetKind(), err)
+	}
+	if err := cli.Patch(ctx, existing, patch); err != nil {
+		return false, fmt.Errorf("failed to adopt %s %s: %w", existing.GetKind(), existing.GetName(), err)
+	}
+	log.FromContext(ctx).Info("Adopted existing resource", "kind", existing.GetKind(), "name", existing.GetName())
+	return true, nil
 }
 
 // applyPlugins runs all Go-based transformations on the resource map.
@@ -222,6 +411,10 @@ func applyPlugins(resMap *resmap.ResMap, ownerInstance *llamav1alpha1.LlamaStack
 		return fmt.Errorf("failed to apply field transformer: %w", err)
 	}
 
+	if err := applyNetworkRules(*resMap, ownerInstance); err != nil {
+		return fmt.Errorf("failed to apply network rules: %w", err)
+	}
+
 	return nil
 }
 
@@ -235,7 +428,8 @@ func getFieldMappings(ownerInstance *llamav1alpha1.LlamaStackDistribution) []plu
 	operatorNS := getOperatorNamespace()
 	instanceLabelPath := "/app.kubernetes.io~1instance"
 
-	return buildFieldMappings(instanceName, instanceNamespace, serviceAccountName, servicePort, storageSize, operatorNS, instanceLabelPath, ownerInstance.Spec.Replicas)
+	mappings := buildFieldMappings(instanceName, instanceNamespace, serviceAccountName, servicePort, storageSize, operatorNS, instanceLabelPath, GetDesiredReplicas(ownerInstance))
+	return append(mappings, getMonitoringFieldMappings(ownerInstance)...)
 }
 
 // buildFieldMappings constructs the field mappings array.
@@ -269,6 +463,12 @@ func buildFieldMappings(instanceName, instanceNamespace, serviceAccountName stri
 			TargetKind:        "Service",
 			CreateIfNotExists: true,
 		},
+		{
+			SourceValue:       instanceName,
+			TargetField:       "/metadata/labels" + instanceLabelPath,
+			TargetKind:        "Service",
+			CreateIfNotExists: true,
+		},
 		{
 			SourceValue:       instanceName,
 			TargetField:       "/metadata/name",
@@ -375,9 +575,12 @@ type ManifestContext struct {
 	CABundleHash  string
 	ContainerSpec map[string]any
 	PodSpec       map[string]any
+	// PodLabels are added to the pod template. Labels already set by the manifests are kept.
+	PodLabels map[string]string
 }
 
-// RenderManifestWithContext renders manifests and enhances the Deployment with complex specs.
+// RenderManifestWithContext renders manifests, enhances the Deployment with complex specs and
+// applies the override patches of the instance.
 func RenderManifestWithContext(
 	fs filesys.FileSystem,
 	manifestsPath string,
@@ -390,20 +593,22 @@ func RenderManifestWithContext(
 		return nil, fmt.Errorf("failed to render base manifests: %w", err)
 	}
 
-	// If no manifest context provided, return base manifests
-	if manifestCtx == nil {
-		return resMap, nil
-	}
+	// Update the Deployment with the manifest context, if provided
+	if manifestCtx != nil {
+		for _, res := range (*resMap).Resources() {
+			if res.GetKind() != "Deployment" {
+				continue
+			}
 
-	// Update the Deployment with the manifest context
-	for _, res := range (*resMap).Resources() {
-		if res.GetKind() != "Deployment" {
-			continue
+			if err := updateDeploymentSpec(res, manifestCtx); err != nil {
+				return nil, fmt.Errorf("failed to update Deployment: %w", err)
+			}
 		}
+	}
 
-		if err := updateDeploymentSpec(res, manifestCtx); err != nil {
-			return nil, fmt.Errorf("failed to update Deployment: %w", err)
-		}
+	// Apply the user patches last, so they can override anything rendered by the operator
+	if err := applyOverridePatches(*resMap, ownerInstance); err != nil {
+		return nil, err
 	}
 
 	return resMap, nil
@@ -435,6 +640,9 @@ func updateDeploymentSpec(res *resource.Resource, manifestCtx *ManifestContext)
 		return err
 	}
 
+	// Add the user pod labels without overriding the selector labels
+	addPodLabels(data, manifestCtx.PodLabels)
+
 	// Update the resource with the manifest context
 	return updateResourceFromData(res, data)
 }
@@ -499,15 +707,42 @@ func addConfigMapAnnotations(data map[string]any, manifestCtx *ManifestContext)
 	}
 
 	if manifestCtx.ConfigMapHash != "" {
-		annotations["configmap.hash/user-config"] = manifestCtx.ConfigMapHash
+		annotations[UserConfigHashAnnotation] = manifestCtx.ConfigMapHash
 	}
 	if manifestCtx.CABundleHash != "" {
-		annotations["configmap.hash/ca-bundle"] = manifestCtx.CABundleHash
+		annotations[CABundleHashAnnotation] = manifestCtx.CABundleHash
 	}
 
 	return nil
 }
 
+// addPodLabels adds labels to the Deployment pod template. Existing labels take precedence.
+func addPodLabels(data map[string]any, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	spec, _ := data["spec"].(map[string]any)
+	template, _ := spec["template"].(map[string]any)
+	if template == nil {
+		return
+	}
+	templateMeta, ok := template["metadata"].(map[string]any)
+	if !ok {
+		templateMeta = make(map[string]any)
+		template["metadata"] = templateMeta
+	}
+	podLabels, ok := templateMeta["labels"].(map[string]any)
+	if !ok {
+		podLabels = make(map[string]any)
+		templateMeta["labels"] = podLabels
+	}
+	for key, value := range labels {
+		if _, exists := podLabels[key]; !exists {
+			podLabels[key] = value
+		}
+	}
+}
+
 // updateResourceFromData updates the resource with the modified data.
 func updateResourceFromData(res *resource.Resource, data map[string]any) error {
 	updatedJSON, err := json.Marshal(data)
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,9 +20,12 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
 )
 
 const manifestBasePath = "manifests/base"
@@ -162,6 +166,80 @@ metadata:
 		assert.Equal(t, "test-fallback-ns", res.GetNamespace(), "Deployment should have the correct namespace set by plugin")
 	})
 
+	t.Run("should merge the network rules into the NetworkPolicy", func(t *testing.T) {
+		fsys := filesys.MakeFsInMemory()
+		require.NoError(t, fsys.MkdirAll(manifestBasePath))
+		require.NoError(t, fsys.WriteFile(filepath.Join(manifestBasePath, "kustomization.yaml"), []byte(`
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - networkpolicy.yaml
+`)))
+		require.NoError(t, fsys.WriteFile(filepath.Join(manifestBasePath, "networkpolicy.yaml"), []byte(`
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: network-policy
+spec:
+  podSelector:
+    matchLabels:
+      app: llama-stack
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          app.kubernetes.io/part-of: llama-stack
+    ports:
+    - protocol: TCP
+      port: 8321
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: ""
+    ports:
+    - protocol: TCP
+      port: 8321
+`)))
+
+		backendPort := intstr.FromInt32(8000)
+		owner := &llamav1alpha1.LlamaStackDistribution{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-instance", Namespace: "test-network-ns"},
+			Spec: llamav1alpha1.LlamaStackDistributionSpec{
+				Network: &llamav1alpha1.NetworkSpec{
+					Ingress: []networkingv1.NetworkPolicyIngressRule{{
+						From: []networkingv1.NetworkPolicyPeer{{
+							PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "chat"}},
+						}},
+					}},
+					Egress: []networkingv1.NetworkPolicyEgressRule{{
+						To: []networkingv1.NetworkPolicyPeer{{
+							PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vllm"}},
+						}},
+						Ports: []networkingv1.NetworkPolicyPort{{Port: &backendPort}},
+					}},
+				},
+			},
+		}
+
+		resMap, err := RenderManifest(fsys, manifestBasePath, owner)
+		require.NoError(t, err)
+		require.Equal(t, 1, (*resMap).Size())
+
+		policy := &networkingv1.NetworkPolicy{}
+		require.NoError(t, yaml.Unmarshal([]byte((*resMap).Resources()[0].MustYaml()), policy))
+		assert.Equal(t, "test-instance-network-policy", policy.Name)
+		assert.Equal(t, "test-network-ns", policy.Namespace)
+		assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+		require.Len(t, policy.Spec.Ingress, 3)
+		assert.Equal(t, "llama-stack", policy.Spec.Ingress[0].From[0].PodSelector.MatchLabels["app.kubernetes.io/part-of"])
+		assert.Equal(t, owner.Spec.Network.Ingress[0], policy.Spec.Ingress[2])
+		require.Len(t, policy.Spec.Egress, 2)
+		assert.Equal(t, dnsEgressRule(), policy.Spec.Egress[0])
+		assert.Equal(t, owner.Spec.Network.Egress[0], policy.Spec.Egress[1])
+	})
+
 	t.Run("should return an error if a resource file is missing", func(t *testing.T) {
 		// given a kustomization.yaml that references a file that does not exist
 		fsys := filesys.MakeFsInMemory()
@@ -226,7 +304,7 @@ func TestApplyResources(t *testing.T) {
 		require.NoError(t, resMap.Append(desiredSvc))
 
 		// when
-		require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, owner, &resMap)) // Pass address of resMap
+		require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, &resMap)) // Pass address of resMap
 
 		// then
 		// verify deployment created correctly
@@ -286,7 +364,7 @@ func TestApplyResources(t *testing.T) {
 		require.NoError(t, resMap.Append(ownerResrc))
 
 		// when
-		require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, owner, &resMap))
+		require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, &resMap))
 
 		// then
 		// verify deployment created correctly
@@ -372,9 +450,15 @@ func TestApplyResources(t *testing.T) {
 		require.NoError(t, resMap.Append(ownerOtherResrc))
 
 		// when
-		err := ApplyResources(ctx, k8sClient, scheme.Scheme, owner, &resMap)
+		recorder := record.NewFakeRecorder(10)
+		err := ApplyResources(ctx, k8sClient, scheme.Scheme, recorder, owner, &resMap)
 		require.NoError(t, err, "should not error when encountering resources owned by other instances")
 
+		// then verify the skipped service and the other instance were reported
+		require.Len(t, recorder.Events, 2, "skipping resources not owned by this instance should emit events")
+		require.Contains(t, <-recorder.Events, EventReasonResourceNotOwned+" Skipped Service my-service")
+		require.Contains(t, <-recorder.Events, EventReasonResourceNotOwned+" Skipped LlamaStackDistribution test-owner-other")
+
 		// then verify the existing service was not modified (still owned by the other instance)
 		unchangedService := &corev1.Service{}
 		serviceKey := types.NamespacedName{Name: "my-service", Namespace: testNs}
@@ -406,7 +490,7 @@ func TestApplyResources(t *testing.T) {
 		require.NoError(t, resMap.Append(desiredClusterRole))
 
 		// when we apply the resources
-		require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, owner, &resMap))
+		require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, &resMap))
 
 		// then verify the cluster role was created correctly
 		createdClusterRole := &rbacv1.ClusterRole{}
@@ -422,6 +506,126 @@ func TestApplyResources(t *testing.T) {
 	})
 }
 
+// TestApplyResources_Drift verifies that changes made outside the operator are reported, and only
+// reverted with the Correct policy.
+func TestApplyResources_Drift(t *testing.T) {
+	ctx, testNs, owner := setupApplyResourcesTest(t, "drift")
+	svcSpec := map[string]any{"ports": []any{map[string]any{"name": "web", "port": 80}}}
+	desiredSvc := func(tier string) *resmap.ResMap {
+		svc := newTestResource(t, "v1", "Service", "my-service", testNs, svcSpec)
+		svc.SetLabels(map[string]string{"tier": tier})
+		resMap := resmap.New()
+		require.NoError(t, resMap.Append(svc))
+		return &resMap
+	}
+	svcKey := types.NamespacedName{Name: "my-service", Namespace: testNs}
+	setTier := func(tier string) {
+		svc := &corev1.Service{}
+		require.NoError(t, k8sClient.Get(ctx, svcKey, svc))
+		svc.Labels["tier"] = tier
+		require.NoError(t, k8sClient.Update(ctx, svc))
+	}
+	currentTier := func() string {
+		svc := &corev1.Service{}
+		require.NoError(t, k8sClient.Get(ctx, svcKey, svc))
+		return svc.Labels["tier"]
+	}
+
+	result, err := ApplyResourcesWithOptions(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, desiredSvc("api"), ApplyOptions{DriftPolicy: llamav1alpha1.DriftPolicyReport})
+	require.NoError(t, err)
+	require.Empty(t, result.Drifts, "created resources should not be drifted")
+
+	t.Run("reports drift without reverting it", func(t *testing.T) {
+		setTier("edited")
+
+		result, err := ApplyResourcesWithOptions(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, desiredSvc("api"), ApplyOptions{DriftPolicy: llamav1alpha1.DriftPolicyReport})
+		require.NoError(t, err)
+
+		require.Equal(t, []ResourceDrift{{Kind: "Service", Name: "my-service", Paths: []string{"metadata.labels.tier"}}}, result.Drifts)
+		require.Equal(t, "edited", currentTier())
+	})
+
+	t.Run("reverts drift with the Correct policy", func(t *testing.T) {
+		result, err := ApplyResourcesWithOptions(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, desiredSvc("api"), ApplyOptions{DriftPolicy: llamav1alpha1.DriftPolicyCorrect})
+		require.NoError(t, err)
+
+		require.Len(t, result.Drifts, 1)
+		require.True(t, result.Drifts[0].Corrected)
+		require.Equal(t, "api", currentTier())
+	})
+
+	t.Run("applies a changed desired state with the Report policy", func(t *testing.T) {
+		setTier("edited")
+
+		result, err := ApplyResourcesWithOptions(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, desiredSvc("web"), ApplyOptions{DriftPolicy: llamav1alpha1.DriftPolicyReport})
+		require.NoError(t, err)
+
+		require.Empty(t, result.Drifts)
+		require.Equal(t, "web", currentTier())
+	})
+}
+
+// TestApplyResources_Adoption verifies that existing resources are only adopted when the adoption
+// policy allows it, and never when another owner controls them.
+func TestApplyResources_Adoption(t *testing.T) {
+	ctx, testNs, owner := setupApplyResourcesTest(t, "adoption")
+	otherController := metav1.OwnerReference{
+		APIVersion: "apps/v1", Kind: "Deployment", Name: "other", UID: "other-uid", Controller: ptr.To(true),
+	}
+	existing := func(name string, annotations map[string]string, ownerRefs ...metav1.OwnerReference) {
+		require.NoError(t, k8sClient.Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNs, Annotations: annotations, OwnerReferences: ownerRefs},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "web", Port: 80}}},
+		}))
+	}
+	adoptAnnotation := map[string]string{llamav1alpha1.AdoptAnnotation: llamav1alpha1.AdoptValue}
+	existing("annotated", adoptAnnotation)
+	existing("unannotated", nil)
+	existing("controlled", adoptAnnotation, otherController)
+
+	resMap := resmap.New()
+	for _, name := range []string{"annotated", "unannotated", "controlled"} {
+		svc := newTestResource(t, "v1", "Service", name, testNs, map[string]any{"ports": []any{map[string]any{"name": "web", "port": 80}}})
+		svc.SetLabels(map[string]string{"state": "applied"})
+		require.NoError(t, resMap.Append(svc))
+	}
+	controllerUID := func(name string) types.UID {
+		svc := &corev1.Service{}
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: testNs}, svc))
+		if controller := metav1.GetControllerOf(svc); controller != nil {
+			return controller.UID
+		}
+		return ""
+	}
+
+	t.Run("adopts annotated resources", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		result, err := ApplyResourcesWithOptions(ctx, k8sClient, scheme.Scheme, recorder, owner, &resMap,
+			ApplyOptions{AdoptionPolicy: llamav1alpha1.AdoptionPolicyAnnotated})
+		require.NoError(t, err)
+
+		require.Equal(t, []SkippedResource{
+			{Kind: "Service", Name: "unannotated"},
+			{Kind: "Service", Name: "controlled", Controller: "Deployment/other"},
+		}, result.Skipped)
+		require.Equal(t, owner.UID, controllerUID("annotated"))
+		adopted := &corev1.Service{}
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "annotated", Namespace: testNs}, adopted))
+		require.Equal(t, "applied", adopted.Labels["state"], "adopted resources should be patched")
+		require.Contains(t, <-recorder.Events, EventReasonResourceAdopted+" Adopted existing Service annotated")
+	})
+
+	t.Run("adopts unowned resources with the Unowned policy", func(t *testing.T) {
+		result, err := ApplyResourcesWithOptions(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, &resMap,
+			ApplyOptions{AdoptionPolicy: llamav1alpha1.AdoptionPolicyUnowned})
+		require.NoError(t, err)
+
+		require.Equal(t, []SkippedResource{{Kind: "Service", Name: "controlled", Controller: "Deployment/other"}}, result.Skipped)
+		require.Equal(t, owner.UID, controllerUID("unannotated"))
+		require.Equal(t, otherController.UID, controllerUID("controlled"))
+	})
+}
+
 // TestApplyResources_PVCImmutability verifies that PVCs are not patched to maintain immutability.
 func TestApplyResources_PVCImmutability(t *testing.T) {
 	// given
@@ -465,7 +669,7 @@ func TestApplyResources_PVCImmutability(t *testing.T) {
 	require.NoError(t, resMap.Append(desiredPVC))
 
 	// when
-	require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, owner, &resMap))
+	require.NoError(t, ApplyResources(ctx, k8sClient, scheme.Scheme, record.NewFakeRecorder(10), owner, &resMap))
 
 	// then
 	// the PVC was NOT modified
@@ -482,6 +686,51 @@ func TestApplyResources_PVCImmutability(t *testing.T) {
 	require.Equal(t, expStorageSize, storageRequest.String(), "PVC storage spec should remain unchanged")
 }
 
+// TestApplyResources_AdoptPVC verifies that an existing claim made by hand is adopted, but its spec
+// and labels are still left as they are.
+func TestApplyResources_AdoptPVC(t *testing.T) {
+	ctx, testNs, owner := setupApplyResourcesTest(t, "adopt-pvc")
+	require.NoError(t, k8sClient.Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "hand-made",
+			Namespace:   testNs,
+			Labels:      map[string]string{"state": "original"},
+			Annotations: map[string]string{llamav1alpha1.AdoptAnnotation: llamav1alpha1.AdoptValue},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}))
+
+	desiredPVC := newTestResource(t, "v1", "PersistentVolumeClaim", "hand-made", testNs, map[string]any{
+		"accessModes": []any{"ReadWriteOnce"},
+		"resources":   map[string]any{"requests": map[string]any{"storage": "20Gi"}},
+	})
+	desiredPVC.SetLabels(map[string]string{"state": "modified"})
+	resMap := resmap.New()
+	require.NoError(t, resMap.Append(desiredPVC))
+
+	recorder := record.NewFakeRecorder(10)
+	result, err := ApplyResourcesWithOptions(ctx, k8sClient, scheme.Scheme, recorder, owner, &resMap,
+		ApplyOptions{AdoptionPolicy: llamav1alpha1.AdoptionPolicyAnnotated})
+	require.NoError(t, err)
+
+	require.Empty(t, result.Skipped)
+	require.Empty(t, result.Patched, "adopted claims should not be patched")
+	require.Contains(t, <-recorder.Events, EventReasonResourceAdopted+" Adopted existing PersistentVolumeClaim hand-made")
+	adopted := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "hand-made", Namespace: testNs}, adopted))
+	controller := metav1.GetControllerOf(adopted)
+	require.NotNil(t, controller)
+	require.Equal(t, owner.UID, controller.UID)
+	require.Equal(t, "original", adopted.Labels["state"], "PVC labels should remain unchanged")
+	storageRequest := adopted.Spec.Resources.Requests[corev1.ResourceStorage]
+	require.Equal(t, "10Gi", storageRequest.String(), "PVC storage spec should remain unchanged")
+}
+
 // TestFilterExcludeKinds tests the filtering functionality.
 func TestFilterExcludeKinds(t *testing.T) {
 	t.Run("excludes specified kinds", func(t *testing.T) {
@@ -583,3 +832,18 @@ func TestSetDefaultPort(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, int(llamav1alpha1.DefaultServerPort), actualPort)
 }
+
+func TestAddPodLabels(t *testing.T) {
+	labels := map[string]any{"app": "llama-stack"}
+	data := map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": labels},
+			},
+		},
+	}
+
+	addPodLabels(data, map[string]string{"app": "other", "team": "search"})
+
+	assert.Equal(t, map[string]any{"app": "llama-stack", "team": "search"}, labels)
+}
@@ -0,0 +1,117 @@
+package deploy
+
+import (
+	"fmt"
+	"regexp"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/deploy/plugins"
+)
+
+const (
+	// ServiceMonitorKind is the kind of the Prometheus Operator resource scraping the server.
+	ServiceMonitorKind = "ServiceMonitor"
+	// PrometheusRuleKind is the kind of the Prometheus Operator resource holding the default alerts.
+	PrometheusRuleKind = "PrometheusRule"
+	// MonitoringGroup is the API group of the Prometheus Operator resources.
+	MonitoringGroup = "monitoring.coreos.com"
+
+	defaultScrapeInterval = "30s"
+	defaultMetricsPath    = "/metrics"
+)
+
+// getMonitoringFieldMappings returns the field mappings for the ServiceMonitor and PrometheusRule.
+func getMonitoringFieldMappings(ownerInstance *llamav1alpha1.LlamaStackDistribution) []plugins.FieldMapping {
+	var interval, path string
+	if ownerInstance.Spec.Monitoring != nil {
+		interval = ownerInstance.Spec.Monitoring.Interval
+		path = ownerInstance.Spec.Monitoring.Path
+	}
+
+	return []plugins.FieldMapping{
+		{
+			SourceValue:       ownerInstance.GetName(),
+			TargetField:       "/spec/selector/matchLabels/app.kubernetes.io~1instance",
+			TargetKind:        ServiceMonitorKind,
+			CreateIfNotExists: true,
+		},
+		{
+			SourceValue:       interval,
+			DefaultValue:      defaultScrapeInterval,
+			TargetField:       "/spec/endpoints/0/interval",
+			TargetKind:        ServiceMonitorKind,
+			CreateIfNotExists: true,
+		},
+		{
+			SourceValue:       path,
+			DefaultValue:      defaultMetricsPath,
+			TargetField:       "/spec/endpoints/0/path",
+			TargetKind:        ServiceMonitorKind,
+			CreateIfNotExists: true,
+		},
+		{
+			SourceValue:       buildPrometheusRuleGroups(ownerInstance),
+			TargetField:       "/spec/groups",
+			TargetKind:        PrometheusRuleKind,
+			CreateIfNotExists: true,
+		},
+	}
+}
+
+// buildPrometheusRuleGroups returns the default alerts of a distribution. Replica and provider
+// alerts use the operator metrics; crash loops are detected through kube-state-metrics.
+func buildPrometheusRuleGroups(ownerInstance *llamav1alpha1.LlamaStackDistribution) []any {
+	name := ownerInstance.GetName()
+	namespace := ownerInstance.GetNamespace()
+	selector := fmt.Sprintf(`namespace="%s",name="%s"`, namespace, name)
+	// Pods of the Deployment are named <deployment>-<replicaset hash>-<suffix>.
+	podSelector := fmt.Sprintf("namespace=%q,pod=~`%s-[^-]+-[^-]+`", namespace, regexp.QuoteMeta(name))
+
+	return []any{
+		map[string]any{
+			"name": "llama-stack-distribution",
+			"rules": []any{
+				alertRule(
+					"LlamaStackDistributionReplicasUnready",
+					fmt.Sprintf("llamastack_distribution_ready_replicas{%[1]s} < llamastack_distribution_desired_replicas{%[1]s}", selector),
+					"10m",
+					"warning",
+					"LlamaStackDistribution {{ $labels.namespace }}/{{ $labels.name }} has unready replicas",
+					"Only {{ $value }} replicas have been ready for more than 10 minutes.",
+				),
+				alertRule(
+					"LlamaStackDistributionProviderUnhealthy",
+					fmt.Sprintf("llamastack_distribution_provider_health{%s} == 0", selector),
+					"5m",
+					"warning",
+					"LlamaStackDistribution {{ $labels.namespace }}/{{ $labels.name }} has an unhealthy provider",
+					"Provider {{ $labels.provider_id }} of API {{ $labels.api }} has been unhealthy for more than 5 minutes.",
+				),
+				alertRule(
+					"LlamaStackDistributionCrashLooping",
+					fmt.Sprintf(`max_over_time(kube_pod_container_status_waiting_reason{%s,reason="CrashLoopBackOff"}[5m]) >= 1`, podSelector),
+					"15m",
+					"critical",
+					"LlamaStackDistribution {{ $labels.namespace }}/"+name+" is crash looping",
+					"Container {{ $labels.container }} of pod {{ $labels.pod }} has been in CrashLoopBackOff for more than 15 minutes.",
+				),
+			},
+		},
+	}
+}
+
+// alertRule builds a single Prometheus alerting rule.
+func alertRule(alert, expr, duration, severity, summary, description string) map[string]any {
+	return map[string]any{
+		"alert": alert,
+		"expr":  expr,
+		"for":   duration,
+		"labels": map[string]any{
+			"severity": severity,
+		},
+		"annotations": map[string]any{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package deploy
+
+import (
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+const operatorManifestsBasePath = "../../controllers/manifests/base"
+
+func findRenderedResource(t *testing.T, resMap *resmap.ResMap, kind string) map[string]any {
+	t.Helper()
+	for _, res := range (*resMap).Resources() {
+		if res.GetKind() == kind {
+			data, err := res.Map()
+			require.NoError(t, err)
+			return data
+		}
+	}
+	require.Failf(t, "resource not rendered", "kind %s", kind)
+	return nil
+}
+
+func TestRenderMonitoringResources(t *testing.T) {
+	owner := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-llsd", Namespace: "test-ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Monitoring: &llamav1alpha1.MonitoringSpec{Enabled: true, Interval: "15s"},
+		},
+	}
+
+	resMap, err := RenderManifest(filesys.MakeFsOnDisk(), operatorManifestsBasePath, owner)
+	require.NoError(t, err)
+
+	t.Run("service monitor selects the instance service", func(t *testing.T) {
+		monitor := findRenderedResource(t, resMap, ServiceMonitorKind)
+		name, _, _ := unstructured.NestedString(monitor, "metadata", "name")
+		assert.Equal(t, "my-llsd-service-monitor", name)
+		managedBy, _, _ := unstructured.NestedString(monitor, "metadata", "labels", "app.kubernetes.io/managed-by")
+		assert.Equal(t, "llama-stack-operator", managedBy)
+
+		selector, _, _ := unstructured.NestedStringMap(monitor, "spec", "selector", "matchLabels")
+		service := findRenderedResource(t, resMap, "Service")
+		serviceLabels, _, _ := unstructured.NestedStringMap(service, "metadata", "labels")
+		for key, value := range selector {
+			assert.Equal(t, value, serviceLabels[key], "service label %s", key)
+		}
+		expressions, _, _ := unstructured.NestedSlice(monitor, "spec", "selector", "matchExpressions")
+		assert.Equal(t, []any{map[string]any{
+			"key": RolloutTrackLabel, "operator": "NotIn", "values": []any{RolloutTrackPreview},
+		}}, expressions, "preview Services must not be scraped")
+
+		endpoints, _, _ := unstructured.NestedSlice(monitor, "spec", "endpoints")
+		require.Len(t, endpoints, 1)
+		endpoint, ok := endpoints[0].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "15s", endpoint["interval"])
+		assert.Equal(t, defaultMetricsPath, endpoint["path"])
+		assert.Equal(t, "http", endpoint["port"])
+	})
+
+	t.Run("prometheus rule alerts on the instance", func(t *testing.T) {
+		rule := findRenderedResource(t, resMap, PrometheusRuleKind)
+		namespace, _, _ := unstructured.NestedString(rule, "metadata", "namespace")
+		assert.Equal(t, "test-ns", namespace)
+
+		groups, _, _ := unstructured.NestedSlice(rule, "spec", "groups")
+		require.Len(t, groups, 1)
+		group, ok := groups[0].(map[string]any)
+		require.True(t, ok)
+		rules, ok := group["rules"].([]any)
+		require.True(t, ok)
+
+		alerts := map[string]string{}
+		for _, r := range rules {
+			alertRule, ok := r.(map[string]any)
+			require.True(t, ok)
+			alertName, _ := alertRule["alert"].(string)
+			expr, _ := alertRule["expr"].(string)
+			alerts[alertName] = expr
+		}
+		assert.Contains(t, alerts["LlamaStackDistributionReplicasUnready"], `namespace="test-ns",name="my-llsd"`)
+		assert.Contains(t, alerts["LlamaStackDistributionProviderUnhealthy"], "llamastack_distribution_provider_health")
+		assert.Contains(t, alerts["LlamaStackDistributionCrashLooping"], "pod=~`my-llsd-[^-]+-[^-]+`")
+	})
+}
@@ -6,13 +6,68 @@ import (
 
 	"github.com/go-logr/logr"
 	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/yaml"
 )
 
+const dnsPort = 53
+
+// applyNetworkRules adds the ingress and egress rules of the distribution to the rendered
+// NetworkPolicy. Restricting egress always allows DNS, so the server can resolve the backends
+// allowed by the rules.
+func applyNetworkRules(resMap resmap.ResMap, ownerInstance *llamav1alpha1.LlamaStackDistribution) error {
+	network := ownerInstance.Spec.Network
+	if network == nil || (len(network.Ingress) == 0 && len(network.Egress) == 0) {
+		return nil
+	}
+
+	for _, res := range resMap.Resources() {
+		if res.GetKind() != "NetworkPolicy" {
+			continue
+		}
+
+		policy := &networkingv1.NetworkPolicy{}
+		if err := yaml.Unmarshal([]byte(res.MustYaml()), policy); err != nil {
+			return fmt.Errorf("failed to unmarshal NetworkPolicy: %w", err)
+		}
+		policy.Spec.Ingress = append(policy.Spec.Ingress, network.Ingress...)
+		if len(network.Egress) > 0 {
+			policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+			policy.Spec.Egress = append([]networkingv1.NetworkPolicyEgressRule{dnsEgressRule()}, network.Egress...)
+		}
+
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(policy)
+		if err != nil {
+			return fmt.Errorf("failed to convert NetworkPolicy: %w", err)
+		}
+		unstructured.RemoveNestedField(data, "metadata", "creationTimestamp")
+		if err := updateResourceFromData(res, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dnsEgressRule allows DNS queries to any destination.
+func dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	port := intstr.FromInt32(dnsPort)
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: ptr.To(corev1.ProtocolUDP), Port: &port},
+			{Protocol: ptr.To(corev1.ProtocolTCP), Port: &port},
+		},
+	}
+}
+
 // ApplyNetworkPolicy creates or updates a NetworkPolicy.
 func ApplyNetworkPolicy(ctx context.Context, c client.Client, scheme *runtime.Scheme,
 	instance *llamav1alpha1.LlamaStackDistribution, networkPolicy *networkingv1.NetworkPolicy, log logr.Logger) error {
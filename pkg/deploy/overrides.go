@@ -0,0 +1,144 @@
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/kustomize/api/filters/patchjson6902"
+	"sigs.k8s.io/kustomize/api/filters/patchstrategicmerge"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// managedByLabel is set on every rendered resource. The operator relies on it to prune them.
+const managedByLabel = "app.kubernetes.io/managed-by"
+
+// applyOverridePatches applies the patches of spec.overrides to the rendered resources, in order.
+// A patch matching no rendered resource is an error, as it is most likely a typo in its target.
+func applyOverridePatches(resMap resmap.ResMap, ownerInstance *llamav1alpha1.LlamaStackDistribution) error {
+	if ownerInstance.Spec.Overrides == nil {
+		return nil
+	}
+
+	for i, patch := range ownerInstance.Spec.Overrides.Patches {
+		matched := false
+		for _, res := range resMap.Resources() {
+			if res.GetKind() != patch.Target.Kind || (patch.Target.Name != "" && res.GetName() != patch.Target.Name) {
+				continue
+			}
+			matched = true
+			kind, name := res.GetKind(), res.GetName()
+			if err := applyOverridePatch(res, patch); err != nil {
+				return fmt.Errorf("failed to apply patch %d to %s %s: %w", i, kind, name, err)
+			}
+		}
+		if !matched {
+			return fmt.Errorf("failed to apply patch %d: no rendered %s matches its target", i, patch.Target.Kind)
+		}
+	}
+	return nil
+}
+
+// applyOverridePatch applies a patch to a rendered resource and validates the result.
+func applyOverridePatch(res *resource.Resource, patch llamav1alpha1.ManifestPatch) error {
+	before, err := res.Map()
+	if err != nil {
+		return fmt.Errorf("failed to read resource: %w", err)
+	}
+
+	switch patch.Type {
+	case llamav1alpha1.ManifestPatchJSON6902:
+		if err := res.ApplyFilter(patchjson6902.Filter{Patch: patch.Patch}); err != nil {
+			return fmt.Errorf("failed to apply JSON6902 patch: %w", err)
+		}
+	default:
+		patchNode, err := kyaml.Parse(patch.Patch)
+		if err != nil {
+			return fmt.Errorf("failed to parse strategic merge patch: %w", err)
+		}
+		// The patched resource is identified by the target. Its type also selects the merge keys of lists.
+		patchNode.SetApiVersion(res.GetApiVersion())
+		patchNode.SetKind(res.GetKind())
+		if err := patchNode.SetName(res.GetName()); err != nil {
+			return fmt.Errorf("failed to set patch name: %w", err)
+		}
+		if err := res.ApplyFilter(patchstrategicmerge.Filter{Patch: patchNode}); err != nil {
+			return fmt.Errorf("failed to apply strategic merge patch: %w", err)
+		}
+	}
+
+	if res.IsNilOrEmpty() {
+		return errors.New("failed to validate patched resource: patches must not delete resources")
+	}
+	after, err := res.Map()
+	if err != nil {
+		return fmt.Errorf("failed to read patched resource: %w", err)
+	}
+	return validatePatchedResource(before, after)
+}
+
+// validatePatchedResource checks that a patch left the fields the reconciler relies on unchanged,
+// and that the patched resource still decodes into its type.
+func validatePatchedResource(before, after map[string]any) error {
+	for _, path := range protectedFields(before) {
+		beforeValue, _, _ := unstructured.NestedFieldNoCopy(before, path...)
+		afterValue, _, _ := unstructured.NestedFieldNoCopy(after, path...)
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			return fmt.Errorf("failed to validate patched resource: %s must not be changed", strings.Join(path, "."))
+		}
+	}
+
+	patched := &unstructured.Unstructured{Object: after}
+	typed, err := scheme.Scheme.New(patched.GroupVersionKind())
+	if err != nil {
+		// Kinds unknown to the client-go scheme, such as ServiceMonitor, are validated by the API server only.
+		return nil //nolint:nilerr
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(after, typed, true); err != nil {
+		return fmt.Errorf("failed to validate patched %s: %w", patched.GetKind(), err)
+	}
+	return nil
+}
+
+// protectedFields returns the paths of the fields of a rendered resource that patches must not
+// change: its identity, its owner references, its managed-by label and the selectors matching the
+// server pods.
+func protectedFields(obj map[string]any) [][]string {
+	fields := [][]string{
+		{"apiVersion"},
+		{"kind"},
+		{"metadata", "name"},
+		{"metadata", "namespace"},
+		{"metadata", "ownerReferences"},
+		{"metadata", "labels", managedByLabel},
+	}
+
+	kind, _, _ := unstructured.NestedString(obj, "kind")
+	switch kind {
+	case "Deployment":
+		fields = append(fields, []string{"spec", "selector"})
+		// The pod template must keep matching the selector.
+		matchLabels, _, _ := unstructured.NestedStringMap(obj, "spec", "selector", "matchLabels")
+		keys := make([]string, 0, len(matchLabels))
+		for key := range matchLabels {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			fields = append(fields, []string{"spec", "template", "metadata", "labels", key})
+		}
+	case "Service", ServiceMonitorKind, "PodDisruptionBudget":
+		fields = append(fields, []string{"spec", "selector"})
+	case "NetworkPolicy":
+		fields = append(fields, []string{"spec", "podSelector"})
+	}
+	return fields
+}
@@ -0,0 +1,209 @@
+package deploy
+
+import (
+	"path/filepath"
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func newOverridesTestFs(t *testing.T) filesys.FileSystem {
+	t.Helper()
+	fsys := filesys.MakeFsInMemory()
+	require.NoError(t, fsys.MkdirAll(manifestBasePath))
+	files := map[string]string{
+		"kustomization.yaml": `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - deployment.yaml
+  - service.yaml
+`,
+		"deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment
+  labels:
+    app.kubernetes.io/managed-by: llama-stack-operator
+spec:
+  selector:
+    matchLabels:
+      app: llama-stack
+  template:
+    metadata:
+      labels:
+        app: llama-stack
+    spec:
+      containers:
+      - name: llama-stack
+        image: llamastack/distribution-ollama:latest
+`,
+		"service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: service
+  labels:
+    app.kubernetes.io/managed-by: llama-stack-operator
+spec:
+  selector:
+    app: llama-stack
+  ports:
+  - name: http
+    port: 8321
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, fsys.WriteFile(filepath.Join(manifestBasePath, name), []byte(content)))
+	}
+	return fsys
+}
+
+func renderWithPatches(t *testing.T, patches ...llamav1alpha1.ManifestPatch) (map[string]map[string]any, error) {
+	t.Helper()
+	owner := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-instance", Namespace: "test-overrides-ns"},
+		Spec: llamav1alpha1.LlamaStackDistributionSpec{
+			Overrides: &llamav1alpha1.OverridesSpec{Patches: patches},
+		},
+	}
+	resMap, err := RenderManifestWithContext(newOverridesTestFs(t), manifestBasePath, owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	rendered := map[string]map[string]any{}
+	for _, res := range (*resMap).Resources() {
+		data, err := res.Map()
+		require.NoError(t, err)
+		rendered[res.GetKind()] = data
+	}
+	return rendered, nil
+}
+
+func TestApplyOverridePatches(t *testing.T) {
+	t.Run("applies a strategic merge patch", func(t *testing.T) {
+		rendered, err := renderWithPatches(t, llamav1alpha1.ManifestPatch{
+			Target: llamav1alpha1.PatchTarget{Kind: "Deployment", Name: "test-instance"},
+			Patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: llama-stack
+        env:
+        - name: LOG_LEVEL
+          value: debug
+`,
+		})
+		require.NoError(t, err)
+
+		containers, _, err := unstructured.NestedSlice(rendered["Deployment"], "spec", "template", "spec", "containers")
+		require.NoError(t, err)
+		require.Len(t, containers, 1, "containers should be merged by name")
+		container, ok := containers[0].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "llamastack/distribution-ollama:latest", container["image"])
+		assert.Equal(t, []any{map[string]any{"name": "LOG_LEVEL", "value": "debug"}}, container["env"])
+	})
+
+	t.Run("applies a JSON6902 patch to every resource of the kind", func(t *testing.T) {
+		rendered, err := renderWithPatches(t, llamav1alpha1.ManifestPatch{
+			Target: llamav1alpha1.PatchTarget{Kind: "Service"},
+			Type:   llamav1alpha1.ManifestPatchJSON6902,
+			Patch:  `[{"op": "add", "path": "/spec/type", "value": "NodePort"}]`,
+		})
+		require.NoError(t, err)
+
+		serviceType, _, err := unstructured.NestedString(rendered["Service"], "spec", "type")
+		require.NoError(t, err)
+		assert.Equal(t, "NodePort", serviceType)
+	})
+
+	testCases := []struct {
+		name          string
+		patch         llamav1alpha1.ManifestPatch
+		expectedError string
+	}{
+		{
+			name: "rejects a patch matching no resource",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Deployment", Name: "typo"},
+				Patch:  "metadata:\n  annotations:\n    note: value\n",
+			},
+			expectedError: "no rendered Deployment matches its target",
+		},
+		{
+			name: "rejects a changed selector",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Service"},
+				Patch:  "spec:\n  selector:\n    app: other\n",
+			},
+			expectedError: "spec.selector must not be changed",
+		},
+		{
+			name: "rejects pod labels no longer matching the selector",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Deployment"},
+				Type:   llamav1alpha1.ManifestPatchJSON6902,
+				Patch:  `[{"op": "remove", "path": "/spec/template/metadata/labels/app"}]`,
+			},
+			expectedError: "spec.template.metadata.labels.app must not be changed",
+		},
+		{
+			name: "rejects owner references",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Service"},
+				Patch:  "metadata:\n  ownerReferences:\n  - apiVersion: v1\n    kind: ConfigMap\n    name: other\n    uid: other-uid\n",
+			},
+			expectedError: "metadata.ownerReferences must not be changed",
+		},
+		{
+			name: "rejects a removed managed-by label",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Service"},
+				Type:   llamav1alpha1.ManifestPatchJSON6902,
+				Patch:  `[{"op": "remove", "path": "/metadata/labels"}]`,
+			},
+			expectedError: "metadata.labels.app.kubernetes.io/managed-by must not be changed",
+		},
+		{
+			name: "rejects a deleted resource",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Service"},
+				Patch:  "$patch: delete\n",
+			},
+			expectedError: "patches must not delete resources",
+		},
+		{
+			name: "rejects an unknown field",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Deployment"},
+				Patch:  "spec:\n  replicaCount: 2\n",
+			},
+			expectedError: "failed to validate patched Deployment",
+		},
+		{
+			name: "rejects an invalid JSON6902 patch",
+			patch: llamav1alpha1.ManifestPatch{
+				Target: llamav1alpha1.PatchTarget{Kind: "Service"},
+				Type:   llamav1alpha1.ManifestPatchJSON6902,
+				Patch:  `[{"op": "test", "path": "/spec/selector/app", "value": "other"}]`,
+			},
+			expectedError: "failed to apply JSON6902 patch",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := renderWithPatches(t, tc.patch)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+const (
+	// RolloutTrackLabel is the pod label that tells the stable and preview revisions apart.
+	RolloutTrackLabel = "llamastack.io/rollout-track"
+	// RolloutTrackStable is the track of the main Deployment.
+	RolloutTrackStable = "stable"
+	// RolloutTrackPreview is the track of the Deployment running the new revision.
+	RolloutTrackPreview = "preview"
+	// RolloutRevisionAnnotation records the pod template revision applied to a Deployment.
+	RolloutRevisionAnnotation = "llamastack.io/rollout-revision"
+
+	// revisionHashLength is the number of hex characters kept from the template hash.
+	revisionHashLength = 10
+)
+
+// GetPreviewDeploymentName returns the name of the Deployment running the new revision during a rollout.
+func GetPreviewDeploymentName(instance *llamav1alpha1.LlamaStackDistribution) string {
+	return instance.Name + "-preview"
+}
+
+// GetPreviewServiceName returns the name of the Service selecting only the preview pods.
+func GetPreviewServiceName(instance *llamav1alpha1.LlamaStackDistribution) string {
+	return instance.Name + "-preview-service"
+}
+
+// PrepareStableDeployment labels the rendered Deployment as the stable track and
+// annotates it with the revision of its pod template. The revision is returned.
+//
+// The track label is only added to the pod template: the selector of an existing Deployment is
+// immutable, so the stable selector keeps matching the preview pods. This overlap is harmless
+// because a Deployment only manages the ReplicaSets it controls, and the preview pods belong to
+// the ReplicaSet of the preview Deployment, whose selector includes the preview track.
+func PrepareStableDeployment(resMap *resmap.ResMap) (string, error) {
+	res := findResource(resMap, "Deployment")
+	if res == nil {
+		return "", errors.New("failed to find Deployment in rendered manifests")
+	}
+
+	data, err := res.Map()
+	if err != nil {
+		return "", fmt.Errorf("failed to read Deployment: %w", err)
+	}
+	if err := setPodTemplateLabel(data, RolloutTrackLabel, RolloutTrackStable); err != nil {
+		return "", err
+	}
+
+	revision, err := podTemplateRevision(data)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, ok := data["metadata"].(map[string]any)
+	if !ok {
+		return "", errors.New("failed to find Deployment metadata")
+	}
+	annotations, ok := metadata["annotations"].(map[string]any)
+	if !ok {
+		annotations = make(map[string]any)
+		metadata["annotations"] = annotations
+	}
+	annotations[RolloutRevisionAnnotation] = revision
+
+	if err := updateResourceFromData(res, data); err != nil {
+		return "", err
+	}
+	return revision, nil
+}
+
+// BuildPreviewResources derives the preview Deployment and Service from the rendered
+// stable ones. The preview Deployment selects only pods on the preview track and runs
+// the given number of replicas.
+func BuildPreviewResources(
+	resMap *resmap.ResMap,
+	instance *llamav1alpha1.LlamaStackDistribution,
+	replicas int32,
+) (*resmap.ResMap, error) {
+	previewResMap := resmap.New()
+
+	deployment := findResource(resMap, "Deployment")
+	if deployment == nil {
+		return nil, errors.New("failed to find Deployment in rendered manifests")
+	}
+	data, err := deployment.Map()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Deployment: %w", err)
+	}
+	if err := setPodTemplateLabel(data, RolloutTrackLabel, RolloutTrackPreview); err != nil {
+		return nil, err
+	}
+	spec, ok := data["spec"].(map[string]any)
+	if !ok {
+		return nil, errors.New("failed to find deployment spec")
+	}
+	spec["replicas"] = replicas
+	if err := setNestedLabel(spec, RolloutTrackLabel, RolloutTrackPreview, "selector", "matchLabels"); err != nil {
+		return nil, err
+	}
+	setName(data, GetPreviewDeploymentName(instance))
+
+	previewDeployment := deployment.DeepCopy()
+	if err := updateResourceFromData(previewDeployment, data); err != nil {
+		return nil, err
+	}
+	if err := previewResMap.Append(previewDeployment); err != nil {
+		return nil, fmt.Errorf("failed to append preview Deployment: %w", err)
+	}
+
+	// The Service is only rendered when the instance exposes ports.
+	service := findResource(resMap, "Service")
+	if service == nil {
+		return &previewResMap, nil
+	}
+	serviceData, err := service.Map()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Service: %w", err)
+	}
+	serviceSpec, ok := serviceData["spec"].(map[string]any)
+	if !ok {
+		return nil, errors.New("failed to find service spec")
+	}
+	if err := setNestedLabel(serviceSpec, RolloutTrackLabel, RolloutTrackPreview, "selector"); err != nil {
+		return nil, err
+	}
+	// The ServiceMonitor excludes the preview track, so the preview pods are not scraped as the distribution.
+	if err := setNestedLabel(serviceData, RolloutTrackLabel, RolloutTrackPreview, "metadata", "labels"); err != nil {
+		return nil, err
+	}
+	setName(serviceData, GetPreviewServiceName(instance))
+
+	previewService := service.DeepCopy()
+	if err := updateResourceFromData(previewService, serviceData); err != nil {
+		return nil, err
+	}
+	if err := previewResMap.Append(previewService); err != nil {
+		return nil, fmt.Errorf("failed to append preview Service: %w", err)
+	}
+
+	return &previewResMap, nil
+}
+
+// SetServiceTrack pins the rendered Service selector to a single rollout track.
+// It is a no-op when no Service is rendered.
+func SetServiceTrack(resMap *resmap.ResMap, track string) error {
+	service := findResource(resMap, "Service")
+	if service == nil {
+		return nil
+	}
+	data, err := service.Map()
+	if err != nil {
+		return fmt.Errorf("failed to read Service: %w", err)
+	}
+	spec, ok := data["spec"].(map[string]any)
+	if !ok {
+		return errors.New("failed to find service spec")
+	}
+	if err := setNestedLabel(spec, RolloutTrackLabel, track, "selector"); err != nil {
+		return err
+	}
+	return updateResourceFromData(service, data)
+}
+
+// findResource returns the first resource of the given kind in the ResMap, or nil if there is none.
+func findResource(resMap *resmap.ResMap, kind string) *resource.Resource {
+	for _, res := range (*resMap).Resources() {
+		if res.GetKind() == kind {
+			return res
+		}
+	}
+	return nil
+}
+
+// podTemplateRevision returns a short, stable hash of the Deployment pod template.
+func podTemplateRevision(data map[string]any) (string, error) {
+	spec, ok := data["spec"].(map[string]any)
+	if !ok {
+		return "", errors.New("failed to find deployment spec")
+	}
+	template, ok := spec["template"].(map[string]any)
+	if !ok {
+		return "", errors.New("failed to find deployment template")
+	}
+	// encoding/json sorts map keys, so the output is deterministic.
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal deployment template: %w", err)
+	}
+	sum := sha256.Sum256(templateJSON)
+	return hex.EncodeToString(sum[:])[:revisionHashLength], nil
+}
+
+// setPodTemplateLabel sets a label on the Deployment pod template.
+func setPodTemplateLabel(data map[string]any, key, value string) error {
+	spec, ok := data["spec"].(map[string]any)
+	if !ok {
+		return errors.New("failed to find deployment spec")
+	}
+	return setNestedLabel(spec, key, value, "template", "metadata", "labels")
+}
+
+// setNestedLabel sets key=value in the string map found at path, creating it if needed.
+func setNestedLabel(obj map[string]any, key, value string, path ...string) error {
+	current := obj
+	for _, field := range path {
+		next, exists := current[field]
+		if !exists || next == nil {
+			next = make(map[string]any)
+			current[field] = next
+		}
+		nextMap, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to set label %s: field %s is not a map", key, field)
+		}
+		current = nextMap
+	}
+	current[key] = value
+	return nil
+}
+
+// setName sets metadata.name on the object.
+func setName(data map[string]any, name string) {
+	metadata, ok := data["metadata"].(map[string]any)
+	if !ok {
+		metadata = make(map[string]any)
+		data["metadata"] = metadata
+	}
+	metadata["name"] = name
+}
@@ -0,0 +1,123 @@
+package deploy
+
+import (
+	"testing"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+func newRolloutTestResMap(t *testing.T, image string) *resmap.ResMap {
+	t.Helper()
+
+	deployment := newTestResource(t, "apps/v1", "Deployment", "test-instance", "test-ns", map[string]any{
+		"replicas": 3,
+		"template": map[string]any{
+			"metadata": map[string]any{"labels": map[string]any{"app": "test-instance"}},
+			"spec": map[string]any{
+				"containers": []any{
+					map[string]any{"name": "llama-stack", "image": image},
+				},
+			},
+		},
+	})
+	service := newTestResource(t, "v1", "Service", "test-instance-service", "test-ns", map[string]any{
+		"selector": map[string]any{"app": "test-instance"},
+	})
+
+	resMap := resmap.New()
+	require.NoError(t, resMap.Append(deployment))
+	require.NoError(t, resMap.Append(service))
+	return &resMap
+}
+
+func TestPrepareStableDeployment(t *testing.T) {
+	t.Run("should label the pod template and annotate the revision", func(t *testing.T) {
+		resMap := newRolloutTestResMap(t, "llama:v1")
+
+		revision, err := PrepareStableDeployment(resMap)
+		require.NoError(t, err)
+		require.Len(t, revision, revisionHashLength)
+
+		data, err := (*resMap).Resources()[0].Map()
+		require.NoError(t, err)
+		track, _, _ := unstructured.NestedString(data, "spec", "template", "metadata", "labels", RolloutTrackLabel)
+		assert.Equal(t, RolloutTrackStable, track)
+		annotation, _, _ := unstructured.NestedString(data, "metadata", "annotations", RolloutRevisionAnnotation)
+		assert.Equal(t, revision, annotation)
+		_, found, _ := unstructured.NestedString(data, "spec", "selector", "matchLabels", RolloutTrackLabel)
+		assert.False(t, found, "the immutable selector must not change")
+	})
+
+	t.Run("should derive the revision from the pod template only", func(t *testing.T) {
+		first, err := PrepareStableDeployment(newRolloutTestResMap(t, "llama:v1"))
+		require.NoError(t, err)
+		same, err := PrepareStableDeployment(newRolloutTestResMap(t, "llama:v1"))
+		require.NoError(t, err)
+		updated, err := PrepareStableDeployment(newRolloutTestResMap(t, "llama:v2"))
+		require.NoError(t, err)
+
+		assert.Equal(t, first, same)
+		assert.NotEqual(t, first, updated)
+	})
+
+	t.Run("should fail without a Deployment", func(t *testing.T) {
+		resMap := resmap.New()
+		_, err := PrepareStableDeployment(&resMap)
+		require.Error(t, err)
+	})
+}
+
+func TestBuildPreviewResources(t *testing.T) {
+	instance := &llamav1alpha1.LlamaStackDistribution{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-instance", Namespace: "test-ns"},
+	}
+	resMap := newRolloutTestResMap(t, "llama:v2")
+
+	previewResMap, err := BuildPreviewResources(resMap, instance, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, (*previewResMap).Size())
+
+	deployment, err := (*previewResMap).Resources()[0].Map()
+	require.NoError(t, err)
+	name, _, _ := unstructured.NestedString(deployment, "metadata", "name")
+	assert.Equal(t, "test-instance-preview", name)
+	replicas, _, _ := unstructured.NestedFieldNoCopy(deployment, "spec", "replicas")
+	assert.EqualValues(t, 1, replicas)
+	selectorTrack, _, _ := unstructured.NestedString(deployment, "spec", "selector", "matchLabels", RolloutTrackLabel)
+	assert.Equal(t, RolloutTrackPreview, selectorTrack)
+	templateTrack, _, _ := unstructured.NestedString(deployment, "spec", "template", "metadata", "labels", RolloutTrackLabel)
+	assert.Equal(t, RolloutTrackPreview, templateTrack)
+
+	service, err := (*previewResMap).Resources()[1].Map()
+	require.NoError(t, err)
+	serviceName, _, _ := unstructured.NestedString(service, "metadata", "name")
+	assert.Equal(t, "test-instance-preview-service", serviceName)
+	serviceTrack, _, _ := unstructured.NestedString(service, "spec", "selector", RolloutTrackLabel)
+	assert.Equal(t, RolloutTrackPreview, serviceTrack)
+	serviceLabel, _, _ := unstructured.NestedString(service, "metadata", "labels", RolloutTrackLabel)
+	assert.Equal(t, RolloutTrackPreview, serviceLabel, "the preview Service must be excluded from the ServiceMonitor")
+
+	// the rendered resources must be left untouched
+	original, err := (*resMap).Resources()[0].Map()
+	require.NoError(t, err)
+	originalName, _, _ := unstructured.NestedString(original, "metadata", "name")
+	assert.Equal(t, "test-instance", originalName)
+}
+
+func TestSetServiceTrack(t *testing.T) {
+	resMap := newRolloutTestResMap(t, "llama:v1")
+
+	require.NoError(t, SetServiceTrack(resMap, RolloutTrackStable))
+
+	service, err := (*resMap).Resources()[1].Map()
+	require.NoError(t, err)
+	track, _, _ := unstructured.NestedString(service, "spec", "selector", RolloutTrackLabel)
+	assert.Equal(t, RolloutTrackStable, track)
+	app, _, _ := unstructured.NestedString(service, "spec", "selector", "app")
+	assert.Equal(t, "test-instance", app)
+}
@@ -3,8 +3,10 @@ package deploy
 import (
 	"fmt"
 	"os"
+	"time"
 
 	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/schedule"
 )
 
 func GetOperatorNamespace() (string, error) {
@@ -28,3 +30,17 @@ func GetServicePort(instance *llamav1alpha1.LlamaStackDistribution) int32 {
 func GetServiceName(instance *llamav1alpha1.LlamaStackDistribution) string {
 	return fmt.Sprintf("%s-service", instance.Name)
 }
+
+// GetDesiredReplicas returns the number of replicas the Deployment should run.
+// Suspended instances run no replicas, and the active schedule entry overrides spec.replicas.
+func GetDesiredReplicas(instance *llamav1alpha1.LlamaStackDistribution) int32 {
+	if instance.Spec.Suspend {
+		return 0
+	}
+	if len(instance.Spec.Schedule) > 0 {
+		if evaluation := schedule.Evaluate(instance.Spec.Schedule, time.Now()); evaluation.Active != nil {
+			return evaluation.Active.Replicas
+		}
+	}
+	return instance.Spec.Replicas
+}
@@ -9,6 +9,18 @@ type FeatureFlag struct {
 type FeatureFlags struct {
 	// EnableNetworkPolicy controls whether NetworkPolicy resources should be created.
 	EnableNetworkPolicy FeatureFlag `yaml:"enableNetworkPolicy"`
+	// ProviderConfigRedaction controls how provider configs are exposed in the status.
+	ProviderConfigRedaction ProviderConfigRedaction `yaml:"providerConfigRedaction,omitempty"`
+}
+
+// ProviderConfigRedaction configures the redaction of the provider configs reported by the
+// server before they are written to .status.distributionConfig.providers.
+type ProviderConfigRedaction struct {
+	// KeyPatterns are case-insensitive regular expressions matched against config field names,
+	// at any depth. The values of matching fields are redacted. Defaults are used when empty.
+	KeyPatterns []string `yaml:"keyPatterns,omitempty"`
+	// DropConfig removes the provider configs from the status entirely.
+	DropConfig bool `yaml:"dropConfig,omitempty"`
 }
 
 const (
@@ -16,6 +28,8 @@ const (
 	FeatureFlagsKey = "featureFlags"
 	// EnableNetworkPolicyKey is the key for the network policy feature flag.
 	EnableNetworkPolicyKey = "enableNetworkPolicy"
+	// ProviderConfigRedactionKey is the key for the provider config redaction settings.
+	ProviderConfigRedactionKey = "providerConfigRedaction"
 	// NetworkPolicyDefaultValue is the default value for the network policy feature flag.
 	NetworkPolicyDefaultValue = false
 )
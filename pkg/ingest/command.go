@@ -0,0 +1,108 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+)
+
+// Command is the subcommand of the operator binary running an ingestion.
+const Command = "ingest"
+
+// Flags of the ingest subcommand, used by the controller to build the job arguments.
+const (
+	FlagServerURL     = "server-url"
+	FlagVectorStoreID = "vector-store-id"
+	FlagChunkSize     = "chunk-size"
+	FlagChunkOverlap  = "chunk-overlap"
+	FlagPath          = "path"
+	FlagURL           = "url"
+)
+
+// requestTimeout bounds each request to the server and to document URLs.
+const requestTimeout = 5 * time.Minute
+
+// stringList is a flag that can be repeated.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// Main runs the ingest subcommand with args and returns the exit code. The result is written as JSON
+// to the termination message file, so it is available in the status of the terminated container.
+func Main(ctx context.Context, args []string) int {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	var opts Options
+	var serverURL, terminationLog string
+	paths, urls := stringList{}, stringList{}
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.StringVar(&serverURL, FlagServerURL, "", "Base URL of the llama-stack server.")
+	flags.StringVar(&opts.VectorStoreID, FlagVectorStoreID, "", "ID of the vector store to insert the chunks in.")
+	flags.IntVar(&opts.ChunkSize, FlagChunkSize, 512, "Number of words per chunk.")
+	flags.IntVar(&opts.ChunkOverlap, FlagChunkOverlap, 0, "Number of words shared by consecutive chunks.")
+	flags.Var(&paths, FlagPath, "File or directory to ingest, may be repeated.")
+	flags.Var(&urls, FlagURL, "URL of a document to ingest, may be repeated.")
+	flags.StringVar(&terminationLog, "termination-log", "/dev/termination-log", "File the result is written to.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	opts.Paths, opts.URLs = paths, urls
+
+	if serverURL == "" || opts.VectorStoreID == "" {
+		logger.Error(nil, "failed to start ingestion: --server-url and --vector-store-id are required")
+		return 2
+	}
+
+	httpClient := &http.Client{Timeout: requestTimeout}
+	server, err := llamastack.NewClient(httpClient, serverURL)
+	if err != nil {
+		logger.Error(err, "failed to create server client")
+		return 1
+	}
+
+	result, runErr := Run(ctx, server, httpClient, opts)
+	if err := writeResult(terminationLog, result); err != nil {
+		logger.Error(err, "failed to write ingestion result")
+	}
+	if runErr != nil {
+		logger.Error(runErr, "failed to ingest documents", "documents", result.Documents, "failedDocuments", result.FailedDocuments)
+		return 1
+	}
+	logger.Info("Ingestion completed", "documents", result.Documents, "chunks", result.Chunks)
+	return 0
+}
+
+func writeResult(path string, result Result) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		return fmt.Errorf("failed to write result to %s: %w", path, err)
+	}
+	return nil
+}
+
+// ParseResult parses the termination message of an ingestion container.
+func ParseResult(message string) (Result, error) {
+	var result Result
+	if err := json.Unmarshal([]byte(message), &result); err != nil {
+		return result, fmt.Errorf("failed to parse ingestion result: %w", err)
+	}
+	return result, nil
+}
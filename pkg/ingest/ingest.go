@@ -0,0 +1,201 @@
+// Package ingest implements the document ingestion run by the jobs of LlamaStackVectorStores. The
+// documents are read from mounted directories and URLs, split into chunks of words and inserted in
+// the vector store, which embeds them with its pinned embedding model.
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+)
+
+// maxDocumentSize bounds the size of a single document, larger documents are reported as failed.
+const maxDocumentSize = 16 << 20
+
+// Options configures an ingestion run.
+type Options struct {
+	// VectorStoreID is the vector store the chunks are inserted in.
+	VectorStoreID string
+	// ChunkSize is the number of words per chunk.
+	ChunkSize int
+	// ChunkOverlap is the number of words shared by consecutive chunks.
+	ChunkOverlap int
+	// Paths are files or directories to ingest. Directories are walked recursively, skipping hidden entries.
+	Paths []string
+	// URLs are documents fetched over HTTP.
+	URLs []string
+}
+
+// Result summarizes an ingestion run. It is written as the termination message of the job, which
+// the controller copies to the status of the vector store.
+type Result struct {
+	Documents       int32 `json:"documents"`
+	FailedDocuments int32 `json:"failedDocuments"`
+	Chunks          int32 `json:"chunks"`
+	// Message describes the first failure, if any.
+	Message string `json:"message,omitempty"`
+}
+
+// document is a document to ingest, read lazily.
+type document struct {
+	id   string
+	read func(ctx context.Context) ([]byte, error)
+}
+
+// Run ingests every document of opts. A failed document does not stop the run, the returned
+// result counts it and the error reports the first failure.
+func Run(ctx context.Context, server *llamastack.Client, httpClient *http.Client, opts Options) (Result, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+	var result Result
+	var firstErr error
+	fail := func(err error) {
+		result.FailedDocuments++
+		if firstErr == nil {
+			firstErr = err
+			result.Message = err.Error()
+		}
+	}
+
+	documents, err := collectDocuments(opts.Paths)
+	if err != nil {
+		fail(err)
+	}
+	for _, u := range opts.URLs {
+		documents = append(documents, urlDocument(httpClient, u))
+	}
+
+	for _, doc := range documents {
+		content, err := doc.read(ctx)
+		if err != nil {
+			fail(err)
+			continue
+		}
+
+		texts := Chunk(string(content), opts.ChunkSize, opts.ChunkOverlap)
+		chunks := make([]llamastack.Chunk, 0, len(texts))
+		for i, text := range texts {
+			chunks = append(chunks, llamastack.Chunk{
+				Content:  text,
+				Metadata: map[string]any{"document_id": doc.id, "chunk_index": i},
+			})
+		}
+		if len(chunks) > 0 {
+			if err := server.InsertChunks(ctx, opts.VectorStoreID, chunks); err != nil {
+				fail(fmt.Errorf("failed to insert document %s: %w", doc.id, err))
+				continue
+			}
+		}
+
+		result.Documents++
+		result.Chunks += int32(len(chunks)) //nolint:gosec // bounded by the document size
+		logger.Info("Ingested document", "document", doc.id, "chunks", len(chunks))
+	}
+	return result, firstErr
+}
+
+// Chunk splits text into chunks of size words, consecutive chunks sharing overlap words.
+func Chunk(text string, size, overlap int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 || size <= 0 {
+		return nil
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []string
+	for start := 0; ; start += size - overlap {
+		end := min(start+size, len(words))
+		chunks = append(chunks, strings.Join(words[start:end], " "))
+		if end == len(words) {
+			return chunks
+		}
+	}
+}
+
+// collectDocuments lists the regular files under paths. ConfigMap volumes link their keys to a
+// hidden ..data directory, so links are followed and hidden entries skipped.
+func collectDocuments(paths []string) ([]document, error) {
+	var documents []document
+	var errs []error
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				documents = append(documents, fileDocument(path))
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list documents in %s: %w", root, err))
+		}
+	}
+	return documents, errors.Join(errs...)
+}
+
+func fileDocument(path string) document {
+	return document{
+		id: path,
+		read: func(context.Context) ([]byte, error) {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open document %s: %w", path, err)
+			}
+			defer file.Close()
+			return readBounded(path, file)
+		},
+	}
+}
+
+func urlDocument(httpClient *http.Client, url string) document {
+	return document{
+		id: url,
+		read: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create request for document %s: %w", url, err)
+			}
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch document %s: %w", url, err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("failed to fetch document %s: returned status code %d", url, resp.StatusCode)
+			}
+			return readBounded(url, resp.Body)
+		},
+	}
+}
+
+func readBounded(id string, reader io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, maxDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document %s: %w", id, err)
+	}
+	if len(content) > maxDocumentSize {
+		return nil, fmt.Errorf("failed to read document %s: larger than %d bytes", id, maxDocumentSize)
+	}
+	return content, nil
+}
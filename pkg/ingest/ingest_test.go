@@ -0,0 +1,88 @@
+package ingest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/ingest"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunk(t *testing.T) {
+	text := "one two three four five six seven"
+
+	assert.Equal(t, []string{"one two three", "four five six", "seven"}, ingest.Chunk(text, 3, 0))
+	assert.Equal(t, []string{"one two three", "three four five", "five six seven"}, ingest.Chunk(text, 3, 1))
+	assert.Equal(t, []string{text}, ingest.Chunk(text, 10, 2))
+	assert.Empty(t, ingest.Chunk("  \n ", 3, 0))
+}
+
+func TestRun(t *testing.T) {
+	ctx := t.Context()
+	server := fake.NewServer()
+	defer server.Close()
+	_, err := server.Client().RegisterModel(ctx, llamastack.RegisterModelRequest{ModelID: "all-minilm", ModelType: llamastack.ModelTypeEmbedding})
+	require.NoError(t, err)
+	store, err := server.Client().CreateVectorStore(ctx, llamastack.CreateVectorStoreRequest{Name: "docs", EmbeddingModel: "all-minilm"})
+	require.NoError(t, err)
+
+	// Mimic a ConfigMap volume, whose keys link to a hidden directory.
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..data", "guide.md"), []byte(strings.Repeat("word ", 10)), 0o600))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "guide.md"), filepath.Join(dir, "guide.md")))
+
+	documents := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/faq.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("short answer"))
+	}))
+	defer documents.Close()
+
+	result, err := ingest.Run(ctx, server.Client(), documents.Client(), ingest.Options{
+		VectorStoreID: store.ID,
+		ChunkSize:     4,
+		Paths:         []string{dir},
+		URLs:          []string{documents.URL + "/faq.txt", documents.URL + "/missing.txt"},
+	})
+
+	require.Error(t, err, "a failed document is reported")
+	assert.Contains(t, err.Error(), "missing.txt")
+	assert.Equal(t, ingest.Result{Documents: 2, FailedDocuments: 1, Chunks: 4, Message: err.Error()}, result)
+
+	chunks := server.Chunks(store.ID)
+	require.Len(t, chunks, 4, "the hidden ..data entry is not ingested twice")
+	assert.Equal(t, filepath.Join(dir, "guide.md"), chunks[0].Metadata["document_id"])
+	assert.Equal(t, "short answer", chunks[3].Content)
+}
+
+func TestMainWritesResult(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	terminationLog := filepath.Join(t.TempDir(), "termination-log")
+
+	code := ingest.Main(context.Background(), []string{ //nolint:usetesting // Main takes the signal context of the process
+		"--server-url", server.URL(),
+		"--vector-store-id", "vs_missing",
+		"--termination-log", terminationLog,
+		"--url", server.URL() + "/v1/models",
+	})
+	assert.Equal(t, 1, code)
+
+	message, err := os.ReadFile(terminationLog)
+	require.NoError(t, err)
+	result, err := ingest.ParseResult(string(message))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result.FailedDocuments)
+	assert.Contains(t, result.Message, "not found")
+}
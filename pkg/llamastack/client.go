@@ -0,0 +1,121 @@
+// Package llamastack is a minimal client for the resource registration API of a llama-stack server.
+package llamastack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxErrorBodyLength bounds the response body included in errors.
+const maxErrorBodyLength = 512
+
+// ErrNotFound is returned when the requested resource is not registered in the server.
+var ErrNotFound = errors.New("resource not found")
+
+// APIError is returned when the server answers with an unexpected status code.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("failed to %s %s: returned status code %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// Client calls the API of a llama-stack server.
+type Client struct {
+	httpClient *http.Client
+	baseURL    *url.URL
+}
+
+// NewClient creates a client for the server at baseURL, e.g. http://my-llsd-service.ns.svc.cluster.local:8321.
+func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server URL %q: %w", baseURL, err)
+	}
+	return &Client{httpClient: httpClient, baseURL: parsed}, nil
+}
+
+// listResponse is the envelope of the llama-stack list endpoints.
+type listResponse[T any] struct {
+	Data []T `json:"data"`
+}
+
+// do sends a request with optional query parameters and JSON body, and decodes the JSON response
+// into out, if not nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	endpoint := c.baseURL.JoinPath(path)
+	endpoint.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s %s request: %w", method, path, err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), reader)
+	if err != nil {
+		return fmt.Errorf("failed to create %s %s request: %w", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make %s %s request: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s %s response: %w", method, path, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("failed to %s %s: %w", method, path, ErrNotFound)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		if len(respBody) > maxErrorBodyLength {
+			respBody = respBody[:maxErrorBodyLength]
+		}
+		return &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// list fetches every item of a list endpoint.
+func list[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	var response listResponse[T]
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// IgnoreNotFound returns nil if err is ErrNotFound.
+func IgnoreNotFound(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package llamastack_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *llamastack.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := llamastack.NewClient(server.Client(), server.URL)
+	require.NoError(t, err)
+	return client
+}
+
+func TestModels(t *testing.T) {
+	var registered map[string]any
+	var deletedPath string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/models":
+			_, _ = io.WriteString(w, `{"data":[{"identifier":"ollama/llama3.2:3b","provider_id":"ollama","provider_resource_id":"llama3.2:3b","model_type":"llm"}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/models":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&registered))
+			_, _ = io.WriteString(w, `{"identifier":"embed","provider_id":"sentence-transformers","model_type":"embedding"}`)
+		case r.Method == http.MethodDelete:
+			deletedPath = r.URL.Path
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	ctx := t.Context()
+
+	model, err := client.GetModel(ctx, "ollama/llama3.2:3b")
+	require.NoError(t, err)
+	assert.Equal(t, "llama3.2:3b", model.ProviderResourceID)
+
+	_, err = client.GetModel(ctx, "missing")
+	require.ErrorIs(t, err, llamastack.ErrNotFound)
+
+	model, err = client.RegisterModel(ctx, llamastack.RegisterModelRequest{
+		ModelID:    "embed",
+		ProviderID: "sentence-transformers",
+		ModelType:  llamastack.ModelTypeEmbedding,
+		Metadata:   map[string]any{"embedding_dimension": 384},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "embed", model.Identifier)
+	assert.Equal(t, map[string]any{
+		"model_id":    "embed",
+		"provider_id": "sentence-transformers",
+		"model_type":  "embedding",
+		"metadata":    map[string]any{"embedding_dimension": float64(384)},
+	}, registered)
+
+	err = client.UnregisterModel(ctx, "ollama/llama3.2:3b")
+	require.ErrorIs(t, err, llamastack.ErrNotFound)
+	require.NoError(t, llamastack.IgnoreNotFound(err))
+	assert.Equal(t, "/v1/models/ollama/llama3.2:3b", deletedPath)
+}
+
+func TestAPIError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"detail":"Provider ollama not found"}`)
+	})
+
+	_, err := client.RegisterModel(t.Context(), llamastack.RegisterModelRequest{ModelID: "m", ProviderID: "ollama"})
+	var apiErr *llamastack.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Contains(t, err.Error(), "Provider ollama not found")
+}
+
+func TestToolGroups(t *testing.T) {
+	var registered map[string]any
+	var toolsQuery string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/toolgroups":
+			_, _ = io.WriteString(w, `{"data":[{"identifier":"mcp::github","provider_id":"model-context-protocol","mcp_endpoint":{"uri":"http://github-mcp:8000/sse"}}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/toolgroups":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&registered))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/tools":
+			toolsQuery = r.URL.RawQuery
+			_, _ = io.WriteString(w, `{"data":[{"identifier":"create_issue","toolgroup_id":"mcp::github"}]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	ctx := t.Context()
+
+	toolGroup, err := client.GetToolGroup(ctx, "mcp::github")
+	require.NoError(t, err)
+	assert.Equal(t, "http://github-mcp:8000/sse", toolGroup.MCPEndpoint.URI)
+
+	require.NoError(t, client.RegisterToolGroup(ctx, llamastack.RegisterToolGroupRequest{
+		ToolGroupID: "mcp::docs",
+		ProviderID:  llamastack.ProviderIDModelContextProtocol,
+		MCPEndpoint: &llamastack.URL{URI: "http://docs-mcp:8000/sse"},
+	}))
+	assert.Equal(t, map[string]any{
+		"toolgroup_id": "mcp::docs",
+		"provider_id":  "model-context-protocol",
+		"mcp_endpoint": map[string]any{"uri": "http://docs-mcp:8000/sse"},
+	}, registered)
+
+	tools, err := client.ListTools(ctx, "mcp::github")
+	require.NoError(t, err)
+	assert.Equal(t, "toolgroup_id=mcp%3A%3Agithub", toolsQuery)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "create_issue", tools[0].Identifier)
+}
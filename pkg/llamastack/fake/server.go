@@ -0,0 +1,392 @@
+// Package fake provides an in-memory llama-stack server implementing the subset of the API used by
+// the operator, for tests of the controllers and the ingestion job.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/llamastack"
+)
+
+// Server is an in-memory llama-stack server. Its state is only changed through the API, except by
+// Restart which drops it like a server without persistent storage.
+type Server struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	models       map[string]llamastack.Model
+	vectorStores map[string]llamastack.VectorStore
+	chunks       map[string][]llamastack.Chunk
+	toolGroups   map[string]llamastack.ToolGroup
+	shields      map[string]llamastack.Shield
+	// mcpTools are the tools of the reachable MCP servers by endpoint, they survive a restart
+	mcpTools map[string][]string
+	nextID   int
+}
+
+// NewServer starts a fake server. It is closed with Close.
+func NewServer() *Server {
+	s := &Server{mcpTools: map[string][]string{}}
+	s.reset()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/models", s.listModels)
+	mux.HandleFunc("POST /v1/models", s.registerModel)
+	mux.HandleFunc("DELETE /v1/models/{id...}", s.unregisterModel)
+	mux.HandleFunc("GET /v1/vector_stores", s.listVectorStores)
+	mux.HandleFunc("POST /v1/vector_stores", s.createVectorStore)
+	mux.HandleFunc("GET /v1/vector_stores/{id}", s.getVectorStore)
+	mux.HandleFunc("DELETE /v1/vector_stores/{id}", s.deleteVectorStore)
+	mux.HandleFunc("POST /v1/vector-io/insert", s.insertChunks)
+	mux.HandleFunc("GET /v1/toolgroups", s.listToolGroups)
+	mux.HandleFunc("POST /v1/toolgroups", s.registerToolGroup)
+	mux.HandleFunc("DELETE /v1/toolgroups/{id...}", s.unregisterToolGroup)
+	mux.HandleFunc("GET /v1/tools", s.listTools)
+	mux.HandleFunc("GET /v1/shields", s.listShields)
+	mux.HandleFunc("POST /v1/shields", s.registerShield)
+	mux.HandleFunc("DELETE /v1/shields/{id...}", s.unregisterShield)
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the base URL of the server.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// HTTPClient returns a client sending every request to the server whatever its host, so code
+// building in-cluster service URLs can be pointed at the fake server.
+func (s *Server) HTTPClient() *http.Client {
+	addr := s.server.Listener.Addr().String()
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+// Client returns an API client of the server.
+func (s *Server) Client() *llamastack.Client {
+	client, err := llamastack.NewClient(s.HTTPClient(), s.URL())
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Restart drops every registered resource.
+func (s *Server) Restart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+}
+
+func (s *Server) reset() {
+	s.models = map[string]llamastack.Model{}
+	s.vectorStores = map[string]llamastack.VectorStore{}
+	s.chunks = map[string][]llamastack.Chunk{}
+	s.toolGroups = map[string]llamastack.ToolGroup{}
+	s.shields = map[string]llamastack.Shield{}
+}
+
+// SetMCPTools makes the MCP server at endpoint reachable with the given tools, or unreachable when
+// tools is nil.
+func (s *Server) SetMCPTools(endpoint string, tools []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tools == nil {
+		delete(s.mcpTools, endpoint)
+		return
+	}
+	s.mcpTools[endpoint] = tools
+}
+
+// Models returns the registered models sorted by identifier.
+func (s *Server) Models() []llamastack.Model {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.models)
+}
+
+// VectorStores returns the vector stores sorted by ID.
+func (s *Server) VectorStores() []llamastack.VectorStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.vectorStores)
+}
+
+// Chunks returns the chunks inserted in a vector store.
+func (s *Server) Chunks(vectorStoreID string) []llamastack.Chunk {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]llamastack.Chunk(nil), s.chunks[vectorStoreID]...)
+}
+
+// ToolGroups returns the registered tool groups sorted by identifier.
+func (s *Server) ToolGroups() []llamastack.ToolGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.toolGroups)
+}
+
+// Shields returns the registered shields sorted by identifier.
+func (s *Server) Shields() []llamastack.Shield {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.shields)
+}
+
+func (s *Server) listModels(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{"data": s.Models()})
+}
+
+func (s *Server) registerModel(w http.ResponseWriter, req *http.Request) {
+	var body llamastack.RegisterModelRequest
+	if !readJSON(w, req, &body) {
+		return
+	}
+	model := llamastack.Model{
+		Identifier:         body.ModelID,
+		ProviderID:         body.ProviderID,
+		ProviderResourceID: body.ProviderModelID,
+		ModelType:          body.ModelType,
+		Metadata:           body.Metadata,
+	}
+	if model.ProviderResourceID == "" {
+		model.ProviderResourceID = body.ModelID
+	}
+	if model.ModelType == "" {
+		model.ModelType = llamastack.ModelTypeLLM
+	}
+
+	s.mu.Lock()
+	s.models[model.Identifier] = model
+	s.mu.Unlock()
+	writeJSON(w, model)
+}
+
+func (s *Server) unregisterModel(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := req.PathValue("id")
+	if _, ok := s.models[id]; !ok {
+		http.Error(w, fmt.Sprintf("model %s not found", id), http.StatusNotFound)
+		return
+	}
+	delete(s.models, id)
+}
+
+func (s *Server) listVectorStores(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{"data": s.VectorStores()})
+}
+
+func (s *Server) createVectorStore(w http.ResponseWriter, req *http.Request) {
+	var body llamastack.CreateVectorStoreRequest
+	if !readJSON(w, req, &body) {
+		return
+	}
+	if body.EmbeddingModel != "" {
+		s.mu.Lock()
+		_, known := s.models[body.EmbeddingModel]
+		s.mu.Unlock()
+		if !known {
+			http.Error(w, fmt.Sprintf("embedding model %s not found", body.EmbeddingModel), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	store := llamastack.VectorStore{
+		ID:       fmt.Sprintf("vs_%d", s.nextID),
+		Name:     body.Name,
+		Status:   "completed",
+		Metadata: body.Metadata,
+	}
+	s.vectorStores[store.ID] = store
+	s.mu.Unlock()
+	writeJSON(w, store)
+}
+
+func (s *Server) getVectorStore(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	store, ok := s.vectorStores[req.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "vector store not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, store)
+}
+
+func (s *Server) deleteVectorStore(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := req.PathValue("id")
+	if _, ok := s.vectorStores[id]; !ok {
+		http.Error(w, "vector store not found", http.StatusNotFound)
+		return
+	}
+	delete(s.vectorStores, id)
+	delete(s.chunks, id)
+	writeJSON(w, map[string]any{"id": id, "object": "vector_store.deleted", "deleted": true})
+}
+
+func (s *Server) insertChunks(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		VectorDBID string             `json:"vector_db_id"`
+		Chunks     []llamastack.Chunk `json:"chunks"`
+	}
+	if !readJSON(w, req, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.vectorStores[body.VectorDBID]; !ok {
+		http.Error(w, "vector store not found", http.StatusNotFound)
+		return
+	}
+	for _, chunk := range body.Chunks {
+		if _, ok := chunk.Metadata["document_id"]; !ok {
+			http.Error(w, "chunk metadata must contain document_id", http.StatusBadRequest)
+			return
+		}
+	}
+	s.chunks[body.VectorDBID] = append(s.chunks[body.VectorDBID], body.Chunks...)
+}
+
+func (s *Server) listToolGroups(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{"data": s.ToolGroups()})
+}
+
+func (s *Server) registerToolGroup(w http.ResponseWriter, req *http.Request) {
+	var body llamastack.RegisterToolGroupRequest
+	if !readJSON(w, req, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.toolGroups[body.ToolGroupID]; ok {
+		http.Error(w, fmt.Sprintf("tool group %s already exists", body.ToolGroupID), http.StatusBadRequest)
+		return
+	}
+	s.toolGroups[body.ToolGroupID] = llamastack.ToolGroup{
+		Identifier:  body.ToolGroupID,
+		ProviderID:  body.ProviderID,
+		MCPEndpoint: body.MCPEndpoint,
+		Args:        body.Args,
+	}
+}
+
+func (s *Server) unregisterToolGroup(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := req.PathValue("id")
+	if _, ok := s.toolGroups[id]; !ok {
+		http.Error(w, fmt.Sprintf("tool group %s not found", id), http.StatusNotFound)
+		return
+	}
+	delete(s.toolGroups, id)
+}
+
+func (s *Server) listTools(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := req.URL.Query().Get("toolgroup_id")
+	toolGroup, ok := s.toolGroups[id]
+	if !ok {
+		http.Error(w, fmt.Sprintf("tool group %s not found", id), http.StatusNotFound)
+		return
+	}
+
+	var endpoint string
+	if toolGroup.MCPEndpoint != nil {
+		endpoint = toolGroup.MCPEndpoint.URI
+	}
+	names, reachable := s.mcpTools[endpoint]
+	if !reachable {
+		http.Error(w, fmt.Sprintf("failed to connect to MCP server at %s", endpoint), http.StatusInternalServerError)
+		return
+	}
+	tools := make([]llamastack.Tool, 0, len(names))
+	for _, name := range names {
+		tools = append(tools, llamastack.Tool{Identifier: name, ToolGroupID: id})
+	}
+	writeJSON(w, map[string]any{"data": tools})
+}
+
+func (s *Server) listShields(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{"data": s.Shields()})
+}
+
+func (s *Server) registerShield(w http.ResponseWriter, req *http.Request) {
+	var body llamastack.RegisterShieldRequest
+	if !readJSON(w, req, &body) {
+		return
+	}
+	shield := llamastack.Shield{
+		Identifier:         body.ShieldID,
+		ProviderID:         body.ProviderID,
+		ProviderResourceID: body.ProviderShieldID,
+		Params:             body.Params,
+	}
+	if shield.ProviderResourceID == "" {
+		shield.ProviderResourceID = body.ShieldID
+	}
+
+	s.mu.Lock()
+	s.shields[shield.Identifier] = shield
+	s.mu.Unlock()
+	writeJSON(w, shield)
+}
+
+func (s *Server) unregisterShield(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := req.PathValue("id")
+	if _, ok := s.shields[id]; !ok {
+		http.Error(w, fmt.Sprintf("shield %s not found", id), http.StatusNotFound)
+		return
+	}
+	delete(s.shields, id)
+}
+
+func readJSON(w http.ResponseWriter, req *http.Request, out any) bool {
+	if err := json.NewDecoder(req.Body).Decode(out); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func sortedValues[T any](items map[string]T) []T {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	values := make([]T, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, items[key])
+	}
+	return values
+}
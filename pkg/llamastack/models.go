@@ -0,0 +1,67 @@
+package llamastack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const modelsPath = "/v1/models"
+
+// Model types supported by llama-stack.
+const (
+	ModelTypeLLM       = "llm"
+	ModelTypeEmbedding = "embedding"
+)
+
+// Model is a model registered in the server.
+type Model struct {
+	Identifier         string         `json:"identifier"`
+	ProviderID         string         `json:"provider_id"`
+	ProviderResourceID string         `json:"provider_resource_id"`
+	ModelType          string         `json:"model_type"`
+	Metadata           map[string]any `json:"metadata,omitempty"`
+}
+
+// RegisterModelRequest is the body of a model registration.
+type RegisterModelRequest struct {
+	ModelID         string         `json:"model_id"`
+	ProviderModelID string         `json:"provider_model_id,omitempty"`
+	ProviderID      string         `json:"provider_id,omitempty"`
+	ModelType       string         `json:"model_type,omitempty"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+}
+
+// ListModels returns the models registered in the server.
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	return list[Model](ctx, c, modelsPath, nil)
+}
+
+// GetModel returns the registered model with the given identifier, or ErrNotFound. The model is
+// looked up in the list, since servers disagree on how a missing model is reported by GET.
+func (c *Client) GetModel(ctx context.Context, modelID string) (*Model, error) {
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range models {
+		if models[i].Identifier == modelID {
+			return &models[i], nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get model %s: %w", modelID, ErrNotFound)
+}
+
+// RegisterModel registers a model and returns it as stored by the server.
+func (c *Client) RegisterModel(ctx context.Context, req RegisterModelRequest) (*Model, error) {
+	model := &Model{}
+	if err := c.do(ctx, http.MethodPost, modelsPath, nil, req, model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// UnregisterModel removes a model. Model identifiers may contain slashes, which the server accepts in the path.
+func (c *Client) UnregisterModel(ctx context.Context, modelID string) error {
+	return c.do(ctx, http.MethodDelete, modelsPath+"/"+modelID, nil, nil, nil)
+}
@@ -0,0 +1,59 @@
+package llamastack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const shieldsPath = "/v1/shields"
+
+// Shield is a safety shield registered in the server.
+type Shield struct {
+	Identifier         string         `json:"identifier"`
+	ProviderID         string         `json:"provider_id"`
+	ProviderResourceID string         `json:"provider_resource_id"`
+	Params             map[string]any `json:"params,omitempty"`
+}
+
+// RegisterShieldRequest is the body of a shield registration.
+type RegisterShieldRequest struct {
+	ShieldID         string         `json:"shield_id"`
+	ProviderShieldID string         `json:"provider_shield_id,omitempty"`
+	ProviderID       string         `json:"provider_id,omitempty"`
+	Params           map[string]any `json:"params,omitempty"`
+}
+
+// ListShields returns the shields registered in the server.
+func (c *Client) ListShields(ctx context.Context) ([]Shield, error) {
+	return list[Shield](ctx, c, shieldsPath, nil)
+}
+
+// GetShield returns the registered shield with the given identifier, or ErrNotFound. Like models,
+// shields are looked up in the list.
+func (c *Client) GetShield(ctx context.Context, shieldID string) (*Shield, error) {
+	shields, err := c.ListShields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range shields {
+		if shields[i].Identifier == shieldID {
+			return &shields[i], nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get shield %s: %w", shieldID, ErrNotFound)
+}
+
+// RegisterShield registers a shield and returns it as stored by the server.
+func (c *Client) RegisterShield(ctx context.Context, req RegisterShieldRequest) (*Shield, error) {
+	shield := &Shield{}
+	if err := c.do(ctx, http.MethodPost, shieldsPath, nil, req, shield); err != nil {
+		return nil, err
+	}
+	return shield, nil
+}
+
+// UnregisterShield removes a shield. Shield identifiers may contain slashes, like model identifiers.
+func (c *Client) UnregisterShield(ctx context.Context, shieldID string) error {
+	return c.do(ctx, http.MethodDelete, shieldsPath+"/"+shieldID, nil, nil, nil)
+}
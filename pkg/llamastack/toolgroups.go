@@ -0,0 +1,80 @@
+package llamastack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	toolGroupsPath = "/v1/toolgroups"
+	toolsPath      = "/v1/tools"
+)
+
+// ProviderIDModelContextProtocol is the tool runtime provider of MCP tool groups.
+const ProviderIDModelContextProtocol = "model-context-protocol"
+
+// URL is a URL as encoded by the llama-stack API.
+type URL struct {
+	URI string `json:"uri"`
+}
+
+// ToolGroup is a tool group registered in the server.
+type ToolGroup struct {
+	Identifier  string         `json:"identifier"`
+	ProviderID  string         `json:"provider_id"`
+	MCPEndpoint *URL           `json:"mcp_endpoint,omitempty"`
+	Args        map[string]any `json:"args,omitempty"`
+}
+
+// RegisterToolGroupRequest is the body of a tool group registration.
+type RegisterToolGroupRequest struct {
+	ToolGroupID string         `json:"toolgroup_id"`
+	ProviderID  string         `json:"provider_id"`
+	MCPEndpoint *URL           `json:"mcp_endpoint,omitempty"`
+	Args        map[string]any `json:"args,omitempty"`
+}
+
+// Tool is a tool of a registered tool group.
+type Tool struct {
+	Identifier  string `json:"identifier"`
+	ToolGroupID string `json:"toolgroup_id,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ListToolGroups returns the tool groups registered in the server.
+func (c *Client) ListToolGroups(ctx context.Context) ([]ToolGroup, error) {
+	return list[ToolGroup](ctx, c, toolGroupsPath, nil)
+}
+
+// GetToolGroup returns the registered tool group with the given identifier, or ErrNotFound. Like
+// models, tool groups are looked up in the list.
+func (c *Client) GetToolGroup(ctx context.Context, toolGroupID string) (*ToolGroup, error) {
+	toolGroups, err := c.ListToolGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range toolGroups {
+		if toolGroups[i].Identifier == toolGroupID {
+			return &toolGroups[i], nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get tool group %s: %w", toolGroupID, ErrNotFound)
+}
+
+// RegisterToolGroup registers a tool group.
+func (c *Client) RegisterToolGroup(ctx context.Context, req RegisterToolGroupRequest) error {
+	return c.do(ctx, http.MethodPost, toolGroupsPath, nil, req, nil)
+}
+
+// UnregisterToolGroup removes a tool group.
+func (c *Client) UnregisterToolGroup(ctx context.Context, toolGroupID string) error {
+	return c.do(ctx, http.MethodDelete, toolGroupsPath+"/"+toolGroupID, nil, nil, nil)
+}
+
+// ListTools returns the tools of a tool group. For MCP tool groups the server lists the tools of
+// the MCP server, so this also checks that the server is reachable.
+func (c *Client) ListTools(ctx context.Context, toolGroupID string) ([]Tool, error) {
+	return list[Tool](ctx, c, toolsPath, url.Values{"toolgroup_id": []string{toolGroupID}})
+}
@@ -0,0 +1,75 @@
+package llamastack
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	vectorStoresPath = "/v1/vector_stores"
+	vectorIOPath     = "/v1/vector-io/insert"
+)
+
+// VectorStore is a vector store of the server, following the OpenAI vector stores API.
+type VectorStore struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Status     string         `json:"status,omitempty"`
+	UsageBytes int64          `json:"usage_bytes,omitempty"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// CreateVectorStoreRequest is the body of a vector store creation. The embedding model and provider
+// are llama-stack extensions of the OpenAI API.
+type CreateVectorStoreRequest struct {
+	Name               string         `json:"name"`
+	EmbeddingModel     string         `json:"embedding_model,omitempty"`
+	EmbeddingDimension int32          `json:"embedding_dimension,omitempty"`
+	ProviderID         string         `json:"provider_id,omitempty"`
+	Metadata           map[string]any `json:"metadata,omitempty"`
+}
+
+// Chunk is a piece of a document inserted in a vector store. The metadata must hold a document_id.
+type Chunk struct {
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// insertChunksRequest is the body of a chunk insertion.
+type insertChunksRequest struct {
+	VectorDBID string  `json:"vector_db_id"`
+	Chunks     []Chunk `json:"chunks"`
+}
+
+// ListVectorStores returns the vector stores of the server.
+func (c *Client) ListVectorStores(ctx context.Context) ([]VectorStore, error) {
+	return list[VectorStore](ctx, c, vectorStoresPath, nil)
+}
+
+// GetVectorStore returns the vector store with the given ID, or ErrNotFound.
+func (c *Client) GetVectorStore(ctx context.Context, id string) (*VectorStore, error) {
+	store := &VectorStore{}
+	if err := c.do(ctx, http.MethodGet, vectorStoresPath+"/"+id, nil, nil, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// CreateVectorStore creates a vector store and returns it with the ID assigned by the server.
+func (c *Client) CreateVectorStore(ctx context.Context, req CreateVectorStoreRequest) (*VectorStore, error) {
+	store := &VectorStore{}
+	if err := c.do(ctx, http.MethodPost, vectorStoresPath, nil, req, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// DeleteVectorStore deletes a vector store and its content.
+func (c *Client) DeleteVectorStore(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, vectorStoresPath+"/"+id, nil, nil, nil)
+}
+
+// InsertChunks embeds the chunks with the embedding model of the vector store and inserts them.
+func (c *Client) InsertChunks(ctx context.Context, vectorStoreID string, chunks []Chunk) error {
+	return c.do(ctx, http.MethodPost, vectorIOPath, nil, insertChunksRequest{VectorDBID: vectorStoreID, Chunks: chunks}, nil)
+}
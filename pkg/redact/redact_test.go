@@ -0,0 +1,73 @@
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactJSON(t *testing.T) {
+	redactor, err := redact.New(nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "top level keys",
+			input:    `{"api_key":"sk-123","url":"http://ollama:11434","max_tokens":0}`,
+			expected: `{"api_key":"<redacted>","max_tokens":0,"url":"http://ollama:11434"}`,
+		},
+		{
+			name:     "nested objects and arrays",
+			input:    `{"kvstore":{"type":"postgres","Password":"hunter2","replicas":[{"host":"db","auth":{"accessToken":"abc"}}]}}`,
+			expected: `{"kvstore":{"Password":"<redacted>","replicas":[{"auth":{"accessToken":"<redacted>"},"host":"db"}],"type":"postgres"}}`,
+		},
+		{
+			name:     "sensitive keys holding objects are replaced entirely",
+			input:    `{"credentials":{"user":"admin","key":"value"}}`,
+			expected: `{"credentials":"<redacted>"}`,
+		},
+		{
+			name:     "null and boolean values are kept",
+			input:    `{"api_key":null,"tls_password_required":true}`,
+			expected: `{"api_key":null,"tls_password_required":true}`,
+		},
+		{
+			name:     "credentials in URLs",
+			input:    `{"db":"postgres://admin:s3cret@db:5432/llama?sslmode=require&access_token=abc","list":["https://user@example.com/path"]}`,
+			expected: `{"db":"postgres://<redacted>@db:5432/llama?sslmode=require&access_token=<redacted>","list":["https://<redacted>@example.com/path"]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			redacted, err := redactor.JSON([]byte(tc.input))
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(redacted))
+		})
+	}
+}
+
+func TestRedactCustomPatterns(t *testing.T) {
+	redactor, err := redact.New([]string{`^endpoint$`, `license`})
+	require.NoError(t, err)
+
+	redacted, err := redactor.JSON([]byte(`{"endpoint":"http://internal","license_id":"L-1","api_key":"kept","nested":{"Endpoint":"x"}}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"endpoint":"<redacted>","license_id":"<redacted>","api_key":"kept","nested":{"Endpoint":"<redacted>"}}`, string(redacted))
+}
+
+func TestRedactErrors(t *testing.T) {
+	_, err := redact.New([]string{`(unclosed`})
+	require.ErrorContains(t, err, "failed to compile redaction pattern")
+
+	redactor, err := redact.New(nil)
+	require.NoError(t, err)
+	_, err = redactor.JSON([]byte(`{"truncated":`))
+	require.Error(t, err)
+}
@@ -0,0 +1,224 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchLimit bounds how far Next and Prev look for a matching time. Five years covers
+// every valid expression, including ones that only match on February 29th.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// cronFieldCount is the number of fields in a standard cron expression.
+const cronFieldCount = 5
+
+// bounds is the range of values accepted by a cron field.
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds     = bounds{name: "minute", min: 0, max: 59}
+	hourBounds       = bounds{name: "hour", min: 0, max: 23}
+	dayOfMonthBounds = bounds{name: "day of month", min: 1, max: 31}
+	monthBounds      = bounds{name: "month", min: 1, max: 12}
+	// Day of week accepts 7 as an alias for Sunday.
+	dayOfWeekBounds = bounds{name: "day of week", min: 0, max: 7}
+)
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month, month and day of week.
+type Cron struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// Standard cron matches a day if either day field matches, unless one of them is a wildcard.
+	dayOfMonthWildcard bool
+	dayOfWeekWildcard  bool
+}
+
+// ParseCron parses a standard five-field cron expression. Each field accepts "*",
+// single values, ranges ("1-5"), steps ("*/15", "0-30/10") and comma separated lists.
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != cronFieldCount {
+		return nil, fmt.Errorf("failed to parse cron expression %q: expected %d fields, got %d", expr, cronFieldCount, len(fields))
+	}
+
+	cron := &Cron{
+		dayOfMonthWildcard: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekWildcard:  strings.HasPrefix(fields[4], "*"),
+	}
+	targets := []struct {
+		bits   *uint64
+		bounds bounds
+	}{
+		{&cron.minute, minuteBounds},
+		{&cron.hour, hourBounds},
+		{&cron.dayOfMonth, dayOfMonthBounds},
+		{&cron.month, monthBounds},
+		{&cron.dayOfWeek, dayOfWeekBounds},
+	}
+	for i, target := range targets {
+		bits, err := parseField(fields[i], target.bounds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cron expression %q: %w", expr, err)
+		}
+		*target.bits = bits
+	}
+
+	// Fold Sunday as 7 into Sunday as 0.
+	if cron.dayOfWeek&(1<<7) != 0 {
+		cron.dayOfWeek |= 1
+	}
+	return cron, nil
+}
+
+// parseField parses a comma separated cron field into a bit set of matching values.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		partBits, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+// parseRange parses a single "*", "a", "a-b" term with an optional "/step".
+func parseRange(term string, b bounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(term, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("failed to parse %s step %q", b.name, stepPart)
+		}
+	}
+
+	var start, end int
+	switch {
+	case rangePart == "*":
+		start, end = b.min, b.max
+	case strings.Contains(rangePart, "-"):
+		low, high, _ := strings.Cut(rangePart, "-")
+		var err error
+		if start, err = parseValue(low, b); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(high, b); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("failed to parse %s range %q: start is after end", b.name, rangePart)
+		}
+	default:
+		var err error
+		if start, err = parseValue(rangePart, b); err != nil {
+			return 0, err
+		}
+		end = start
+		// "a/n" means every n starting at a.
+		if hasStep {
+			end = b.max
+		}
+	}
+
+	var bits uint64
+	for v := start; v <= end; v += step {
+		bits |= 1 << uint(v) //nolint:gosec // v is within the field bounds
+	}
+	return bits, nil
+}
+
+// parseValue parses a single numeric value and checks it against the field bounds.
+func parseValue(value string, b bounds) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s value %q", b.name, value)
+	}
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("failed to parse %s value %d: must be between %d and %d", b.name, v, b.min, b.max)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t that matches the expression, in t's location.
+func (c *Cron) Next(t time.Time) (time.Time, error) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		var next time.Time
+		switch {
+		case !has(c.month, int(t.Month())):
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(c.hour, t.Hour()):
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(c.minute, t.Minute()):
+			next = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+		// Daylight saving transitions can normalize a wall clock time backwards.
+		if !next.After(t) {
+			next = t.Add(time.Minute)
+		}
+		t = next
+	}
+	return time.Time{}, errors.New("failed to find the next matching time")
+}
+
+// Prev returns the latest time at or before t that matches the expression, in t's location.
+func (c *Cron) Prev(t time.Time) (time.Time, error) {
+	t = t.Truncate(time.Minute)
+	limit := t.Add(-searchLimit)
+
+	for t.After(limit) {
+		var prev time.Time
+		switch {
+		case !has(c.month, int(t.Month())):
+			prev = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !c.matchesDay(t):
+			prev = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !has(c.hour, t.Hour()):
+			prev = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(-time.Minute)
+		case !has(c.minute, t.Minute()):
+			prev = t.Add(-time.Minute)
+		default:
+			return t, nil
+		}
+		// Daylight saving transitions can normalize a wall clock time forwards.
+		if !prev.Before(t) {
+			prev = t.Add(-time.Minute)
+		}
+		t = prev
+	}
+	return time.Time{}, errors.New("failed to find the previous matching time")
+}
+
+// matchesDay applies the standard cron rule for combining day of month and day of week.
+func (c *Cron) matchesDay(t time.Time) bool {
+	domMatch := has(c.dayOfMonth, t.Day())
+	dowMatch := has(c.dayOfWeek, int(t.Weekday()))
+	if c.dayOfMonthWildcard || c.dayOfWeekWildcard {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// has reports whether value is set in the bit set.
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0 //nolint:gosec // value comes from time fields
+}
@@ -0,0 +1,97 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	testCases := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "wildcards", expr: "* * * * *"},
+		{name: "business hours", expr: "0 8 * * 1-5"},
+		{name: "lists and steps", expr: "*/15 0,12 1-31/2 1-12 0-6"},
+		{name: "sunday as seven", expr: "0 0 * * 7"},
+		{name: "too few fields", expr: "0 8 * *", wantErr: true},
+		{name: "value out of range", expr: "60 8 * * *", wantErr: true},
+		{name: "inverted range", expr: "0 18-8 * * *", wantErr: true},
+		{name: "invalid step", expr: "*/0 * * * *", wantErr: true},
+		{name: "not a number", expr: "0 8 * JAN *", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := schedule.ParseCron(tc.expr)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCronNextAndPrev(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, time.March, 12, 10, 30, 15, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		expr         string
+		expectedNext time.Time
+		expectedPrev time.Time
+	}{
+		{
+			name:         "weekday mornings",
+			expr:         "0 8 * * 1-5",
+			expectedNext: time.Date(2025, time.March, 13, 8, 0, 0, 0, time.UTC),
+			expectedPrev: time.Date(2025, time.March, 12, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "weekend only",
+			expr:         "0 0 * * 0,6",
+			expectedNext: time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+			expectedPrev: time.Date(2025, time.March, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "every quarter hour",
+			expr:         "*/15 * * * *",
+			expectedNext: time.Date(2025, time.March, 12, 10, 45, 0, 0, time.UTC),
+			expectedPrev: time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "day of month or day of week",
+			expr:         "0 0 1 * 5",
+			expectedNext: time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC),
+			expectedPrev: time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "leap day",
+			expr:         "0 0 29 2 *",
+			expectedNext: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+			expectedPrev: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cron, err := schedule.ParseCron(tc.expr)
+			require.NoError(t, err)
+
+			next, err := cron.Next(now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedNext, next)
+
+			prev, err := cron.Prev(now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPrev, prev)
+		})
+	}
+}
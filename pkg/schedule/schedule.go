@@ -0,0 +1,77 @@
+// Package schedule evaluates the replica schedule of a LlamaStackDistribution.
+package schedule
+
+import (
+	"fmt"
+	"time"
+	// Embed the time zone database so schedules work on images without tzdata.
+	_ "time/tzdata"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+)
+
+// Evaluation is the result of evaluating schedule entries at a point in time.
+type Evaluation struct {
+	// Active is the entry that fired most recently, nil if none has fired yet.
+	Active *llamav1alpha1.ScheduleEntry
+	// ActiveSince is when the active entry fired.
+	ActiveSince time.Time
+	// NextTransition is the earliest time any entry fires next, zero if none does.
+	NextTransition time.Time
+	// Errors lists the entries that could not be evaluated.
+	Errors []error
+}
+
+// EntryName returns the name used to report a schedule entry.
+func EntryName(entry *llamav1alpha1.ScheduleEntry) string {
+	if entry.Name != "" {
+		return entry.Name
+	}
+	return entry.Cron
+}
+
+// Evaluate determines which schedule entry is active at now. The entry that fired most
+// recently wins; when several fire at the same time, the one listed last wins.
+// Entries that fail to parse are reported in Errors and otherwise ignored.
+func Evaluate(entries []llamav1alpha1.ScheduleEntry, now time.Time) Evaluation {
+	var evaluation Evaluation
+
+	for i := range entries {
+		entry := &entries[i]
+		cron, location, err := parseEntry(entry)
+		if err != nil {
+			evaluation.Errors = append(evaluation.Errors, err)
+			continue
+		}
+
+		local := now.In(location)
+		if prev, err := cron.Prev(local); err == nil && !prev.Before(evaluation.ActiveSince) {
+			evaluation.Active = entry
+			evaluation.ActiveSince = prev
+		}
+		if next, err := cron.Next(local); err == nil &&
+			(evaluation.NextTransition.IsZero() || next.Before(evaluation.NextTransition)) {
+			evaluation.NextTransition = next
+		}
+	}
+
+	return evaluation
+}
+
+// parseEntry parses the cron expression and time zone of a schedule entry.
+func parseEntry(entry *llamav1alpha1.ScheduleEntry) (*Cron, *time.Location, error) {
+	location := time.UTC
+	if entry.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(entry.Timezone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load time zone of schedule entry %s: %w", EntryName(entry), err)
+		}
+	}
+
+	cron, err := ParseCron(entry.Cron)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse schedule entry %s: %w", EntryName(entry), err)
+	}
+	return cron, location, nil
+}
@@ -0,0 +1,87 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func businessHours(timezone string) []llamav1alpha1.ScheduleEntry {
+	return []llamav1alpha1.ScheduleEntry{
+		{Name: "business-hours", Cron: "0 8 * * 1-5", Timezone: timezone, Replicas: 3},
+		{Name: "off-hours", Cron: "0 18 * * 1-5", Timezone: timezone, Replicas: 0},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Run("active entry is the one that fired last", func(t *testing.T) {
+		// Wednesday 10:30 UTC
+		now := time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+
+		evaluation := schedule.Evaluate(businessHours(""), now)
+
+		require.NotNil(t, evaluation.Active)
+		assert.Equal(t, "business-hours", evaluation.Active.Name)
+		assert.Equal(t, time.Date(2025, time.March, 12, 8, 0, 0, 0, time.UTC), evaluation.ActiveSince.UTC())
+		assert.Equal(t, time.Date(2025, time.March, 12, 18, 0, 0, 0, time.UTC), evaluation.NextTransition.UTC())
+		assert.Empty(t, evaluation.Errors)
+	})
+
+	t.Run("off-hours entry stays active over the weekend", func(t *testing.T) {
+		// Sunday noon UTC
+		now := time.Date(2025, time.March, 16, 12, 0, 0, 0, time.UTC)
+
+		evaluation := schedule.Evaluate(businessHours(""), now)
+
+		require.NotNil(t, evaluation.Active)
+		assert.Equal(t, "off-hours", evaluation.Active.Name)
+		assert.Equal(t, time.Date(2025, time.March, 17, 8, 0, 0, 0, time.UTC), evaluation.NextTransition.UTC())
+	})
+
+	t.Run("entries are evaluated in their time zone", func(t *testing.T) {
+		// 10:30 UTC is 06:30 in New York, before business hours start there
+		now := time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+
+		evaluation := schedule.Evaluate(businessHours("America/New_York"), now)
+
+		require.NotNil(t, evaluation.Active)
+		assert.Equal(t, "off-hours", evaluation.Active.Name)
+		assert.Equal(t, time.Date(2025, time.March, 12, 12, 0, 0, 0, time.UTC), evaluation.NextTransition.UTC())
+	})
+
+	t.Run("invalid entries are reported and ignored", func(t *testing.T) {
+		now := time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+		entries := append(businessHours(""),
+			llamav1alpha1.ScheduleEntry{Cron: "0 25 * * *", Replicas: 1},
+			llamav1alpha1.ScheduleEntry{Cron: "0 9 * * *", Timezone: "Mars/Olympus_Mons", Replicas: 1},
+		)
+
+		evaluation := schedule.Evaluate(entries, now)
+
+		require.NotNil(t, evaluation.Active)
+		assert.Equal(t, "business-hours", evaluation.Active.Name)
+		assert.Len(t, evaluation.Errors, 2)
+	})
+
+	t.Run("later entries win ties", func(t *testing.T) {
+		now := time.Date(2025, time.March, 12, 10, 30, 0, 0, time.UTC)
+		entries := []llamav1alpha1.ScheduleEntry{
+			{Name: "first", Cron: "0 8 * * *", Replicas: 1},
+			{Name: "second", Cron: "0 8 * * *", Replicas: 2},
+		}
+
+		evaluation := schedule.Evaluate(entries, now)
+
+		require.NotNil(t, evaluation.Active)
+		assert.Equal(t, "second", evaluation.Active.Name)
+	})
+}
+
+func TestEntryName(t *testing.T) {
+	assert.Equal(t, "nightly", schedule.EntryName(&llamav1alpha1.ScheduleEntry{Name: "nightly", Cron: "0 0 * * *"}))
+	assert.Equal(t, "0 0 * * *", schedule.EntryName(&llamav1alpha1.ScheduleEntry{Cron: "0 0 * * *"}))
+}
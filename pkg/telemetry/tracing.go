@@ -0,0 +1,198 @@
+// Package telemetry exports the operator's own traces to the OTLP collectors configured on distributions.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// OperatorServiceName is the service.name of the spans emitted by the operator.
+	OperatorServiceName = "llama-stack-k8s-operator"
+	// TracerName is the instrumentation scope of the operator spans.
+	TracerName = "github.com/llamastack/llama-stack-k8s-operator"
+
+	// DefaultSamplingRatio is used when a distribution does not set a sampling ratio.
+	DefaultSamplingRatio = "1"
+	// tracesPath is appended to the collector base URL for OTLP over HTTP.
+	tracesPath = "/v1/traces"
+)
+
+// TracerProviders hands out tracers exporting to the collector of a distribution. Providers
+// are created on first use and shared by all distributions using the same collector settings.
+type TracerProviders struct {
+	mu        sync.Mutex
+	providers map[providerKey]*tracerProvider
+}
+
+// tracerProvider is a provider with the export headers it was created with. A provider whose
+// headers changed, e.g. after a token rotation, is replaced.
+type tracerProvider struct {
+	*sdktrace.TracerProvider
+	headers map[string]string
+}
+
+type providerKey struct {
+	endpoint      string
+	protocol      llamav1alpha1.OTLPProtocol
+	samplingRatio string
+}
+
+// NewTracerProviders creates an empty set of tracer providers.
+func NewTracerProviders() *TracerProviders {
+	return &TracerProviders{providers: make(map[providerKey]*tracerProvider)}
+}
+
+// Tracer returns a tracer exporting to the collector configured in spec with the given export
+// headers, or the global tracer when spec is nil.
+func (p *TracerProviders) Tracer(ctx context.Context, spec *llamav1alpha1.TelemetrySpec, headers map[string]string) (trace.Tracer, error) {
+	if spec == nil {
+		return otel.Tracer(TracerName), nil
+	}
+
+	key := providerKey{endpoint: spec.Endpoint, protocol: spec.Protocol, samplingRatio: spec.SamplingRatio}
+	if key.protocol == "" {
+		key.protocol = llamav1alpha1.OTLPProtocolHTTPProtobuf
+	}
+	if key.samplingRatio == "" {
+		key.samplingRatio = DefaultSamplingRatio
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	previous, ok := p.providers[key]
+	if ok && maps.Equal(previous.headers, headers) {
+		return previous.Tracer(TracerName), nil
+	}
+
+	provider, err := newTracerProvider(ctx, key, headers)
+	if err != nil {
+		return nil, err
+	}
+	p.providers[key] = &tracerProvider{TracerProvider: provider, headers: maps.Clone(headers)}
+	if ok {
+		// Flush the spans recorded with the previous headers without holding up the reconciliation.
+		go func() { _ = previous.Shutdown(context.WithoutCancel(ctx)) }()
+	}
+	return provider.Tracer(TracerName), nil
+}
+
+// Start blocks until ctx is done and then flushes and stops all providers, so the set can be
+// added to a controller-runtime manager.
+func (p *TracerProviders) Start(ctx context.Context) error {
+	<-ctx.Done()
+	// The manager context is already canceled, give the exporters their own.
+	return p.Shutdown(context.WithoutCancel(ctx))
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; spans are flushed on every replica.
+func (p *TracerProviders) NeedLeaderElection() bool {
+	return false
+}
+
+// Shutdown flushes and stops all providers.
+func (p *TracerProviders) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for key, provider := range p.providers {
+		if err := provider.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down tracer provider for %s: %w", key.endpoint, err))
+		}
+		delete(p.providers, key)
+	}
+	return errors.Join(errs...)
+}
+
+// ParseSamplingRatio parses a sampling ratio between 0 and 1.
+func ParseSamplingRatio(value string) (float64, error) {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse sampling ratio %q: %w", value, err)
+	}
+	if ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("failed to parse sampling ratio %q: must be between 0 and 1", value)
+	}
+	return ratio, nil
+}
+
+// ParseHeaders parses export headers in the format of OTEL_EXPORTER_OTLP_HEADERS: comma separated
+// key=value pairs whose values may be URL encoded.
+func ParseHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("failed to parse export headers: %q is not a key=value pair", strings.TrimSpace(pair))
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse export header %q: %w", key, err)
+		}
+		headers[key] = decoded
+	}
+	return headers, nil
+}
+
+func newTracerProvider(ctx context.Context, key providerKey, headers map[string]string) (*sdktrace.TracerProvider, error) {
+	ratio, err := ParseSamplingRatio(key.samplingRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := newExporter(ctx, key, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", OperatorServiceName))),
+	), nil
+}
+
+func newExporter(ctx context.Context, key providerKey, headers map[string]string) (*otlptrace.Exporter, error) {
+	var (
+		exporter *otlptrace.Exporter
+		err      error
+	)
+	switch key.protocol {
+	case llamav1alpha1.OTLPProtocolGRPC:
+		exporter, err = otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(key.endpoint), otlptracegrpc.WithHeaders(headers))
+	case llamav1alpha1.OTLPProtocolHTTPProtobuf:
+		var endpoint string
+		endpoint, err = url.JoinPath(key.endpoint, tracesPath)
+		if err == nil {
+			exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint), otlptracehttp.WithHeaders(headers))
+		}
+	default:
+		return nil, fmt.Errorf("failed to create OTLP exporter: unsupported protocol %q", key.protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter for %s: %w", key.endpoint, err)
+	}
+	return exporter, nil
+}
@@ -0,0 +1,71 @@
+package telemetry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	llamav1alpha1 "github.com/llamastack/llama-stack-k8s-operator/api/v1alpha1"
+	"github.com/llamastack/llama-stack-k8s-operator/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSamplingRatio(t *testing.T) {
+	ratio, err := telemetry.ParseSamplingRatio("0.5")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, ratio, 0)
+
+	_, err = telemetry.ParseSamplingRatio("1.5")
+	require.Error(t, err)
+	_, err = telemetry.ParseSamplingRatio("half")
+	require.Error(t, err)
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := telemetry.ParseHeaders("authorization=Bearer%20token, x-tenant = a=b,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer token", "x-tenant": "a=b"}, headers)
+
+	_, err = telemetry.ParseHeaders("authorization")
+	require.Error(t, err)
+	_, err = telemetry.ParseHeaders("authorization=%zz")
+	require.Error(t, err)
+}
+
+func TestTracerProviders(t *testing.T) {
+	providers := telemetry.NewTracerProviders()
+	t.Cleanup(func() {
+		// There is no collector listening, so flushing the recorded spans is expected to fail.
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second) //nolint:usetesting // t.Context is canceled before cleanups run
+		defer cancel()
+		_ = providers.Shutdown(ctx)
+	})
+
+	t.Run("falls back to the global tracer without telemetry", func(t *testing.T) {
+		tracer, err := providers.Tracer(t.Context(), nil, nil)
+		require.NoError(t, err)
+		assert.NotNil(t, tracer)
+	})
+
+	t.Run("creates exporters for both protocols", func(t *testing.T) {
+		for _, protocol := range []llamav1alpha1.OTLPProtocol{llamav1alpha1.OTLPProtocolHTTPProtobuf, llamav1alpha1.OTLPProtocolGRPC} {
+			tracer, err := providers.Tracer(t.Context(), &llamav1alpha1.TelemetrySpec{
+				Endpoint: "http://127.0.0.1:4318",
+				Protocol: protocol,
+			}, map[string]string{"authorization": "Bearer token"})
+			require.NoError(t, err, protocol)
+			_, span := tracer.Start(t.Context(), "test")
+			assert.True(t, span.SpanContext().IsSampled(), "spans are sampled by default")
+			span.End()
+		}
+	})
+
+	t.Run("rejects invalid sampling ratios", func(t *testing.T) {
+		_, err := providers.Tracer(t.Context(), &llamav1alpha1.TelemetrySpec{
+			Endpoint:      "http://127.0.0.1:4318",
+			SamplingRatio: "2",
+		}, nil)
+		require.Error(t, err)
+	})
+}